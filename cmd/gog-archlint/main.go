@@ -0,0 +1,13 @@
+// Command gog-archlint runs the gog architecture analyzers as a go vet tool:
+//
+//	go vet -vettool=$(which gog-archlint) ./...
+package main
+
+import (
+	"github.com/nayla-finance/gog/internal/lint/arch"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(arch.Analyzers()...)
+}
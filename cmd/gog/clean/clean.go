@@ -0,0 +1,115 @@
+package clean_cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/examples"
+	"github.com/nayla-finance/gog/internal/report"
+	"github.com/nayla-finance/gog/internal/swagger"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove template content from a project",
+	}
+
+	cmd.AddCommand(newExamplesCmd())
+
+	return cmd
+}
+
+func newExamplesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "examples",
+		Short: "Remove the example domains of the template",
+		Long: `Removes the ` + strings.Join(examples.Domains, ", ") + ` example domains, their interfaces,
+models and registry files, then removes every reference to them from the remaining code:
+registry fields, provider embeds, route registrations, consumers and the imports left unused.
+Comments naming a removed domain go with the code they describe.
+
+The swagger docs are regenerated and go build ./... is run to check the project still compiles.
+The migrations are left as they are, write a new migration to drop the example tables.`,
+		Example:      "gog clean examples\ngog clean examples --dry-run\ngog clean examples -d ./my-service",
+		SilenceUsage: true,
+		RunE:         runExamples,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("dry-run", false, "Print the files that would be removed or edited without changing them")
+	report.AddFlags(cmd)
+
+	return cmd
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get dry-run flag: %w", err)
+	}
+
+	reporter, err := report.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := examples.Remove(dir, examples.Options{DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Removed) == 0 {
+		reporter.Completed("No example domains to remove")
+		return nil
+	}
+
+	if dryRun {
+		reporter.Info("🔍", "Dry run, nothing is changed")
+	}
+
+	for _, rel := range result.Removed {
+		reporter.FileRemoved(rel)
+	}
+	for _, rel := range result.Edited {
+		reporter.FileUpdated(rel)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, swagger.MainFile)); err == nil {
+		const step = "Regenerating swagger docs"
+		reporter.StepStarted("📚", step)
+		if err := swagger.Generate(dir, filepath.Join(dir, swagger.DocsDir)); err != nil {
+			reporter.Warn(fmt.Sprintf("Failed to regenerate swagger docs, run just swagger: %v", err))
+		} else {
+			reporter.StepSucceeded(step)
+		}
+	}
+
+	const step = "Building project"
+	reporter.StepStarted("🔨", step)
+	build := exec.Command("go", "build", "./...")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		reporter.StepFailed(step, out, err)
+		return fmt.Errorf("❌ The project does not build without the examples, fix the remaining references:\n%s", out)
+	}
+	reporter.StepSucceeded(step)
+
+	reporter.Warn("The migrations still create the example tables, add a migration dropping them if they were applied")
+	reporter.Completed("Example domains removed")
+
+	return nil
+}
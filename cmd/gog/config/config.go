@@ -0,0 +1,318 @@
+package config_cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nayla-finance/gog/internal/configspec"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with the config of a project",
+		Long: `The config commands type check the ` + configspec.DefaultType + ` struct of ` + configspec.DefaultPackage + ` and read
+its mapstructure keys, validate tags, doc comments and the viper defaults set by config.Load.
+The dependencies of the project must be downloaded.`,
+	}
+
+	cmd.AddCommand(newSchemaCmd(), newValidateCmd(), newEnvCmd())
+
+	return cmd
+}
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of config.yaml",
+		Long: `Prints the JSON Schema of the config file for the completion and validation of config.yaml
+in editors. With the YAML language server, add this line at the top of config.yaml:
+
+  # yaml-language-server: $schema=./config.schema.json`,
+		Example:      "gog config schema -o config.schema.json",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get output flag: %w", err)
+			}
+
+			spec, err := loadSpec(cmd, dir)
+			if err != nil {
+				return err
+			}
+
+			module := ""
+			if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+				module = modfile.ModulePath(data)
+			}
+
+			schema, err := spec.Schema(filepath.Base(module) + " config")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to generate the schema: %w", err)
+			}
+			schema = append(schema, '\n')
+
+			if output == "" {
+				_, err := os.Stdout.Write(schema)
+				return err
+			}
+
+			if err := os.WriteFile(output, schema, 0644); err != nil {
+				return fmt.Errorf("❌ Failed to write %s: %w", output, err)
+			}
+			fmt.Printf("✅ Wrote '%s'\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().StringP("output", "o", "", "Write the schema to a file instead of stdout")
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a config file and the environment against the config struct",
+		Long: `Checks a config file, config.yaml of the project directory by default, against the config
+struct: unknown keys with the closest known key, values viper can not decode into their field,
+durations without a unit, required keys set neither by the file, the environment nor a
+default, and a database.migrations_dir or nats.creds_path that can not be read, relative to the
+project directory. config.Load leaves the paths to the components reading them, a missing
+creds file is a warning since secrets/ is mounted at runtime.
+
+The environment variables of the process are checked too, like config.Load they override the
+keys, e.g. DATABASE__PASSWORD for database.password, and set the required keys missing from the
+file. --env-file adds the variables of a dotenv file, --env=false only checks the files, e.g.
+config.yaml.example in the CI.
+
+The command exits with a non zero status when an error is found.`,
+		Example:      "gog config validate\ngog config validate config.yaml.example\ngog config validate --env-file .env --json",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			useEnv, err := cmd.Flags().GetBool("env")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get env flag: %w", err)
+			}
+
+			envFiles, err := cmd.Flags().GetStringArray("env-file")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get env-file flag: %w", err)
+			}
+
+			asJSON, err := cmd.Flags().GetBool("json")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get json flag: %w", err)
+			}
+
+			file := filepath.Join(dir, "config.yaml")
+			if len(args) == 1 {
+				file = args[0]
+			}
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("❌ Failed to read config file: %w", err)
+			}
+
+			var env []string
+			for _, f := range envFiles {
+				vars, err := configspec.ReadEnvFile(f)
+				if err != nil {
+					return err
+				}
+				env = append(env, vars...)
+			}
+			if useEnv {
+				env = append(env, os.Environ()...)
+			}
+
+			spec, err := loadSpec(cmd, dir)
+			if err != nil {
+				return err
+			}
+
+			problems, err := spec.Validate(file, data, configspec.ValidateOptions{Env: env, Dir: dir})
+			if err != nil {
+				return err
+			}
+
+			errs := 0
+			for _, p := range problems {
+				if p.Level == configspec.LevelError {
+					errs++
+				}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if problems == nil {
+					problems = []configspec.Problem{}
+				}
+				if err := enc.Encode(problems); err != nil {
+					return err
+				}
+
+				// keep stdout valid JSON, the error is only the exit status
+				if errs > 0 {
+					os.Exit(1)
+				}
+
+				return nil
+			}
+
+			for _, p := range problems {
+				emoji := "❌"
+				if p.Level == configspec.LevelWarning {
+					emoji = "⚠️ "
+				}
+				fmt.Printf("%s %s\n", emoji, p)
+			}
+
+			if errs > 0 {
+				return fmt.Errorf("❌ %d error(s) in %s", errs, file)
+			}
+			fmt.Printf("✅ %s is valid\n", file)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("env", true, "Check the environment variables of the process, config.Load reads them")
+	cmd.Flags().StringArray("env-file", nil, "Also check the variables of a dotenv file, can be repeated")
+	cmd.Flags().Bool("json", false, "Print the problems as JSON")
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Print the environment variables overriding the config keys",
+		Long: `Prints the environment variable of every key of the config struct with its type, viper
+default, whether it is required or a secret and its doc comment. config.Load replaces the dots
+of the keys with __, e.g. database.password is read from DATABASE__PASSWORD.
+
+Formats:
+  markdown    a table for the README
+  dotenv      a .env example, the secrets are left empty
+  kubernetes  a ConfigMap and a Secret with the secrets, for envFrom
+  json        the variables as JSON`,
+		Example:      "gog config env\ngog config env -f dotenv -o .env.example\ngog config env -f kubernetes --name payments",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get format flag: %w", err)
+			}
+
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get name flag: %w", err)
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get output flag: %w", err)
+			}
+
+			spec, err := loadSpec(cmd, dir)
+			if err != nil {
+				return err
+			}
+
+			if name == "" {
+				if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+					name = filepath.Base(modfile.ModulePath(data))
+				}
+			}
+
+			var b bytes.Buffer
+			vars := spec.EnvVars()
+			switch format {
+			case "markdown":
+				err = configspec.WriteMarkdown(&b, vars)
+			case "dotenv":
+				err = configspec.WriteDotenv(&b, vars)
+			case "kubernetes":
+				err = configspec.WriteKubernetes(&b, name, vars)
+			case "json":
+				enc := json.NewEncoder(&b)
+				enc.SetIndent("", "  ")
+				err = enc.Encode(vars)
+			default:
+				return fmt.Errorf("❌ Unknown format '%s', use markdown, dotenv, kubernetes or json", format)
+			}
+			if err != nil {
+				return fmt.Errorf("❌ Failed to write the variables: %w", err)
+			}
+
+			if output == "" {
+				_, err := os.Stdout.Write(b.Bytes())
+				return err
+			}
+
+			if err := os.WriteFile(output, b.Bytes(), 0644); err != nil {
+				return fmt.Errorf("❌ Failed to write %s: %w", output, err)
+			}
+			fmt.Printf("✅ Wrote '%s'\n", output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().StringP("format", "f", "markdown", "The output format: markdown, dotenv, kubernetes or json")
+	cmd.Flags().String("name", "", "The name of the Kubernetes ConfigMap and Secret, the module name by default")
+	cmd.Flags().StringP("output", "o", "", "Write the variables to a file instead of stdout")
+	addSpecFlags(cmd)
+
+	return cmd
+}
+
+func addSpecFlags(cmd *cobra.Command) {
+	cmd.Flags().String("package", configspec.DefaultPackage, "The package of the config struct, relative to the module")
+	cmd.Flags().String("type", configspec.DefaultType, "The name of the config struct")
+}
+
+func loadSpec(cmd *cobra.Command, dir string) (*configspec.Spec, error) {
+	pkg, err := cmd.Flags().GetString("package")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to get package flag: %w", err)
+	}
+
+	typ, err := cmd.Flags().GetString("type")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to get type flag: %w", err)
+	}
+
+	return configspec.Load(dir, configspec.Options{Package: pkg, Type: typ})
+}
@@ -0,0 +1,108 @@
+package doctor_cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var statusEmoji = map[doctor.Status]string{
+	doctor.StatusPass: "✅",
+	doctor.StatusWarn: "⚠️ ",
+	doctor.StatusFail: "❌",
+}
+
+func NewCmd() *cobra.Command {
+	var checks []string
+	for _, c := range doctor.Checks {
+		checks = append(checks, fmt.Sprintf("  %-20s %s", c.Name, c.Description))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check a project for drift from the template conventions",
+		Long: `Runs offline checks on a project directory and prints pass, warn or fail for each with
+a hint on how to fix it:
+
+` + strings.Join(checks, "\n") + `
+
+--fix repairs the safe issues: it copies the missing config keys from config.yaml.example,
+restores the RegistryProvider assertion, regenerates docs/ and installs the pre-commit hook.
+
+The command exits with a non zero status when a check fails.`,
+		Example:      "gog doctor\ngog doctor --fix\ngog doctor -d ./my-service --json",
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("fix", false, "Repair the issues that are safe to repair automatically")
+	cmd.Flags().Bool("json", false, "Print the report as JSON")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	fix, err := cmd.Flags().GetBool("fix")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get fix flag: %w", err)
+	}
+
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get json flag: %w", err)
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("❌ Failed to read project directory: %w", err)
+	}
+
+	report := doctor.Run(dir, fix)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+
+		// keep stdout valid JSON, the error is only the exit status
+		if report.Summary.Fail > 0 {
+			os.Exit(1)
+		}
+
+		return nil
+	}
+
+	for _, r := range report.Results {
+		fixed := ""
+		if r.Fixed {
+			fixed = " (fixed)"
+		}
+
+		fmt.Printf("%s %-20s %s%s\n", statusEmoji[r.Status], r.Check, r.Message, fixed)
+		for _, d := range r.Details {
+			fmt.Printf("      - %s\n", d)
+		}
+		if r.Hint != "" && r.Status != doctor.StatusPass {
+			fmt.Printf("      💡 %s\n", r.Hint)
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d warnings, %d failed\n", report.Summary.Pass, report.Summary.Warn, report.Summary.Fail)
+
+	if report.Summary.Fail > 0 {
+		return fmt.Errorf("❌ %d check(s) failed", report.Summary.Fail)
+	}
+
+	return nil
+}
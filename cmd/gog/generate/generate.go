@@ -0,0 +1,311 @@
+package generate_cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/generate"
+	"github.com/nayla-finance/gog/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate code in a project",
+	}
+
+	cmd.AddCommand(newHandlersCmd(), newMigrationCmd(), newDTOCmd())
+
+	return cmd
+}
+
+func newHandlersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "handlers",
+		Short: "Generate domains from an OpenAPI 3 spec",
+		Long: `Generates a domain package per tag of an OpenAPI 3 document (YAML or JSON):
+
+  internal/domains/model/<spec>_gen.go          DTOs of the component, body and parameter schemas with validate tags
+  internal/domains/interfaces/<domain>_gen.go   the <Domain>Service interface
+  internal/domains/<domain>/routes_gen.go       RegisterRoutes and the request parsing and validation
+  internal/domains/<domain>/handler.go          handler stubs with their swag annotations
+  internal/domains/<domain>/service.go          service stubs
+
+The *_gen.go files are rewritten on every run. handler.go and service.go are yours: missing
+stubs are appended and the signatures and swag annotations of the existing ones are updated
+when the spec changes, their bodies are never touched.
+
+Paths are registered on the /api group, so the spec paths must not include the /api prefix.`,
+		Example:      "gog generate handlers --from-spec api.yaml\ngog generate handlers --from-spec api.json -d ./my-service",
+		SilenceUsage: true,
+		RunE:         runHandlers,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().String("from-spec", "", "The OpenAPI 3 document to generate from")
+	cmd.MarkFlagRequired("from-spec")
+	report.AddFlags(cmd)
+
+	return cmd
+}
+
+func runHandlers(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	spec, err := cmd.Flags().GetString("from-spec")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get from-spec flag: %w", err)
+	}
+
+	reporter, err := report.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := generate.Handlers(generate.HandlersOptions{Dir: dir, Spec: spec})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range result.Created {
+		reporter.FileCreated(f)
+	}
+
+	for _, f := range result.Updated {
+		reporter.FileUpdated(f)
+	}
+
+	for _, w := range result.Warnings {
+		reporter.Warn(w)
+	}
+
+	if len(result.Created)+len(result.Updated) == 0 {
+		reporter.Completed("Everything is up to date")
+		return nil
+	}
+
+	for _, d := range result.NewDomains {
+		reporter.Info("📝", fmt.Sprintf(`Wire the %[1]s domain in the registry:
+
+  // internal/registry/registry.go, Registry fields
+  %[2]sService Lazy[interfaces.%[3]sService]
+
+  // internal/registry/registry_api.go, RegisterApiRoutes
+  %[1]s.NewHandler(r).RegisterRoutes(api)
+
+  // internal/registry/registry_provider.go, RegistryProvider
+  interfaces.%[3]sServiceProvider
+
+  // internal/registry/registry_%[1]s.go
+  func (r *Registry) %[3]sService() interfaces.%[3]sService {
+  	return r.%[2]sService.Get(func() interfaces.%[3]sService {
+  		return %[1]s.NewService(r)
+  	})
+  }
+`, d.Package, strings.ToLower(d.Name[:1])+d.Name[1:], d.Name))
+	}
+
+	reporter.Completed("Handlers generated, update the docs", "just swagger")
+
+	return nil
+}
+
+func newMigrationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migration",
+		Short: "Generate a goose migration from the changes of the models",
+		Long: `Compares the models, the structs with a TableName method, with the schema left by the
+CREATE, ALTER and DROP TABLE statements of the SQL migrations, and writes a goose migration with
+the Up and Down statements:
+
+  - CREATE TABLE for the models without a table
+  - ADD COLUMN for the fields without a column, the columns are named after the db tags
+  - DROP COLUMN for the columns without a field
+  - ALTER COLUMN TYPE when the column does not scan into the field, e.g. TEXT into an int
+  - SET or DROP NOT NULL when the field is not or is a pointer
+
+Dropping a column and changing its type lose data, they are refused unless --allow-destructive
+is set. The type changes Postgres can not cast, e.g. a SERIAL id to a UUID, are always refused
+and are written by hand. The tables without a model are left alone. Check the migration before applying it, the
+indexes, constraints and foreign keys are never generated.`,
+		Example:      "gog generate migration --from-models\ngog generate migration --from-models --name add_user_avatar --dry-run",
+		SilenceUsage: true,
+		RunE:         runMigration,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("from-models", false, "Generate the migration from the changes of the models")
+	cmd.Flags().String("models", generate.DefaultModelsDir, "The directory of the models, relative to the project directory")
+	cmd.Flags().String("name", "update_models", "The name of the migration")
+	cmd.Flags().Bool("allow-destructive", false, "Allow dropping columns and changing their type")
+	cmd.Flags().Bool("dry-run", false, "Print the migration instead of writing it")
+	cmd.MarkFlagRequired("from-models")
+	report.AddFlags(cmd)
+
+	return cmd
+}
+
+func runMigration(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	fromModels, err := cmd.Flags().GetBool("from-models")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get from-models flag: %w", err)
+	}
+
+	models, err := cmd.Flags().GetString("models")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get models flag: %w", err)
+	}
+
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get name flag: %w", err)
+	}
+
+	allowDestructive, err := cmd.Flags().GetBool("allow-destructive")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get allow-destructive flag: %w", err)
+	}
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get dry-run flag: %w", err)
+	}
+
+	if !fromModels {
+		return fmt.Errorf("❌ Only --from-models migrations can be generated")
+	}
+
+	reporter, err := report.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := generate.Migration(generate.MigrationOptions{
+		Dir:              dir,
+		Models:           models,
+		Name:             name,
+		AllowDestructive: allowDestructive,
+		DryRun:           dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, w := range result.Warnings {
+		reporter.Warn(w)
+	}
+
+	if len(result.Changes) == 0 {
+		reporter.Completed("The migrations match the models")
+		return nil
+	}
+
+	for _, c := range result.Changes {
+		reporter.Info("🗃️ ", c)
+	}
+
+	if dryRun {
+		fmt.Print(result.SQL)
+		return nil
+	}
+
+	reporter.FileCreated(result.File)
+	reporter.Completed("Migration generated, check it before applying it", "just migrate")
+
+	return nil
+}
+
+func newDTOCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dto <model>",
+		Short: "Generate the request and response DTOs of a model",
+		Long: `Generates the DTOs of a model, e.g. model.User, in <model>_dto_gen.go next to it:
+
+  --create    CreateUserDTO, its Validate method and ToUser returning a new User
+  --update    UpdateUserDTO with pointer fields, its Validate method and ApplyUpdate(u *User)
+  --response  UserResponse and NewUserResponse(u *User)
+
+All three are generated when none is set. The validate tags of the model fields are copied to
+the DTOs, the create DTO requires the fields that are not pointers. The gog tag of a model field
+picks the DTOs it is in:
+
+  gog:"-"            in none of them
+  gog:"readonly"     only in the response, the default of ID, CreatedAt, UpdatedAt and DeletedAt
+  gog:"create-only"  not in the update DTO
+  gog:"writeonly"    not in the response, e.g. a password
+  gog:"optional"     not required in the create DTO
+
+The fields holding other models are relations, they are skipped. The file is rewritten on every
+run, rerun the command after changing the model.`,
+		Example:      "gog generate dto model.User\ngog generate dto model.Post --create --response",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runDTO,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("create", false, "Generate the create DTO")
+	cmd.Flags().Bool("update", false, "Generate the update DTO")
+	cmd.Flags().Bool("response", false, "Generate the response")
+	report.AddFlags(cmd)
+
+	return cmd
+}
+
+func runDTO(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	create, err := cmd.Flags().GetBool("create")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get create flag: %w", err)
+	}
+
+	update, err := cmd.Flags().GetBool("update")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get update flag: %w", err)
+	}
+
+	response, err := cmd.Flags().GetBool("response")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get response flag: %w", err)
+	}
+
+	reporter, err := report.FromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := generate.DTO(generate.DTOOptions{Dir: dir, Model: args[0], Create: create, Update: update, Response: response})
+	if err != nil {
+		return err
+	}
+
+	for _, f := range result.Created {
+		reporter.FileCreated(f)
+	}
+
+	for _, f := range result.Updated {
+		reporter.FileUpdated(f)
+	}
+
+	if len(result.Created)+len(result.Updated) == 0 {
+		reporter.Completed("Everything is up to date")
+		return nil
+	}
+
+	reporter.Completed("DTOs generated", "just swagger")
+
+	return nil
+}
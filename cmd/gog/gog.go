@@ -5,7 +5,16 @@ import (
 	"os"
 
 	"github.com/nayla-finance/gog"
+	clean_cmd "github.com/nayla-finance/gog/cmd/gog/clean"
+	config_cmd "github.com/nayla-finance/gog/cmd/gog/config"
+	doctor_cmd "github.com/nayla-finance/gog/cmd/gog/doctor"
+	generate_cmd "github.com/nayla-finance/gog/cmd/gog/generate"
+	graph_cmd "github.com/nayla-finance/gog/cmd/gog/graph"
+	hooks_cmd "github.com/nayla-finance/gog/cmd/gog/hooks"
+	lint_cmd "github.com/nayla-finance/gog/cmd/gog/lint"
 	new_cmd "github.com/nayla-finance/gog/cmd/gog/new"
+	plugin_cmd "github.com/nayla-finance/gog/cmd/gog/plugin"
+	routes_cmd "github.com/nayla-finance/gog/cmd/gog/routes"
 	"github.com/nayla-finance/gog/cmd/gog/swag"
 	"github.com/spf13/cobra"
 )
@@ -14,13 +23,29 @@ var rootCmd = &cobra.Command{
 	Use:     "gog [command]",
 	Short:   "gog is a tool for generating Go projects",
 	Version: gog.Version,
+	// main prints the error, cobra would print it a second time
+	SilenceErrors: true,
 	CompletionOptions: cobra.CompletionOptions{
 		DisableDefaultCmd: true,
 	},
 }
 
 func main() {
-	rootCmd.AddCommand(new_cmd.NewCmd(), swag.NewSwag())
+	rootCmd.AddCommand(new_cmd.NewCmd(), swag.NewSwag(), graph_cmd.NewCmd(), lint_cmd.NewCmd(), routes_cmd.NewCmd(), generate_cmd.NewCmd(), doctor_cmd.NewCmd(), clean_cmd.NewCmd(), hooks_cmd.NewCmd(), config_cmd.NewCmd(), plugin_cmd.NewCmd())
+
+	rootCmd.AddGroup(&cobra.Group{ID: "builtin", Title: "Available Commands:"})
+	for _, c := range rootCmd.Commands() {
+		c.GroupID = "builtin"
+	}
+	rootCmd.SetHelpCommandGroupID("builtin")
+
+	// plugins are listed apart from the built-in commands in gog help, the group is only shown
+	// when a plugin is installed
+	if plugins := plugin_cmd.Commands(rootCmd); len(plugins) > 0 {
+		rootCmd.AddGroup(&cobra.Group{ID: plugin_cmd.GroupID, Title: "Plugin Commands:"})
+		rootCmd.AddCommand(plugins...)
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -0,0 +1,69 @@
+package graph_cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nayla-finance/gog/internal/graph"
+	"github.com/nayla-finance/gog/internal/source"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph [project directory]",
+		Short: "Print the provider dependency graph of a project",
+		Long: `Statically analyzes every *Dependencies interface and constructor under internal/
+and prints how they are wired through the registry.
+
+Dependency cycles, providers embedded in RegistryProvider that nothing consumes and
+dependency interfaces the registry does not satisfy are reported on stderr.`,
+		Example: "gog graph\ngog graph ./my-service -f mermaid\ngog graph -f json --strict",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runGraph,
+	}
+
+	cmd.Flags().StringP("format", "f", graph.FormatDOT, "Output format (dot, mermaid, json)")
+	cmd.Flags().Bool("strict", false, "Exit with an error if any issue is found")
+
+	return cmd
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get format flag: %w", err)
+	}
+
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get strict flag: %w", err)
+	}
+
+	m, err := source.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	g, err := graph.Build(m)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Render(os.Stdout, format); err != nil {
+		return err
+	}
+
+	g.WriteIssues(os.Stderr)
+
+	if strict && g.HasIssues() {
+		return fmt.Errorf("❌ Provider graph has issues")
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package hooks_cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/hooks"
+	"github.com/nayla-finance/gog/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the git hooks of a project",
+		Long: `The git hooks of a project are listed in ` + hooks.File + `, versioned with the project.
+Each hook is a list of steps, a shell command (run) or a built-in check (check):
+
+  gofmt       the Go files are formatted, with fix: true they are formatted and restaged
+  vet         go vet ./...
+  swag        docs/ matches the swag annotations, like gog swag check
+  migrations  the goose migration file names and annotations
+  secrets     config.yaml, secrets/, private keys and tokens are not committed
+
+gog hooks install writes a script per hook calling gog hooks run, rerun it after editing
+the steps. gog hooks run --all checks every tracked file instead of the staged ones, for CI.`,
+		Example: "gog hooks install\ngog hooks run pre-commit --all\ngog hooks uninstall",
+	}
+
+	cmd.AddCommand(newInstallCmd(), newRunCmd(), newUninstallCmd())
+
+	return cmd
+}
+
+func newInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "install",
+		Short:        "Install the hooks of " + hooks.File + ", it is created with the default steps if missing",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			force, err := cmd.Flags().GetBool("force")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get force flag: %w", err)
+			}
+
+			written, err := hooks.Install(dir, hooks.ProjectDefaults, force)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range written {
+				fmt.Printf("  📄 Wrote '%s'\n", f)
+			}
+			fmt.Println("✅ Hooks installed")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("force", false, "Replace the hooks not written by gog, they are kept with a .bak suffix")
+
+	return cmd
+}
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [hook] [-- hook arguments]",
+		Short: "Run the steps of a hook, pre-commit by default",
+		Long: `Runs the steps of a hook of ` + hooks.File + `, every step runs even when one fails.
+
+The checks look at the staged files, --all checks every tracked file and never modifies them,
+use it in CI. Available hooks: ` + strings.Join(hooks.Supported, ", ") + `.`,
+		Example:      "gog hooks run\ngog hooks run pre-push --all\ngog hooks run --all --output json",
+		Args:         cobra.ArbitraryArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			all, err := cmd.Flags().GetBool("all")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get all flag: %w", err)
+			}
+
+			reporter, err := report.FromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// the arguments git passes to the hook follow --
+			hook, hookArgs := "pre-commit", args
+			if n := cmd.ArgsLenAtDash(); n != 0 && len(args) > 0 {
+				hook, hookArgs = args[0], args[1:]
+			}
+
+			return hooks.Run(dir, hook, hooks.RunOptions{All: all, Args: hookArgs, Reporter: reporter})
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().Bool("all", false, "Check every tracked file instead of the staged ones")
+	report.AddFlags(cmd)
+
+	return cmd
+}
+
+func newUninstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "uninstall",
+		Short:        "Remove the hooks installed by gog and restore the ones they replaced",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("directory")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+			}
+
+			removed, err := hooks.Uninstall(dir)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range removed {
+				fmt.Printf("  🗑️  Removed '%s'\n", f)
+			}
+			fmt.Printf("✅ Hooks uninstalled, %s is kept\n", hooks.File)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+
+	return cmd
+}
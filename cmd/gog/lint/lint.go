@@ -0,0 +1,149 @@
+package lint_cmd
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nayla-finance/gog/internal/lint/arch"
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lint a project against the template conventions",
+	}
+
+	cmd.AddCommand(newArchCmd())
+
+	return cmd
+}
+
+func newArchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "arch [packages]",
+		Short: "Check the handler -> service -> repository layering",
+		Long: `Checks the layering conventions of the template:
+
+  handlerdeps    handlers only depend on interfaces.*ServiceProvider
+  repoexport     repository methods are unexported
+  servicefiber   services and repositories never import fiber
+  crossrepo      domains never use another domain's repository
+  handlererrors  handlers use errors.ErrorProvider instead of fmt.Errorf/errors.New
+  usercontext    handlers pass c.UserContext() to services instead of c.Context()
+
+Rules can be disabled or excepted for some files in .gog/lint.yaml:
+
+  arch:
+    disabled: [handlererrors]
+    exceptions:
+      handlerdeps:
+        - internal/domains/health/**
+
+The same analyzers can run with go vet:
+
+  go install github.com/nayla-finance/gog/cmd/gog-archlint@latest
+  go vet -vettool=$(which gog-archlint) ./...`,
+		Example:      "gog lint arch\ngog lint arch ./internal/domains/...\ngog lint arch -d ./my-service",
+		SilenceUsage: true,
+		RunE:         runArch,
+	}
+
+	cmd.Flags().StringP("directory", "d", ".", "The project directory")
+	cmd.Flags().StringP("config", "c", "", "Lint configuration file (default <directory>/"+arch.ConfigFile+")")
+
+	return cmd
+}
+
+func runArch(cmd *cobra.Command, args []string) error {
+	dir, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get directory flag: %w", err)
+	}
+
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get config flag: %w", err)
+	}
+
+	if configFile != "" {
+		arch.SetConfigFile(configFile)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	patterns := args
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Dir: dir,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo,
+	}, patterns...)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load packages: %w", err)
+	}
+
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  %s has errors (%s), results are based on its syntax only\n", p.PkgPath, p.Errors[0])
+		}
+	}
+
+	graph, err := checker.Analyze(arch.Analyzers(), pkgs, nil)
+	if err != nil {
+		return err
+	}
+
+	type finding struct {
+		pos token.Position
+		msg string
+	}
+
+	var findings []finding
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return fmt.Errorf("❌ %s: %w", act, act.Err)
+		}
+
+		for _, d := range act.Diagnostics {
+			pos := act.Package.Fset.Position(d.Pos)
+			if rel, err := filepath.Rel(absDir, pos.Filename); err == nil {
+				pos.Filename = rel
+			}
+
+			findings = append(findings, finding{pos: pos, msg: d.Message})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].pos, findings[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+
+	for _, f := range findings {
+		fmt.Printf("%s: %s\n", f.pos, f.msg)
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("❌ Found %d architecture issue(s)", len(findings))
+	}
+
+	fmt.Println("✅ No architecture issues found")
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/job"
+	"github.com/PROJECT_NAME/internal/registry"
+	"github.com/getsentry/sentry-go"
+	"github.com/spf13/cobra"
+)
+
+func NewRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the job once and exit",
+		RunE:  Run,
+	}
+
+	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
+	cmd.Flags().Duration("timeout", time.Hour, "stop the job after this duration")
+
+	return cmd
+}
+
+func Run(cmd *cobra.Command, args []string) error {
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get config file: %v", err)
+	}
+
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get profile: %v", err)
+	}
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get timeout: %v", err)
+	}
+
+	cfg, err := config.Load(configFile, config.WithProfile(profile))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %v", err)
+	}
+
+	r := registry.NewRegistry(cfg)
+
+	initCtx, initCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer initCancel()
+
+	if err := r.InitializeHeadless(initCtx); err != nil {
+		return err
+	}
+	defer r.Cleanup()
+
+	// A SIGTERM (e.g. the pod being evicted) cancels the job instead of killing it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	r.Logger().Infow(ctx, "🚀 Starting job")
+
+	if err := job.Run(ctx, r); err != nil {
+		sentry.CaptureException(err)
+		r.Logger().Errorw(ctx, "❌ Job failed", "error", err, "duration", time.Since(start).String())
+		return err
+	}
+
+	r.Logger().Infow(ctx, "✅ Job completed", "duration", time.Since(start).String())
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package job
+
+import (
+	"context"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/nayla-finance/go-nayla/logger"
+	"github.com/nayla-finance/go-nayla/nats"
+)
+
+type dependencies interface {
+	logger.Provider
+	config.ConfigProvider
+	db.DBProvider
+	nats.ServiceProvider
+}
+
+// Run does the work of the job, it is called once by the run command. Return an error to
+// exit with a non zero status so the scheduler (e.g. a Kubernetes CronJob) retries it.
+func Run(ctx context.Context, d dependencies) error {
+	d.Logger().Infow(ctx, "👷 Doing the job")
+
+	// do the work here, stop early when ctx is done
+
+	return nil
+}
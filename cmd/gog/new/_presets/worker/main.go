@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	config_cmd "github.com/PROJECT_NAME/cmd/config"
+	"github.com/PROJECT_NAME/cmd/migrate"
+	"github.com/PROJECT_NAME/cmd/work"
+	"github.com/getsentry/sentry-go"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	dns := os.Getenv("SENTRY__DSN")
+
+	if dns == "" {
+		fmt.Fprintln(os.Stderr, "⚠️ SENTRY__DSN is not set in env")
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn: dns,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "⚠️ Failed to initialize sentry: ", err)
+	}
+	defer sentry.Flush(2 * time.Second)
+
+	cmd := &cobra.Command{
+		Use:   "PROJECT_NAME",
+		Short: "PROJECT_NAME CLI",
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+	}
+
+	cmd.AddCommand(work.NewWorkCmd(), migrate.NewMigrateCmd(), config_cmd.NewConfigCmd())
+	if err := cmd.Execute(); err != nil {
+		sentry.CaptureException(err)
+		panic(err)
+	}
+}
@@ -0,0 +1,136 @@
+package config_cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigEncrypt() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [value]",
+		Short: "Encrypt a value of the configuration",
+		Long: `Prints the value encrypted with the config key, e.g. enc:v1:..., to replace the plaintext in the
+config file. The value is read from stdin when it is not passed, to keep it out of the shell
+history. The key is generated when neither $` + config.KeyEnv + ` nor the key file exist.`,
+		Example: "PROJECT_NAME config encrypt my-password\necho -n my-password | PROJECT_NAME config encrypt",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runConfigEncrypt,
+	}
+
+	cmd.Flags().StringP("key-file", "k", config.DefaultKeyFile, "file of the config key, $"+config.KeyEnv+" takes precedence")
+
+	return cmd
+}
+
+func newConfigDecrypt() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "decrypt [value]",
+		Short:   "Decrypt an enc: value of the configuration",
+		Long:    "Prints the plaintext of a value encrypted by config encrypt, it is read from stdin when it is not passed.",
+		Example: "PROJECT_NAME config decrypt enc:v1:...",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runConfigDecrypt,
+	}
+
+	cmd.Flags().StringP("key-file", "k", config.DefaultKeyFile, "file of the config key, $"+config.KeyEnv+" takes precedence")
+
+	return cmd
+}
+
+func runConfigEncrypt(cmd *cobra.Command, args []string) error {
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get key file: %v", err)
+	}
+
+	value, err := readValue(args)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureKey(keyFile); err != nil {
+		return err
+	}
+
+	key, err := config.LoadKey(keyFile)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := config.Encrypt(key, value)
+	if err != nil {
+		return err
+	}
+	fmt.Println(encrypted)
+
+	return nil
+}
+
+func runConfigDecrypt(cmd *cobra.Command, args []string) error {
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get key file: %v", err)
+	}
+
+	value, err := readValue(args)
+	if err != nil {
+		return err
+	}
+
+	key, err := config.LoadKey(keyFile)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := config.Decrypt(key, value)
+	if err != nil {
+		return err
+	}
+	fmt.Println(plaintext)
+
+	return nil
+}
+
+// readValue returns the argument, or the first line of stdin.
+func readValue(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("❌ Failed to read the value from stdin: %v", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ensureKey generates the key file when there is no key yet.
+func ensureKey(keyFile string) error {
+	if _, ok := os.LookupEnv(config.KeyEnv); ok {
+		return nil
+	}
+
+	if _, err := os.Stat(keyFile); !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	key, err := config.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	if err := config.WriteKey(keyFile, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "🔑 Generated the config key %s, keep it out of git and pass it to the deployments as $%s\n", keyFile, config.KeyEnv)
+
+	return nil
+}
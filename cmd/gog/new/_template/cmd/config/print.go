@@ -0,0 +1,53 @@
+package config_cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigPrint() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "print",
+		Short:                 "Print the effective configuration with the secrets redacted",
+		DisableFlagsInUseLine: true,
+		RunE:                  runConfigPrint,
+	}
+
+	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
+	cmd.Flags().StringP("key-file", "k", config.DefaultKeyFile, "file of the config key, $"+config.KeyEnv+" takes precedence")
+
+	return cmd
+}
+
+func runConfigPrint(cmd *cobra.Command, args []string) error {
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get config file: %v", err)
+	}
+
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get profile: %v", err)
+	}
+
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get key file: %v", err)
+	}
+
+	if err := config.Print(os.Stdout, configFile, config.WithProfile(profile), config.WithKeyFile(keyFile)); err != nil {
+		// the config is printed before its problems
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			return validationErr
+		}
+		return fmt.Errorf("❌ Failed to load configuration: %v", err)
+	}
+
+	return nil
+}
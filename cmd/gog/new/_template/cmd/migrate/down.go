@@ -21,6 +21,7 @@ migrate down 0 - Rollback all migrations`,
 	}
 
 	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
 
 	return cmd
 }
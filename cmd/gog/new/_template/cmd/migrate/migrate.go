@@ -33,7 +33,12 @@ func setupMigration(cmd *cobra.Command) (*config.Config, *sql.DB, error) {
 		return nil, nil, fmt.Errorf("❌ Failed to get config file: %v", err)
 	}
 
-	cfg, err := config.Load(configFile)
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to get profile: %v", err)
+	}
+
+	cfg, err := config.Load(configFile, config.WithProfile(profile))
 	if err != nil {
 		return nil, nil, fmt.Errorf("❌ Failed to load configuration: %v", err)
 	}
@@ -10,6 +10,7 @@ import (
 
 	_ "github.com/PROJECT_NAME/docs"
 	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/lifecycle"
 	"github.com/PROJECT_NAME/internal/registry"
 	"github.com/gofiber/contrib/swagger"
 	"github.com/gofiber/fiber/v2"
@@ -24,6 +25,9 @@ func NewServeCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
+	cmd.Flags().Bool("watch-config", false, "reload the config when the file changes, it is always reloaded on SIGHUP")
+	cmd.Flags().Bool("no-consumers", false, "serve the API without the NATS consumers, they run in the work command")
 
 	return cmd
 }
@@ -42,10 +46,26 @@ func Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to get config file: %v", err)
 	}
 
-	cfg, err := config.Load(configFile)
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get profile: %v", err)
+	}
+
+	watchConfig, err := cmd.Flags().GetBool("watch-config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get watch-config: %v", err)
+	}
+
+	noConsumers, err := cmd.Flags().GetBool("no-consumers")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get no-consumers: %v", err)
+	}
+
+	watcher, err := config.NewWatcher(configFile, config.WithProfile(profile))
 	if err != nil {
 		return fmt.Errorf("❌ Failed to load configuration: %v", err)
 	}
+	cfg := watcher.Config()
 
 	r := registry.NewRegistry(cfg)
 
@@ -56,54 +76,74 @@ func Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	startCtx, startCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer startCancel()
+
+	if !noConsumers {
+		if err := r.StartConsumers(startCtx); err != nil {
+			return err
+		}
+	}
+
+	if err := r.WatchConfig(watcher, watchConfig); err != nil {
+		return err
+	}
+
 	// Create error channel to capture server errors
 	serverErr := make(chan error, 1)
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start server in a goroutine
-	go func() {
-		if err := app.Listen(fmt.Sprintf(":%d", cfg.App.Port)); err != nil {
-			serverErr <- err
-		}
-	}()
+	// The server is started last and stopped first, the in-flight requests complete before the
+	// consumers are drained and the database is closed
+	if err := r.Lifecycle().Register(NewServerComponent(cfg, app, serverErr)); err != nil {
+		return err
+	}
+
+	if err := r.Lifecycle().Start(startCtx); err != nil {
+		return err
+	}
 
 	select {
 	case err := <-serverErr:
+		if cleanupErr := r.Cleanup(); cleanupErr != nil {
+			r.Logger().Errorw(context.Background(), "Error during cleanup", "error", cleanupErr)
+		}
+
 		return fmt.Errorf("server error: %w", err)
 	case sig := <-sigChan:
+		r.Logger().Infow(context.Background(), "Received shutdown signal", "signal", sig)
 
-		// Create shutdown context with timeout
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer shutdownCancel()
-
-		r.Logger().Infow(shutdownCtx, "Received shutdown signal", "signal", sig)
-
-		// Start cleanup in a goroutine
-		done := make(chan struct{})
-		go func() {
-			defer close(done)
-
-			// Cleanup registry (your services, DB connections, etc)
-			r.Cleanup()
-
-			// Graceful shutdown of the fiber app
-			if err := app.ShutdownWithContext(shutdownCtx); err != nil {
-				r.Logger().Errorw(shutdownCtx, "Error during HTTP server shutdown", "error", err)
-			}
-		}()
-
-		// Wait for cleanup to finish or timeout
-		select {
-		case <-done:
-			r.Logger().Infow(shutdownCtx, "Graceful shutdown completed")
-		case <-shutdownCtx.Done():
-			r.Logger().Errorw(shutdownCtx, "Shutdown timed out")
+		// Stops the server, the consumers and the registry (your services, DB connections, etc)
+		if err := r.Cleanup(); err != nil {
+			r.Logger().Errorw(context.Background(), "Error during graceful shutdown", "error", err)
+			return err
 		}
+
+		r.Logger().Infow(context.Background(), "Graceful shutdown completed")
 	}
 
 	return nil
+}
 
+// NewServerComponent runs the fiber app, Listen errors after the start are sent to serverErr.
+func NewServerComponent(cfg *config.Config, app *fiber.App, serverErr chan<- error) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      "http",
+		DependsOn: []string{registry.ComponentDB, registry.ComponentNats, registry.ComponentClients},
+		Start: func(ctx context.Context) error {
+			go func() {
+				if err := app.Listen(fmt.Sprintf(":%d", cfg.App.Port)); err != nil {
+					serverErr <- err
+				}
+			}()
+
+			return nil
+		},
+		// waits for the in-flight requests
+		Stop:    app.ShutdownWithContext,
+		Timeout: 20 * time.Second,
+	}
 }
 
 func NewApp(cfg *config.Config, r *registry.Registry) *fiber.App {
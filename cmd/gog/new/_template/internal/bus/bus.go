@@ -0,0 +1,279 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/nayla-finance/go-nayla/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// DefaultQueueSize is the number of signals queued for an Async or Drop subscriber.
+const DefaultQueueSize = 100
+
+const (
+	// Sync calls the subscriber in Publish, its error is returned by Publish
+	Sync Delivery = iota + 1
+	// Async queues the signal, Publish waits while the queue of the subscriber is full, until
+	// ctx is done or the bus is closed
+	Async
+	// Drop queues the signal, it is dropped and counted when the queue of the subscriber is full
+	Drop
+)
+
+var ErrClosed = errors.New("❌ The signal bus is closed")
+
+var dropped = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "signal_bus_dropped_total",
+	Help: "Signals dropped because the queue of their subscriber was full",
+}, []string{"signal", "subscriber"})
+
+var _ Bus = new(bus)
+
+type (
+	Delivery int
+
+	// Signal is a kind of signal with a payload of type T, declare it once and publish and
+	// subscribe through it:
+	//
+	//	var SignalUserCreated = bus.NewSignal[UserCreated]("user.created")
+	Signal[T any] struct {
+		name string
+	}
+
+	// Bus delivers the signals published in the service to their subscribers, use the
+	// methods of Signal instead of the untyped ones.
+	Bus interface {
+		Subscribe(signal, subscriber string, fn func(ctx context.Context, payload any) error, opts ...SubscribeOption) error
+		Publish(ctx context.Context, signal string, payload any) error
+		// Close stops the publishing and waits for the queued signals to be delivered
+		Close(ctx context.Context) error
+	}
+
+	Provider interface {
+		Bus() Bus
+	}
+
+	SubscribeOption func(s *subscriber)
+
+	busDependencies interface {
+		logger.Provider
+	}
+
+	subscriber struct {
+		signal    string
+		name      string
+		fn        func(ctx context.Context, payload any) error
+		delivery  Delivery
+		queueSize int
+		queue     chan envelope
+	}
+
+	envelope struct {
+		ctx     context.Context
+		payload any
+	}
+
+	bus struct {
+		d           busDependencies
+		mu          sync.RWMutex
+		subscribers map[string][]*subscriber
+		closed      bool
+		// done is closed by Close, before it takes the lock, to release the publishers waiting
+		// for a full queue
+		done      chan struct{}
+		closeOnce sync.Once
+		// publishing counts the Publish delivering without the lock, the queues are closed once
+		// they return
+		publishing sync.WaitGroup
+		wg         sync.WaitGroup
+	}
+)
+
+func NewSignal[T any](name string) Signal[T] {
+	return Signal[T]{name: name}
+}
+
+func (s Signal[T]) Name() string {
+	return s.name
+}
+
+// Publish delivers payload to the subscribers of the signal, it returns the errors of the Sync
+// subscribers and the Async ones that could not be queued before ctx is done.
+func (s Signal[T]) Publish(ctx context.Context, b Bus, payload T) error {
+	return b.Publish(ctx, s.name, payload)
+}
+
+// Subscribe calls fn with the payload of every signal published, name identifies the
+// subscriber in the logs and metrics. The subscribers are Async by default.
+func (s Signal[T]) Subscribe(b Bus, name string, fn func(ctx context.Context, payload T) error, opts ...SubscribeOption) error {
+	return b.Subscribe(s.name, name, func(ctx context.Context, payload any) error {
+		return fn(ctx, payload.(T))
+	}, opts...)
+}
+
+func WithDelivery(d Delivery) SubscribeOption {
+	return func(s *subscriber) {
+		s.delivery = d
+	}
+}
+
+func WithQueueSize(size int) SubscribeOption {
+	return func(s *subscriber) {
+		s.queueSize = size
+	}
+}
+
+func NewBus(d busDependencies) *bus {
+	return &bus{
+		d:           d,
+		subscribers: map[string][]*subscriber{},
+		done:        make(chan struct{}),
+	}
+}
+
+func (b *bus) Subscribe(signal, name string, fn func(ctx context.Context, payload any) error, opts ...SubscribeOption) error {
+	s := &subscriber{
+		signal:    signal,
+		name:      name,
+		fn:        fn,
+		delivery:  Async,
+		queueSize: DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.delivery != Sync && s.queueSize < 1 {
+		return fmt.Errorf("❌ The queue of subscriber %s must hold at least one signal", name)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	if s.delivery != Sync {
+		s.queue = make(chan envelope, s.queueSize)
+
+		b.wg.Add(1)
+		go b.run(s)
+	}
+
+	b.subscribers[signal] = append(b.subscribers[signal], s)
+
+	return nil
+}
+
+// Publish delivers payload to a copy of the subscribers taken under the lock, so the
+// subscribers can subscribe and publish while a signal is delivered.
+func (b *bus) Publish(ctx context.Context, signal string, payload any) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+
+	subscribers := slices.Clone(b.subscribers[signal])
+	b.publishing.Add(1)
+	b.mu.RUnlock()
+
+	defer b.publishing.Done()
+
+	var errs []error
+	for _, s := range subscribers {
+		switch s.delivery {
+		case Sync:
+			if err := b.call(ctx, s, payload); err != nil {
+				errs = append(errs, err)
+			}
+		case Async:
+			// the subscriber outlives the request publishing the signal
+			select {
+			case s.queue <- envelope{ctx: context.WithoutCancel(ctx), payload: payload}:
+			case <-ctx.Done():
+				errs = append(errs, fmt.Errorf("❌ Failed to deliver %s to %s: %w", signal, s.name, ctx.Err()))
+			case <-b.done:
+				errs = append(errs, fmt.Errorf("❌ Failed to deliver %s to %s: %w", signal, s.name, ErrClosed))
+			}
+		case Drop:
+			select {
+			case s.queue <- envelope{ctx: context.WithoutCancel(ctx), payload: payload}:
+			default:
+				dropped.WithLabelValues(signal, s.name).Inc()
+				b.d.Logger().Warnw(ctx, "⚠️ Dropping signal, the subscriber is busy", "signal", signal, "subscriber", s.name)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close releases the publishers waiting for a full queue, then waits for the publishers to
+// return before closing the queues, and for the queued signals to be delivered.
+func (b *bus) Close(ctx context.Context) error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	// no subscriber is added once closed is set
+	done := make(chan struct{})
+	go func() {
+		b.publishing.Wait()
+		for _, subscribers := range b.subscribers {
+			for _, s := range subscribers {
+				if s.queue != nil {
+					close(s.queue)
+				}
+			}
+		}
+
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("❌ Failed to deliver the pending signals: %w", ctx.Err())
+	}
+}
+
+// run delivers the queued signals of s until the bus is closed.
+func (b *bus) run(s *subscriber) {
+	defer b.wg.Done()
+
+	for e := range s.queue {
+		if err := b.call(e.ctx, s, e.payload); err != nil {
+			b.d.Logger().Errorw(e.ctx, "❌ Subscriber failed", "signal", s.signal, "subscriber", s.name, "error", err)
+		}
+	}
+}
+
+// call calls the subscriber, a panic is returned as an error and reported to sentry.
+func (b *bus) call(ctx context.Context, s *subscriber, payload any) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("❌ Subscriber %s of %s panicked: %v", s.name, s.signal, p)
+			sentry.CaptureException(err)
+		}
+	}()
+
+	return s.fn(ctx, payload)
+}
@@ -1,15 +1,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	// Embed timezone data
 	_ "time/tzdata"
 
+	"github.com/PROJECT_NAME/internal/configbase"
 	"github.com/nayla-finance/go-nayla/config"
-	"github.com/nayla-finance/go-nayla/validator"
 	"github.com/spf13/viper"
 )
 
@@ -19,19 +23,109 @@ type ConfigProvider interface {
 
 type (
 	Config struct {
-		App           config.App           `mapstructure:"app"`
-		Health        config.Health        `mapstructure:"health"`
-		Api           config.API           `mapstructure:"api"`
-		Database      config.Database      `mapstructure:"database"`
-		Nats          config.Nats          `mapstructure:"nats"`
-		Sentry        config.Sentry        `mapstructure:"sentry"`
-		OpenTelemetry config.OpenTelemetry `mapstructure:"open_telemetry"`
-		KYC           config.Service       `mapstructure:"kyc"`
-		LOS           config.Service       `mapstructure:"los"`
+		// Base holds the keys every service reads, e.g. app and database
+		configbase.Base `mapstructure:",squash"`
+
+		KYC          config.Service `mapstructure:"kyc"`
+		LOS          config.Service `mapstructure:"los"`
+		FeatureFlags FeatureFlags   `mapstructure:"feature_flags"`
+		Worker       Worker         `mapstructure:"worker"`
+	}
+
+	FeatureFlags struct {
+		// RefreshInterval is how often the flags of the database are read again, to apply the
+		// ones toggled through another instance, 0 disables the refresh
+		RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+		// AdminKey is sent in the X-Admin-Key header to set and delete the flags through the
+		// API, they are read-only when it is empty
+		AdminKey string                 `mapstructure:"admin_key"`
+		Flags    map[string]FeatureFlag `mapstructure:"flags" validate:"dive"`
+	}
+
+	// FeatureFlag is a flag defined in the config, the environment and the database override
+	// it, see featureflags.Flag.
+	FeatureFlag struct {
+		Enabled bool `mapstructure:"enabled"`
+		// Percentage of the subjects the flag is enabled for, all of them when not set
+		Percentage *int `mapstructure:"percentage" validate:"omitempty,min=0,max=100"`
+		// Attributes restrict the flag to the subjects with one of the values, e.g. user_id
+		Attributes map[string][]string `mapstructure:"attributes"`
+	}
+
+	// Worker is the config of the work command running the consumers without the API.
+	Worker struct {
+		// AdminPort serves the health checks and the metrics of the work command
+		AdminPort int `mapstructure:"admin_port"`
+	}
+)
+
+const (
+	// ProfileEnv selects the profile when none is passed, e.g. APP__ENV=production merges
+	// config.production.yaml over config.yaml
+	ProfileEnv = "APP__ENV"
+
+	// filePrefix references a file holding the value, e.g. file:///run/secrets/db_password
+	// or file://secrets/db_password relative to the working directory
+	filePrefix = "file://"
+	// envPrefix references an environment variable holding the value, e.g. env:DB_PASSWORD
+	envPrefix = "env:"
+)
+
+type (
+	Option func(o *options)
+
+	options struct {
+		profile string
+		keyFile string
 	}
 )
 
-func Load(configFile string) (*Config, error) {
+// WithProfile merges config.<profile>.yaml, next to the config file, over it. The profile
+// file must exist, unlike the one selected by APP__ENV.
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// Load reads the config file, the profile merged over it and the environment overrides, then
+// resolves the file:// and env: references and decrypts the enc: values.
+func Load(configFile string, opts ...Option) (*Config, error) {
+	v, _, err := read(configFile, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// resolveProfile returns the profile, APP__ENV when none is passed, and whether its file is
+// required.
+func (o options) resolveProfile() (string, bool) {
+	if o.profile != "" {
+		return o.profile, true
+	}
+
+	return os.Getenv(ProfileEnv), false
+}
+
+// read reads the config file, the profile, the environment overrides and the defaults. It
+// returns the references it resolved by key.
+func read(configFile string, o options) (*viper.Viper, map[string]string, error) {
 	fmt.Println("🔄 Loading configuration from file: ", configFile)
 
 	v := viper.New()
@@ -42,22 +136,127 @@ func Load(configFile string) (*Config, error) {
 	v.AutomaticEnv()
 
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "__"))
+	bindEnv(v)
 
-	// err is ignored to allow reading from os env
+	// a missing file is ignored to allow reading from os env
 	if err := v.ReadInConfig(); err != nil {
-		return nil, err
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("❌ Failed to read %s: %w", configFile, err)
+		}
+		fmt.Println("⚠️ Configuration file not found, reading the environment only: ", configFile)
+	}
+
+	if err := mergeProfile(v, configFile, o); err != nil {
+		return nil, nil, err
 	}
 
 	config.LoadDefaultConfig(v)
-	v.SetDefault("health.dependencies", config.Dependencies{
-		"nats":     config.Dependency{ReadinessCheck: true, LivenessCheck: true},
-		"database": config.Dependency{ReadinessCheck: true, LivenessCheck: true},
-		"kyc":      config.Dependency{ReadinessCheck: false, LivenessCheck: true},
-		"los":      config.Dependency{ReadinessCheck: false, LivenessCheck: true},
-	})
+	dependencies := configbase.DefaultDependencies()
+	dependencies["kyc"] = config.Dependency{ReadinessCheck: false, LivenessCheck: true}
+	dependencies["los"] = config.Dependency{ReadinessCheck: false, LivenessCheck: true}
+	v.SetDefault("health.dependencies", dependencies)
+	v.SetDefault("feature_flags.refresh_interval", 30*time.Second)
+	v.SetDefault("worker.admin_port", 9090)
+
+	refs, err := resolveReferences(v, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return v, refs, nil
+}
+
+// mergeProfile merges the profile file over the config read by v, the profile defaults to
+// APP__ENV and its file is optional then.
+func mergeProfile(v *viper.Viper, configFile string, o options) error {
+	profile, required := o.resolveProfile()
+	if profile == "" {
+		return nil
+	}
+
+	profileFile := ProfileFile(configFile, profile)
+	if _, err := os.Stat(profileFile); err != nil {
+		if required || !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("❌ Failed to read the %s profile: %w", profile, err)
+		}
+		return nil
+	}
+
+	fmt.Println("🔄 Merging configuration profile: ", profileFile)
+
+	v.SetConfigFile(profileFile)
+	if err := v.MergeInConfig(); err != nil {
+		return fmt.Errorf("❌ Failed to merge the %s profile: %w", profile, err)
+	}
+
+	return nil
+}
+
+// ProfileFile returns the file of a profile, e.g. config.production.yaml for config.yaml.
+func ProfileFile(configFile string, profile string) string {
+	ext := filepath.Ext(configFile)
+	return strings.TrimSuffix(configFile, ext) + "." + profile + ext
+}
+
+// resolveReferences replaces the file:// and env: values by the value they reference, the
+// trailing newlines of the files are trimmed, and decrypts the enc: values. It returns the
+// references by key.
+func resolveReferences(v *viper.Viper, o options) (map[string]string, error) {
+	loadKey := o.keyLoader()
+
+	refs := map[string]string{}
+	for _, key := range v.AllKeys() {
+		ref, ok := v.Get(key).(string)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(ref, filePrefix):
+			data, err := os.ReadFile(strings.TrimPrefix(ref, filePrefix))
+			if err != nil {
+				return nil, fmt.Errorf("❌ Failed to resolve %s: %w", key, err)
+			}
+			v.Set(key, strings.TrimRight(string(data), "\r\n"))
+		case strings.HasPrefix(ref, envPrefix):
+			name := strings.TrimPrefix(ref, envPrefix)
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				return nil, fmt.Errorf("❌ Failed to resolve %s: %s is not set", key, name)
+			}
+			v.Set(key, value)
+		case IsEncrypted(ref):
+			configKey, err := loadKey()
+			if err != nil {
+				return nil, fmt.Errorf("❌ Failed to decrypt %s: %w", key, err)
+			}
+
+			value, err := Decrypt(configKey, ref)
+			if err != nil {
+				return nil, fmt.Errorf("❌ Failed to decrypt %s: %w", key, err)
+			}
+			v.Set(key, value)
+
+			// the ciphertext is not printed, it tells nothing
+			ref = strings.TrimSuffix(encPrefix, ":")
+		default:
+			continue
+		}
+
+		refs[key] = ref
+	}
+
+	return refs, nil
+}
+
+// decode unmarshals and validates the config read by v, the error lists all the problems. The
+// config is returned with a *ValidationError, as far as it could be decoded.
+func decode(v *viper.Viper) (*Config, error) {
+	var ps problems
+	decodeKeys(v, &ps)
 
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil && len(ps) == 0 {
 		return nil, err
 	}
 
@@ -71,8 +270,9 @@ func Load(configFile string) (*Config, error) {
 		config.Database.SSLMode = "disable"
 	}
 
-	if err := validator.Validate(config); err != nil {
-		return nil, err
+	validate(&config, &ps)
+	if err := ps.err(); err != nil {
+		return &config, err
 	}
 
 	// 🚨 This only works if os.Setenv is called before any time.Now() is called
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// KeyEnv holds the base64 key decrypting the enc: values, it takes precedence over the key
+	// file
+	KeyEnv = "CONFIG_KEY"
+	// DefaultKeyFile holds the base64 key when KeyEnv is not set
+	DefaultKeyFile = "secrets/config.key"
+
+	// encPrefix marks a value encrypted with AES-256-GCM, followed by the base64 of the nonce
+	// and the ciphertext
+	encPrefix = "enc:v1:"
+	keySize   = 32
+)
+
+// WithKeyFile reads the key decrypting the enc: values from file instead of DefaultKeyFile.
+func WithKeyFile(file string) Option {
+	return func(o *options) {
+		o.keyFile = file
+	}
+}
+
+// IsEncrypted reports whether a value is encrypted, e.g. enc:v1:q83v...
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encPrefix)
+}
+
+// GenerateKey returns a random AES-256 key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("❌ Failed to generate the key: %w", err)
+	}
+
+	return key, nil
+}
+
+// LoadKey returns the key of KeyEnv, or the one of keyFile when it is not set.
+func LoadKey(keyFile string) ([]byte, error) {
+	encoded, ok := os.LookupEnv(KeyEnv)
+	source := KeyEnv
+	if !ok {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read the config key, set %s or create %s: %w", KeyEnv, keyFile, err)
+		}
+		encoded, source = string(data), keyFile
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("❌ The config key of %s is not base64: %w", source, err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("❌ The config key of %s has %d bytes, expected %d", source, len(key), keySize)
+	}
+
+	return key, nil
+}
+
+// WriteKey writes a key to keyFile, readable by its owner only. An existing file is not
+// overwritten.
+func WriteKey(keyFile string, key []byte) error {
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0o700); err != nil {
+		return fmt.Errorf("❌ Failed to create the directory of %s: %w", keyFile, err)
+	}
+
+	f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to create %s: %w", keyFile, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		return fmt.Errorf("❌ Failed to write %s: %w", keyFile, err)
+	}
+
+	return f.Close()
+}
+
+// Encrypt encrypts a value of the config, the result is decrypted by Load.
+func Encrypt(key []byte, value string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("❌ Failed to generate the nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt.
+func Decrypt(key []byte, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encPrefix)
+	if !ok {
+		return "", fmt.Errorf("❌ The value is not encrypted, it must start with %s", encPrefix)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("❌ The encrypted value is not base64: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("❌ The encrypted value is truncated")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to decrypt the value, it was encrypted with another key or modified: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Reencrypt decrypts the enc: values of a config file with oldKey and encrypts them with
+// newKey. It returns the new content of the file and the keys of the values, the file is left
+// untouched. Only the values are replaced, the comments and the layout of the file are kept.
+func Reencrypt(configFile string, oldKey []byte, newKey []byte) ([]byte, []string, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to read %s: %w", configFile, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to parse %s: %w", configFile, err)
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+
+	var keys []string
+	var walk func(n *yaml.Node, key string) error
+	walk = func(n *yaml.Node, key string) error {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, c := range n.Content {
+				if err := walk(c, key); err != nil {
+					return err
+				}
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if err := walk(n.Content[i+1], join(key, strings.ToLower(n.Content[i].Value))); err != nil {
+					return err
+				}
+			}
+		case yaml.ScalarNode:
+			if !IsEncrypted(n.Value) {
+				return nil
+			}
+
+			value, err := Decrypt(oldKey, n.Value)
+			if err != nil {
+				return fmt.Errorf("❌ Failed to decrypt %s of %s: %w", key, configFile, err)
+			}
+
+			encrypted, err := Encrypt(newKey, value)
+			if err != nil {
+				return err
+			}
+
+			// the ciphertext is unique and is not escaped, it is replaced on the line of the value
+			line := n.Line - 1
+			if line < 0 || line >= len(lines) || !bytes.Contains(lines[line], []byte(n.Value)) {
+				return fmt.Errorf("❌ Failed to replace %s of %s, write the enc: value on one line", key, configFile)
+			}
+			lines[line] = bytes.Replace(lines[line], []byte(n.Value), []byte(encrypted), 1)
+			keys = append(keys, key)
+		}
+
+		return nil
+	}
+
+	if err := walk(&doc, ""); err != nil {
+		return nil, nil, err
+	}
+
+	if len(keys) == 0 {
+		return data, nil, nil
+	}
+
+	return bytes.Join(lines, nil), keys, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Invalid config key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyLoader loads the key on the first encrypted value, the configs without one need no key.
+func (o options) keyLoader() func() ([]byte, error) {
+	var (
+		key []byte
+		err error
+	)
+
+	return func() ([]byte, error) {
+		if key == nil && err == nil {
+			keyFile := o.keyFile
+			if keyFile == "" {
+				keyFile = DefaultKeyFile
+			}
+
+			key, err = LoadKey(keyFile)
+		}
+
+		return key, err
+	}
+}
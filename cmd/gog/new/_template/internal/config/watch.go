@@ -0,0 +1,297 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// hotKeys are the keys applied without a restart, with the keys under them. The components
+// read them on every use, e.g. the health checks, or subscribe to the changes, e.g. the logger.
+var hotKeys = []string{
+	"app.log_level",
+	"api.public_routes",
+	"open_telemetry.excluded_routes",
+	"health.liveness.verbose_log",
+	"health.readiness.verbose_log",
+	"feature_flags.flags",
+}
+
+type (
+	// Change is a reload of the config.
+	Change struct {
+		Config *Config
+		// Changed are the keys whose new value is applied
+		Changed []string
+		// RestartRequired are the changed keys that are only applied by a restart, Config keeps
+		// their running value
+		RestartRequired []string
+	}
+
+	// Watcher reloads the config on SIGHUP, or when the file changes, and swaps it atomically.
+	Watcher struct {
+		file    string
+		options options
+		config  atomic.Pointer[Config]
+
+		// mu serializes the reloads
+		mu sync.Mutex
+		// settings are the flattened values of the running config
+		settings    map[string]any
+		subscribers []func(Change)
+	}
+)
+
+// NewWatcher loads the config file, see Load. The reloads merge the same profile.
+func NewWatcher(configFile string, opts ...Option) (*Watcher, error) {
+	o := newOptions(opts)
+	v, _, err := read(configFile, o)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{file: configFile, options: o, settings: settings(v)}
+	w.config.Store(cfg)
+
+	return w, nil
+}
+
+// Config returns the running config.
+func (w *Watcher) Config() *Config {
+	return w.config.Load()
+}
+
+// Subscribe calls fn after every reload changing a key.
+func (w *Watcher) Subscribe(fn func(Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload reads the file and the environment again. The new config is only swapped when it is
+// valid, and the keys that are not hot keep their running value.
+func (w *Watcher) Reload() (Change, error) {
+	change, subscribers, err := w.reload()
+	if err != nil {
+		return Change{}, err
+	}
+
+	// the subscribers run unlocked, they may read, reload or subscribe to the config
+	for _, fn := range subscribers {
+		fn(change)
+	}
+
+	return change, nil
+}
+
+// reload reads and swaps the config under the lock, it returns the subscribers to call when a
+// key changed.
+func (w *Watcher) reload() (Change, []func(Change), error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	v, _, err := read(w.file, w.options)
+	if err != nil {
+		return Change{}, nil, fmt.Errorf("❌ Failed to read the config: %w", err)
+	}
+
+	// the keys requiring a restart are validated too, the next start would fail
+	if _, err := decode(v); err != nil {
+		return Change{}, nil, fmt.Errorf("❌ The new config is invalid, the running one is kept: %w", err)
+	}
+
+	change := Change{}
+	for _, key := range changedKeys(w.settings, settings(v)) {
+		if isHot(key) {
+			change.Changed = append(change.Changed, key)
+			continue
+		}
+
+		// not half-applied: the components created with the old value keep running with it
+		change.RestartRequired = append(change.RestartRequired, key)
+		v.Set(key, w.settings[key])
+	}
+
+	cfg, err := decode(v)
+	if err != nil {
+		return Change{}, nil, fmt.Errorf("❌ Failed to keep the running value of %s: %w", strings.Join(change.RestartRequired, ", "), err)
+	}
+
+	change.Config = cfg
+	if len(change.Changed) == 0 && len(change.RestartRequired) == 0 {
+		return change, nil, nil
+	}
+
+	w.config.Store(cfg)
+	w.settings = settings(v)
+
+	return change, slices.Clone(w.subscribers), nil
+}
+
+// Watch reloads the config on SIGHUP, and when the file changes if watchFile is set, until ctx
+// is done. The reload errors are passed to onError.
+func (w *Watcher) Watch(ctx context.Context, watchFile bool, onError func(err error)) {
+	reload := func() {
+		if _, err := w.Reload(); err != nil {
+			onError(err)
+		}
+	}
+
+	if watchFile {
+		if err := w.watchFiles(ctx, reload, onError); err != nil {
+			onError(err)
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		for {
+			select {
+			case <-hup:
+				reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// watchFiles calls reload when a config file is written or replaced, until ctx is done. The
+// directories are watched since the editors and the Kubernetes ConfigMaps replace the files,
+// the ConfigMaps by swapping a symlink.
+func (w *Watcher) watchFiles(ctx context.Context, reload func(), onError func(err error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("❌ Failed to watch the config: %w", err)
+	}
+
+	// the real paths of the files, by file
+	files := map[string]string{}
+	for _, file := range w.files() {
+		file = filepath.Clean(file)
+		files[file], _ = filepath.EvalSymlinks(file)
+
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("❌ Failed to watch the config: %w", err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filesChanged(files, e) {
+					reload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				onError(fmt.Errorf("❌ Failed to watch the config: %w", err))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// filesChanged reports whether e writes or creates one of the files, or swaps the symlink to
+// it. files are updated with the new real paths.
+func filesChanged(files map[string]string, e fsnotify.Event) bool {
+	changed := false
+	for file, realPath := range files {
+		if filepath.Clean(e.Name) == file && e.Has(fsnotify.Write|fsnotify.Create) {
+			changed = true
+		}
+
+		if current, _ := filepath.EvalSymlinks(file); current != "" && current != realPath {
+			files[file] = current
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// files returns the config file and the file of the profile when it exists.
+func (w *Watcher) files() []string {
+	files := []string{w.file}
+	if profile, _ := w.options.resolveProfile(); profile != "" {
+		profileFile := ProfileFile(w.file, profile)
+		if _, err := os.Stat(profileFile); err == nil {
+			files = append(files, profileFile)
+		}
+	}
+
+	return files
+}
+
+// settings flattens the values of v by key.
+func settings(v *viper.Viper) map[string]any {
+	s := map[string]any{}
+	for _, key := range v.AllKeys() {
+		s[key] = v.Get(key)
+	}
+
+	return s
+}
+
+func changedKeys(old, new map[string]any) []string {
+	var keys []string
+	for key, value := range new {
+		if !reflect.DeepEqual(old[key], value) {
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+func isHot(key string) bool {
+	for _, hot := range hotKeys {
+		if key == hot || strings.HasPrefix(key, hot+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+// Package configbase holds the keys every service reads: the app, the health checks, the API,
+// the database, NATS, Sentry and OpenTelemetry. The Config of the service embeds Base and adds
+// its own keys, e.g. its clients, so a workspace can share the base between its services.
+package configbase
+
+import (
+	"github.com/nayla-finance/go-nayla/config"
+)
+
+type Base struct {
+	App           config.App           `mapstructure:"app"`
+	Health        config.Health        `mapstructure:"health"`
+	Api           config.API           `mapstructure:"api"`
+	Database      config.Database      `mapstructure:"database"`
+	Nats          config.Nats          `mapstructure:"nats"`
+	Sentry        config.Sentry        `mapstructure:"sentry"`
+	OpenTelemetry config.OpenTelemetry `mapstructure:"open_telemetry"`
+}
+
+// DefaultDependencies returns the default health.dependencies of the base: the database and
+// NATS, the service adds its clients.
+func DefaultDependencies() config.Dependencies {
+	return config.Dependencies{
+		"nats":     config.Dependency{ReadinessCheck: true, LivenessCheck: true},
+		"database": config.Dependency{ReadinessCheck: true, LivenessCheck: true},
+	}
+}
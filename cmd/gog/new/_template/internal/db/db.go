@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/PROJECT_NAME/internal/config"
 	"github.com/jmoiron/sqlx"
+	"github.com/nayla-finance/go-nayla/config"
 	"github.com/nayla-finance/go-nayla/logger"
 )
 
@@ -23,8 +23,14 @@ type (
 		DB() Database
 	}
 
+	// DatabaseConfigProvider only exposes the database section of the configuration so the
+	// package does not depend on the service config (and can be shared in a workspace).
+	DatabaseConfigProvider interface {
+		DatabaseConfig() config.Database
+	}
+
 	dbDependencies interface {
-		config.ConfigProvider
+		DatabaseConfigProvider
 		logger.Provider
 	}
 
@@ -34,11 +40,12 @@ type (
 )
 
 func Connect(d dbDependencies) (*db, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s timezone=%s", d.Config().Database.Host, d.Config().Database.Username, d.Config().Database.Password, d.Config().Database.Name, d.Config().Database.Port, d.Config().Database.SSLMode, d.Config().Database.Timezone)
+	cfg := d.DatabaseConfig()
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s timezone=%s", cfg.Host, cfg.Username, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode, cfg.Timezone)
 
 	ctx := context.Background()
 
-	d.Logger().Debugw(ctx, "🔄 Connecting to database", "name", d.Config().Database.Name, "user", d.Config().Database.Username)
+	d.Logger().Debugw(ctx, "🔄 Connecting to database", "name", cfg.Name, "user", cfg.Username)
 	conn, err := sqlx.Connect("postgres", dsn)
 	if err != nil {
 		d.Logger().Errorw(ctx, "❌ Failed to connect to database", "error", err)
@@ -3,12 +3,12 @@ package health
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/PROJECT_NAME/internal/config"
 	"github.com/PROJECT_NAME/internal/db"
 	"github.com/PROJECT_NAME/internal/errors"
-	"github.com/getsentry/sentry-go"
+	"github.com/PROJECT_NAME/internal/healthcheck"
+	"github.com/PROJECT_NAME/internal/lifecycle"
 	"github.com/nayla-finance/go-nayla/clients/rest/kyc"
 	"github.com/nayla-finance/go-nayla/clients/rest/los"
 	"github.com/nayla-finance/go-nayla/logger"
@@ -33,6 +33,7 @@ type (
 		db.DBProvider
 		nats.ServiceProvider
 		config.ConfigProvider
+		lifecycle.ManagerProvider
 		kyc.ClientProvider
 		los.ClientProvider
 	}
@@ -53,128 +54,60 @@ func NewService(d svcDependencies) *svc {
 }
 
 func (s *svc) ReadinessCheck(ctx context.Context) error {
-	isVerbose := s.d.Config().Health.Readiness.VerboseLog
-	if isVerbose {
-		s.PrintServiceDependenciesHealth(ctx)
-	}
-
-	dbConfig, ok := s.d.Config().Health.Dependencies["database"]
-	if ok && dbConfig.ReadinessCheck {
-		if err := s.d.DB().Ping(); err != nil {
-			s.d.Logger().Errorw(ctx, "❌ Database is not healthy", "error", err)
-
-			sentry.CaptureException(fmt.Errorf("❌ Database is not healthy: %w", err))
-			// 🚨 Readiness check for internal dependencies should return an error if they fail
-			return err
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ Database is ready and caffeinated! ☕ It's got its schemas in order and its transactions committed.")
-		}
-	}
-
-	natsConfig, ok := s.d.Config().Health.Dependencies["nats"]
-	if ok && natsConfig.ReadinessCheck {
-		if !s.d.NatsService().Ping(ctx) {
-			s.d.Logger().Errorw(ctx, "❌ Nats connection is not ready")
-
-			sentry.CaptureException(fmt.Errorf("❌ Nats connection is not ready"))
-			// 🚨 Readiness check for internal dependencies should return an error if they fail
-			return fmt.Errorf("❌ Nats connection is not ready")
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ NATS is ready to deliver! 📮 Like a postal service that actually works on time.")
-		}
-	}
-
-	kycConfig, ok := s.d.Config().Health.Dependencies["kyc"]
-	if ok && kycConfig.ReadinessCheck {
-		if err := s.d.KYCClient().Ping(ctx); err != nil {
-			s.d.Logger().Errorw(ctx, "❌ KYC client is not ready", "error", err)
-
-			sentry.CaptureException(fmt.Errorf("❌ KYC client is not ready: %w", err))
-			// 🚨 Readiness check for external dependencies should return an error if they fail
-			return err
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ KYC client is ready for battle! ⚔️ All identities are accounted for and customer data is verified.")
-		}
-	}
-
-	losConfig, ok := s.d.Config().Health.Dependencies["los"]
-	if ok && losConfig.ReadinessCheck {
-		if err := s.d.LOSClient().Ping(ctx); err != nil {
-			s.d.Logger().Errorw(ctx, "❌ LOS client is not ready", "error", err)
+	cfg := s.d.Config().Health
 
-			sentry.CaptureException(fmt.Errorf("❌ LOS client is not ready: %w", err))
-			// 🚨 Readiness check for external dependencies should return an error if they fail
-			return err
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ LOS client is ready for battle! ⚔️ All identities are accounted for and customer data is verified.")
+	// not ready while starting or shutting down, so no new requests are routed to the pod
+	if err := s.d.Lifecycle().Ready(); err != nil {
+		if cfg.Readiness.VerboseLog {
+			healthcheck.PrintDependencies(ctx, s.d.Logger(), cfg)
 		}
+		s.d.Logger().Warnw(ctx, "⚠️ Service is not ready", "error", err)
+		return err
 	}
 
-	s.d.Logger().Infow(ctx, "✅ All service dependencies are healthy and having a great day! 🎉 Time to get back to some serious SMS business!")
-
-	return nil
+	return healthcheck.Ready(ctx, s.d.Logger(), cfg, s.dependencies())
 }
 
 func (s *svc) LivenessCheck(ctx context.Context) error {
-	isVerbose := s.d.Config().Health.Liveness.VerboseLog
-	if isVerbose {
-		s.PrintServiceDependenciesHealth(ctx)
-	}
-
-	var failedServices []string
-
-	dbConfig, ok := s.d.Config().Health.Dependencies["database"]
-	if ok && dbConfig.LivenessCheck {
-		if err := s.d.DB().Ping(); err != nil {
-			s.d.Logger().Errorw(ctx, "❌ Database is not healthy", "error", err)
-			sentry.CaptureException(fmt.Errorf("❌ Database is not healthy: %w", err))
-			return fmt.Errorf("❌ Critical service Database is not healthy: %w", err)
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ Database is alive! 🧟‍♂️ It just told me a joke about SQL injections. Don't worry, I didn't laugh.")
-		}
-	}
-
-	natsConfig, ok := s.d.Config().Health.Dependencies["nats"]
-	if ok && natsConfig.LivenessCheck {
-		if !s.d.NatsService().Ping(ctx) {
-			s.d.Logger().Errorw(ctx, "❌ Nats connection is not healthy")
-			sentry.CaptureException(fmt.Errorf("❌ Nats connection is not healthy"))
-			return fmt.Errorf("❌ Critical service NATS is not healthy")
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ NATS is buzzing with life! 🐝 Messages are flowing faster than gossip in a small town.")
-		}
-	}
-
-	kycConfig, ok := s.d.Config().Health.Dependencies["kyc"]
-	if ok && kycConfig.LivenessCheck {
-		if err := s.d.KYCClient().Ping(ctx); err != nil {
-			s.d.Logger().Errorw(ctx, "❌ KYC client is not healthy", "error", err)
-			sentry.CaptureException(fmt.Errorf("❌ KYC client is not healthy: %w", err))
-			failedServices = append(failedServices, "KYC client")
-		} else if isVerbose {
-			s.d.Logger().Infow(ctx, "✅ KYC client is alive and verifying! 🆔 All identities are properly checked.")
-		}
-	}
-
-	// Only log success if no services failed
-	if len(failedServices) == 0 {
-		s.d.Logger().Infow(ctx, "✅ All service dependencies are healthy and having a great day! 🎉 Time to get back to some serious business!")
-	} else {
-		s.d.Logger().Warnw(ctx, "⚠️ Some services are not healthy, but service is still operational",
-			"failed_services", failedServices,
-			"total_failed", len(failedServices))
-	}
-
-	return nil
+	return healthcheck.Alive(ctx, s.d.Logger(), s.d.Config().Health, s.dependencies())
 }
 
-func (s *svc) PrintServiceDependenciesHealth(ctx context.Context) error {
-	s.d.Logger().Infow(ctx, "This Service Depends on the following dependencies:")
-
-	for dep, config := range s.d.Config().Health.Dependencies {
-		depName := strings.ToLower(dep)
-		s.d.Logger().Infow(ctx, "🔗 Dependency", "name", depName, "readiness_check", config.ReadinessCheck, "liveness_check", config.LivenessCheck)
+// dependencies lists the dependencies of the service, in the order they are checked.
+func (s *svc) dependencies() []healthcheck.Dependency {
+	return []healthcheck.Dependency{
+		{
+			Name:     "database",
+			Label:    "Database",
+			Critical: true,
+			Ping:     func(ctx context.Context) error { return s.d.DB().Ping() },
+			ReadyLog: "✅ Database is ready and caffeinated! ☕ It's got its schemas in order and its transactions committed.",
+			AliveLog: "✅ Database is alive! 🧟‍♂️ It just told me a joke about SQL injections. Don't worry, I didn't laugh.",
+		},
+		{
+			Name:     "nats",
+			Label:    "NATS",
+			Critical: true,
+			Ping: func(ctx context.Context) error {
+				if !s.d.NatsService().Ping(ctx) {
+					return fmt.Errorf("❌ Nats connection is not ready")
+				}
+				return nil
+			},
+			ReadyLog: "✅ NATS is ready to deliver! 📮 Like a postal service that actually works on time.",
+			AliveLog: "✅ NATS is buzzing with life! 🐝 Messages are flowing faster than gossip in a small town.",
+		},
+		{
+			Name:     "kyc",
+			Label:    "KYC client",
+			Ping:     func(ctx context.Context) error { return s.d.KYCClient().Ping(ctx) },
+			ReadyLog: "✅ KYC client is ready for battle! ⚔️ All identities are accounted for and customer data is verified.",
+			AliveLog: "✅ KYC client is alive and verifying! 🆔 All identities are properly checked.",
+		},
+		{
+			Name:     "los",
+			Label:    "LOS client",
+			Ping:     func(ctx context.Context) error { return s.d.LOSClient().Ping(ctx) },
+			ReadyLog: "✅ LOS client is ready for battle! ⚔️ All identities are accounted for and customer data is verified.",
+		},
 	}
-
-	return nil
 }
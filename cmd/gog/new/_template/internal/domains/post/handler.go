@@ -57,7 +57,7 @@ func (h *Handler) CreatePost(c *fiber.Ctx) error {
 		return h.d.NewError(errors.ErrBadRequest, err.Error())
 	}
 
-	return h.d.PostService().CreatePost(c.Context(), dto)
+	return h.d.PostService().CreatePost(c.UserContext(), dto)
 }
 
 // @Summary		Get a post by ID
@@ -75,7 +75,7 @@ func (h *Handler) GetPost(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	post := &model.Post{}
-	if err := h.d.PostService().GetPostByID(c.Context(), uuid.MustParse(id), post); err != nil {
+	if err := h.d.PostService().GetPostByID(c.UserContext(), uuid.MustParse(id), post); err != nil {
 		return h.d.NewError(errors.ErrInternal, err.Error())
 	}
 
@@ -68,12 +68,12 @@ func (s *svc) saveCall(ctx context.Context, dto SaveCallDto) error {
 		ResponseTimeMs: dto.ResponseTime.Milliseconds(),
 	}
 
-	if err := s.d.TrackerRepository().Create(ctx, tracker); err != nil {
+	if err := s.d.TrackerRepository().create(ctx, tracker); err != nil {
 		s.d.Logger().Errorw(ctx, "failed to save tracker", "error", err, "tracker", tracker)
 		return err
 	}
 
-	tracker, err = s.d.TrackerRepository().GetByID(ctx, id)
+	tracker, err = s.d.TrackerRepository().getByID(ctx, id)
 	if err != nil {
 		s.d.Logger().Errorw(ctx, "failed to get tracker", "error", err, "id", id)
 		return err
@@ -0,0 +1,371 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/nayla-finance/go-nayla/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The attributes of the subject set by Middleware, the flags can be restricted to some of
+// their values.
+const (
+	AttributeAPIKey = "api_key"
+	AttributeUserID = "user_id"
+)
+
+// The sources of the flags, a source overrides the flags of the ones before it.
+const (
+	SourceConfig   = "config"
+	SourceEnv      = "env"
+	SourceDatabase = "database"
+)
+
+var (
+	enabledGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "feature_flag_enabled",
+		Help: "1 when the feature flag is enabled, by the source of its definition",
+	}, []string{"flag", "source"})
+
+	percentageGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "feature_flag_percentage",
+		Help: "Percentage of the subjects the feature flag is enabled for",
+	}, []string{"flag"})
+
+	evaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "feature_flag_evaluations_total",
+		Help: "Evaluations of the feature flags by result, unknown for a flag that is not defined",
+	}, []string{"flag", "result"})
+)
+
+// ErrNotStored is returned by Delete for a flag that is not in the database.
+var ErrNotStored = errors.New("❌ The feature flag is not stored in the database")
+
+var _ Flags = new(flags)
+
+type (
+	// Flag enables a feature for all the subjects, a percentage of them, or the ones with an
+	// attribute value.
+	Flag struct {
+		Enabled bool `json:"enabled"`
+		// Percentage of the subjects the flag is enabled for, all of them when nil. A subject is
+		// always in or out, by the hash of its user ID or API key.
+		Percentage *int `json:"percentage,omitempty" validate:"omitempty,min=0,max=100"`
+		// Attributes restrict the flag to the subjects with one of the values of an attribute,
+		// e.g. {"user_id": ["42"]}
+		Attributes map[string][]string `json:"attributes,omitempty"`
+	}
+
+	// State is a flag with the source of its definition.
+	State struct {
+		Name string `json:"name"`
+		Flag
+		Source string `json:"source"`
+	}
+
+	// Change is a reload of the flags changing some of them.
+	Change struct {
+		Flags map[string]State
+		// Changed are the names of the flags added, modified or removed
+		Changed []string
+	}
+
+	// Subject is the caller a flag is evaluated for, by attribute, e.g. user_id.
+	Subject map[string]string
+
+	// Source loads the definitions of the flags by name.
+	Source interface {
+		Name() string
+		Load(ctx context.Context) (map[string]Flag, error)
+	}
+
+	Flags interface {
+		// Enabled reports whether a flag is enabled for the subject of ctx, an unknown flag is
+		// disabled
+		Enabled(ctx context.Context, name string) bool
+		// Get returns a flag and the source of its definition
+		Get(name string) (State, bool)
+		// List returns the flags sorted by name
+		List() []State
+		// Reload loads the sources again, the flags are kept when one fails
+		Reload(ctx context.Context) (Change, error)
+		// Subscribe calls fn after every reload changing a flag
+		Subscribe(fn func(Change))
+		// Watch reloads the flags every interval until ctx is done, e.g. to apply the ones
+		// toggled in the database by another instance
+		Watch(ctx context.Context, interval time.Duration, onError func(err error))
+		// Set stores a flag in the database, it overrides the config and the environment
+		Set(ctx context.Context, name string, flag Flag) error
+		// Delete removes a flag from the database, the config or the environment define it
+		// again. It returns ErrNotStored when the flag is not in the database.
+		Delete(ctx context.Context, name string) error
+	}
+
+	FlagsProvider interface {
+		Flags() Flags
+	}
+
+	flagsDependencies interface {
+		config.ConfigProvider
+		db.DBProvider
+		logger.Provider
+	}
+
+	subjectKey struct{}
+
+	flags struct {
+		d       flagsDependencies
+		repo    *repository
+		sources []Source
+		state   atomic.Pointer[map[string]State]
+
+		// mu serializes the reloads
+		mu          sync.Mutex
+		subscribers []func(Change)
+	}
+)
+
+// NewFlags returns the flags of the config, the environment and the database, in this order
+// of precedence. They are all disabled until the first Reload.
+func NewFlags(d flagsDependencies) *flags {
+	repo := newRepository(d)
+	f := &flags{
+		d:       d,
+		repo:    repo,
+		sources: []Source{configSource{d}, envSource{configSource{d}}, databaseSource{repo}},
+	}
+
+	state := map[string]State{}
+	f.state.Store(&state)
+
+	return f
+}
+
+func (f *flags) Enabled(ctx context.Context, name string) bool {
+	state, ok := (*f.state.Load())[name]
+	if !ok {
+		evaluations.WithLabelValues(name, "unknown").Inc()
+		return false
+	}
+
+	enabled := state.Flag.enabledFor(name, SubjectFrom(ctx))
+	evaluations.WithLabelValues(name, fmt.Sprint(enabled)).Inc()
+
+	return enabled
+}
+
+func (f *flags) Get(name string) (State, bool) {
+	state, ok := (*f.state.Load())[name]
+	return state, ok
+}
+
+func (f *flags) List() []State {
+	states := make([]State, 0, len(*f.state.Load()))
+	for _, state := range *f.state.Load() {
+		states = append(states, state)
+	}
+
+	slices.SortFunc(states, func(a, b State) int { return strings.Compare(a.Name, b.Name) })
+
+	return states
+}
+
+func (f *flags) Reload(ctx context.Context) (Change, error) {
+	change, subscribers, err := f.reload(ctx)
+	if err != nil {
+		return Change{}, err
+	}
+
+	// the subscribers run unlocked, they may read, reload or subscribe to the flags
+	for _, fn := range subscribers {
+		fn(change)
+	}
+
+	return change, nil
+}
+
+// reload loads the sources and swaps the state under the lock, it returns the subscribers to
+// call when a flag changed.
+func (f *flags) reload(ctx context.Context) (Change, []func(Change), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state := map[string]State{}
+	for _, source := range f.sources {
+		loaded, err := source.Load(ctx)
+		if err != nil {
+			return Change{}, nil, fmt.Errorf("❌ Failed to load the feature flags of %s: %w", source.Name(), err)
+		}
+
+		for name, flag := range loaded {
+			state[name] = State{Name: name, Flag: flag, Source: source.Name()}
+		}
+	}
+
+	old := *f.state.Load()
+	change := Change{Flags: state}
+	for name, s := range state {
+		if !reflect.DeepEqual(old[name], s) {
+			change.Changed = append(change.Changed, name)
+		}
+	}
+
+	for name := range old {
+		if _, ok := state[name]; !ok {
+			change.Changed = append(change.Changed, name)
+		}
+	}
+
+	if len(change.Changed) == 0 {
+		return change, nil, nil
+	}
+	slices.Sort(change.Changed)
+
+	f.state.Store(&state)
+	exportState(state)
+
+	return change, slices.Clone(f.subscribers), nil
+}
+
+func (f *flags) Subscribe(fn func(Change)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subscribers = append(f.subscribers, fn)
+}
+
+func (f *flags) Watch(ctx context.Context, interval time.Duration, onError func(err error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := f.Reload(ctx); err != nil {
+					onError(err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (f *flags) Set(ctx context.Context, name string, flag Flag) error {
+	if err := f.repo.upsert(ctx, name, flag); err != nil {
+		return fmt.Errorf("❌ Failed to store the feature flag %s: %w", name, err)
+	}
+
+	_, err := f.Reload(ctx)
+	return err
+}
+
+func (f *flags) Delete(ctx context.Context, name string) error {
+	if err := f.repo.delete(ctx, name); err != nil {
+		if errors.Is(err, ErrNotStored) {
+			return err
+		}
+		return fmt.Errorf("❌ Failed to delete the feature flag %s: %w", name, err)
+	}
+
+	_, err := f.Reload(ctx)
+	return err
+}
+
+// enabledFor evaluates the flag for a subject, name salts the hash of the percentage so the
+// flags at 10% are not all enabled for the same subjects.
+func (f Flag) enabledFor(name string, s Subject) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if len(f.Attributes) > 0 && !f.matches(s) {
+		return false
+	}
+
+	if f.Percentage == nil || *f.Percentage >= 100 {
+		return true
+	}
+
+	id := s[AttributeUserID]
+	if id == "" {
+		id = s[AttributeAPIKey]
+	}
+
+	// an anonymous subject can not stay in or out of the percentage
+	if id == "" {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + id))
+
+	return int(h.Sum32()%100) < *f.Percentage
+}
+
+// matches reports whether the subject has one of the values of an attribute.
+func (f Flag) matches(s Subject) bool {
+	for attribute, values := range f.Attributes {
+		if value, ok := s[attribute]; ok && slices.Contains(values, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithSubject returns a context evaluating the flags for s.
+func WithSubject(ctx context.Context, s Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+// WithAttribute returns a context with an attribute added to its subject, e.g. a tenant.
+func WithAttribute(ctx context.Context, attribute, value string) context.Context {
+	s := Subject{attribute: value}
+	for k, v := range SubjectFrom(ctx) {
+		if k != attribute {
+			s[k] = v
+		}
+	}
+
+	return WithSubject(ctx, s)
+}
+
+// SubjectFrom returns the subject of ctx, nil for an anonymous one.
+func SubjectFrom(ctx context.Context) Subject {
+	s, _ := ctx.Value(subjectKey{}).(Subject)
+	return s
+}
+
+// exportState replaces the metrics of the flags, the removed flags are not exported anymore.
+func exportState(state map[string]State) {
+	enabledGauge.Reset()
+	percentageGauge.Reset()
+
+	for name, s := range state {
+		enabled := 0.0
+		if s.Enabled {
+			enabled = 1
+		}
+		enabledGauge.WithLabelValues(name, s.Source).Set(enabled)
+
+		percentage := 100.0
+		if s.Percentage != nil {
+			percentage = float64(*s.Percentage)
+		}
+		percentageGauge.WithLabelValues(name).Set(percentage)
+	}
+}
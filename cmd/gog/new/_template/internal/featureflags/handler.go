@@ -0,0 +1,152 @@
+package featureflags
+
+import (
+	"crypto/subtle"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nayla-finance/go-nayla/logger"
+	"github.com/nayla-finance/go-nayla/validator"
+)
+
+// AdminKeyHeader carries feature_flags.admin_key, it is required to set and delete the flags.
+const AdminKeyHeader = "X-Admin-Key"
+
+type (
+	handlerDependencies interface {
+		config.ConfigProvider
+		logger.Provider
+		FlagsProvider
+		errors.ErrorProvider
+	}
+
+	// Handler is the admin API of the flags, the flags it sets are stored in the database.
+	Handler struct {
+		d handlerDependencies
+	}
+)
+
+func NewHandler(d handlerDependencies) *Handler {
+	return &Handler{
+		d: d,
+	}
+}
+
+func (h *Handler) RegisterRoutes(api fiber.Router) {
+	api.Get("/feature-flags", h.listFlags)
+	api.Get("/feature-flags/:name", h.getFlag)
+	api.Put("/feature-flags/:name", h.requireAdminKey, h.setFlag)
+	api.Delete("/feature-flags/:name", h.requireAdminKey, h.deleteFlag)
+}
+
+// requireAdminKey restricts a route to the callers sending feature_flags.admin_key in
+// AdminKeyHeader, the API key of the services is not enough. The flags are read-only when no
+// admin key is set.
+func (h *Handler) requireAdminKey(c *fiber.Ctx) error {
+	adminKey := h.d.Config().FeatureFlags.AdminKey
+	if adminKey == "" {
+		return h.d.NewError(errors.ErrForbidden, "the feature flags are read-only, set feature_flags.admin_key to change them")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Get(AdminKeyHeader)), []byte(adminKey)) != 1 {
+		return h.d.NewError(errors.ErrForbidden, "missing or invalid "+AdminKeyHeader)
+	}
+
+	return c.Next()
+}
+
+// @Summary		List the feature flags
+// @Description	List the feature flags with the source of their definition
+// @Tags			feature-flags
+// @Accept			json
+// @Produce		json
+// @Success		200	{array}		featureflags.State
+// @Failure		500	{object}	errors.ErrorResponse
+// @Router			/feature-flags [get]
+func (h *Handler) listFlags(c *fiber.Ctx) error {
+	return c.JSON(h.d.Flags().List())
+}
+
+// @Summary		Get a feature flag
+// @Description	Get a feature flag with the source of its definition
+// @Tags			feature-flags
+// @Accept			json
+// @Produce		json
+// @Param			name	path		string	true	"Flag name"
+// @Success		200		{object}	featureflags.State
+// @Failure		404		{object}	errors.ErrorResponse
+// @Router			/feature-flags/{name} [get]
+func (h *Handler) getFlag(c *fiber.Ctx) error {
+	state, ok := h.d.Flags().Get(c.Params("name"))
+	if !ok {
+		return h.d.NewError(errors.ErrResourceNotFound, "feature flag not found")
+	}
+
+	return c.JSON(state)
+}
+
+// @Summary		Set a feature flag
+// @Description	Store a feature flag in the database, it overrides the config and the environment and is applied by every instance within feature_flags.refresh_interval
+// @Tags			feature-flags
+// @Accept			json
+// @Produce		json
+// @Param			X-Admin-Key	header		string				true	"feature_flags.admin_key"
+// @Param			name		path		string				true	"Flag name"
+// @Param			flag		body		featureflags.Flag	true	"Flag"
+// @Success		200			{object}	featureflags.State
+// @Failure		400			{object}	errors.ErrorResponse
+// @Failure		403			{object}	errors.ErrorResponse
+// @Failure		500			{object}	errors.ErrorResponse
+// @Router			/feature-flags/{name} [put]
+func (h *Handler) setFlag(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	flag := Flag{}
+	if err := c.BodyParser(&flag); err != nil {
+		return h.d.NewError(errors.ErrBadRequest, err.Error())
+	}
+
+	if err := validator.Validate(flag); err != nil {
+		return h.d.NewError(errors.ErrBadRequest, err.Error())
+	}
+
+	if err := h.d.Flags().Set(c.UserContext(), name, flag); err != nil {
+		return h.d.NewError(errors.ErrDatabase, err.Error())
+	}
+
+	h.d.Logger().Infow(c.UserContext(), "🚩 Feature flag set", "flag", name, "enabled", flag.Enabled)
+
+	state, _ := h.d.Flags().Get(name)
+
+	return c.JSON(state)
+}
+
+// @Summary		Delete a feature flag
+// @Description	Delete a feature flag from the database, the config or the environment define it again
+// @Tags			feature-flags
+// @Accept			json
+// @Produce		json
+// @Param			X-Admin-Key	header	string	true	"feature_flags.admin_key"
+// @Param			name		path	string	true	"Flag name"
+// @Success		204			"No Content"
+// @Failure		403			{object}	errors.ErrorResponse
+// @Failure		404			{object}	errors.ErrorResponse
+// @Failure		500			{object}	errors.ErrorResponse
+// @Router			/feature-flags/{name} [delete]
+func (h *Handler) deleteFlag(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	// the flags of the config and the environment are not deleted through the API
+	if state, ok := h.d.Flags().Get(name); !ok || state.Source != SourceDatabase {
+		return h.d.NewError(errors.ErrResourceNotFound, ErrNotStored.Error())
+	}
+
+	if err := h.d.Flags().Delete(c.UserContext(), name); err != nil {
+		return h.d.NewError(errors.ErrDatabase, err.Error())
+	}
+
+	h.d.Logger().Infow(c.UserContext(), "🚩 Feature flag deleted", "flag", name)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PROJECT_NAME/internal/config"
+)
+
+// envPrefix starts the variables of the env source, they are named after the keys of the config
+// like the other variables, e.g. FEATURE_FLAGS__FLAGS__NEW_KYC_FLOW__ENABLED for
+// feature_flags.flags.new_kyc_flow.enabled.
+const envPrefix = "FEATURE_FLAGS__FLAGS__"
+
+type (
+	// configSource reads feature_flags.flags of the running config, it changes on reload.
+	configSource struct {
+		d config.ConfigProvider
+	}
+
+	// envSource reads the FEATURE_FLAGS__FLAGS__<NAME>__ENABLED and
+	// FEATURE_FLAGS__FLAGS__<NAME>__PERCENTAGE variables, they override the fields of the flag
+	// of the config, e.g. its attributes are kept. The other variables are skipped, gog config
+	// validate reports them.
+	envSource struct {
+		config configSource
+	}
+
+	databaseSource struct {
+		repo *repository
+	}
+)
+
+func (s configSource) Name() string {
+	return SourceConfig
+}
+
+func (s configSource) Load(ctx context.Context) (map[string]Flag, error) {
+	flags := map[string]Flag{}
+	for name, f := range s.d.Config().FeatureFlags.Flags {
+		flags[name] = Flag{Enabled: f.Enabled, Percentage: f.Percentage, Attributes: f.Attributes}
+	}
+
+	return flags, nil
+}
+
+func (s envSource) Name() string {
+	return SourceEnv
+}
+
+func (s envSource) Load(ctx context.Context) (map[string]Flag, error) {
+	configured, err := s.config.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := map[string]Flag{}
+	for _, e := range os.Environ() {
+		key, value, _ := strings.Cut(e, "=")
+		rest, ok := strings.CutPrefix(key, envPrefix)
+		if !ok {
+			continue
+		}
+
+		i := strings.LastIndex(rest, "__")
+		if i <= 0 {
+			continue
+		}
+		name, field := strings.ToLower(rest[:i]), rest[i+len("__"):]
+
+		flag, ok := flags[name]
+		if !ok {
+			flag = configured[name]
+		}
+
+		switch field {
+		case "ENABLED":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("❌ %s must be true or false, got %q", key, value)
+			}
+			flag.Enabled = enabled
+		case "PERCENTAGE":
+			percentage, err := strconv.Atoi(value)
+			if err != nil || percentage < 0 || percentage > 100 {
+				return nil, fmt.Errorf("❌ %s must be a percentage between 0 and 100, got %q", key, value)
+			}
+			flag.Percentage = &percentage
+		default:
+			// e.g. the attributes, they are only set by the config file
+			continue
+		}
+		flags[name] = flag
+	}
+
+	return flags, nil
+}
+
+func (s databaseSource) Name() string {
+	return SourceDatabase
+}
+
+func (s databaseSource) Load(ctx context.Context) (map[string]Flag, error) {
+	return s.repo.list(ctx)
+}
@@ -0,0 +1,45 @@
+package registry
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Lazy holds a provider of the registry, it is created on the first Get. Concurrent first
+// calls, e.g. from the request handlers and the consumers, create it once.
+type Lazy[T any] struct {
+	once  sync.Once
+	value T
+}
+
+// Get returns the provider, it is created by newFn on the first call.
+func (l *Lazy[T]) Get(newFn func() T) T {
+	l.once.Do(func() {
+		l.value = newFn()
+	})
+
+	return l.value
+}
+
+// Set provides the value instead of newFn, e.g. a fake in the tests. It has no effect once the
+// value is created.
+func (l *Lazy[T]) Set(value T) {
+	l.once.Do(func() {
+		l.value = value
+	})
+}
+
+// WarmUp creates every provider of RegistryProvider, so the first requests do not pay for it
+// and a constructor panicking fails the startup instead of a request. It must be called once
+// the components are started.
+func (r *Registry) WarmUp() {
+	providers := reflect.TypeOf((*RegistryProvider)(nil)).Elem()
+	registry := reflect.ValueOf(r)
+
+	for i := range providers.NumMethod() {
+		method := registry.MethodByName(providers.Method(i).Name)
+		if method.Type().NumIn() == 0 {
+			method.Call(nil)
+		}
+	}
+}
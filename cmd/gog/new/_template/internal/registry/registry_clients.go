@@ -12,7 +12,7 @@ func (r *Registry) InitializeClients() error {
 	r.kycClient, err = kyc.NewClient(
 		kyc.WithBaseURL(r.Config().KYC.BaseURL),
 		kyc.WithAPIKey(r.Config().KYC.APIKey),
-		kyc.WithLogger(r.Logger()),
+		kyc.WithLogger(r.runningLogger()),
 		kyc.WithErrorResponseMapper(func(er kyc.ErrorResponse) error {
 			// Do any mapping here
 			return r.NewError(errors.ErrInternal, er.Message)
@@ -25,7 +25,7 @@ func (r *Registry) InitializeClients() error {
 	r.losClient, err = los.NewClient(
 		los.WithBaseURL(r.Config().LOS.BaseURL),
 		los.WithAPIKey(r.Config().LOS.APIKey),
-		los.WithLogger(r.Logger()),
+		los.WithLogger(r.runningLogger()),
 		los.WithErrorResponseMapper(func(er los.ErrorResponse) error {
 			// Do any mapping here
 			return r.NewError(errors.ErrInternal, er.Message)
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"context"
+	"slices"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/domains/model"
+	"github.com/PROJECT_NAME/internal/lifecycle"
+	"github.com/nayla-finance/go-nayla/logger"
+)
+
+// WatchConfig registers the config component, it reloads the config on SIGHUP, and when the
+// file changes if watchFile is set. The hot keys of a reload are applied by swapping Config
+// and publishing model.SignalConfigChanged, the other keys are logged as requiring a restart.
+func (r *Registry) WatchConfig(w *config.Watcher, watchFile bool) error {
+	var cancel context.CancelFunc
+
+	return r.Lifecycle().Register(lifecycle.Component{
+		Name:      ComponentConfig,
+		DependsOn: []string{ComponentSignals},
+		Start: func(ctx context.Context) error {
+			w.Subscribe(r.applyConfig)
+
+			// ctx is canceled once the component is started
+			var watchCtx context.Context
+			watchCtx, cancel = context.WithCancel(context.Background())
+			w.Watch(watchCtx, watchFile, func(err error) {
+				r.Logger().Errorw(watchCtx, "❌ Failed to reload the config", "error", err)
+			})
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func (r *Registry) applyConfig(change config.Change) {
+	ctx := context.Background()
+
+	r.config.Store(change.Config)
+
+	if len(change.RestartRequired) > 0 {
+		r.Logger().Warnw(ctx, "⚠️ Config keys changed that are only applied by a restart, they keep their running value", "keys", change.RestartRequired)
+	}
+
+	if len(change.Changed) == 0 {
+		return
+	}
+
+	r.Logger().Infow(ctx, "🔄 Config reloaded", "changed", change.Changed)
+	if err := model.SignalConfigChanged.Publish(ctx, r.Bus(), change); err != nil {
+		r.Logger().Errorw(ctx, "❌ Failed to apply the config change", "error", err)
+	}
+}
+
+// reloadLogger creates the logger again when the log level changes.
+func (r *Registry) reloadLogger(ctx context.Context, change config.Change) error {
+	if !slices.Contains(change.Changed, "app.log_level") {
+		return nil
+	}
+
+	l, err := newLogger(change.Config.App.LogLevel)
+	if err != nil {
+		return err
+	}
+	r.setLogger(l)
+
+	return nil
+}
+
+func newLogger(level string) (logger.Logger, error) {
+	return logger.NewLogger(
+		logger.WithLogLevel(level),
+		logger.WithSpanLevel(level),
+	)
+}
+
+func (r *Registry) setLogger(l logger.Logger) {
+	r.logger.Store(&l)
+}
+
+// runningLogger is given to the middlewares, the NATS service and the clients, they keep the
+// logger they are created with. It logs through the running logger of the registry, so they
+// follow the reloads of app.log_level.
+type runningLogger struct {
+	// Logger is the logger at startup, it serves the methods that are not forwarded
+	logger.Logger
+	r *Registry
+}
+
+func (r *Registry) runningLogger() logger.Logger {
+	return runningLogger{Logger: r.Logger(), r: r}
+}
+
+func (l runningLogger) Debugw(ctx context.Context, msg string, keysAndValues ...any) {
+	l.r.Logger().Debugw(ctx, msg, keysAndValues...)
+}
+
+func (l runningLogger) Infow(ctx context.Context, msg string, keysAndValues ...any) {
+	l.r.Logger().Infow(ctx, msg, keysAndValues...)
+}
+
+func (l runningLogger) Warnw(ctx context.Context, msg string, keysAndValues ...any) {
+	l.r.Logger().Warnw(ctx, msg, keysAndValues...)
+}
+
+func (l runningLogger) Errorw(ctx context.Context, msg string, keysAndValues ...any) {
+	l.r.Logger().Errorw(ctx, msg, keysAndValues...)
+}
@@ -6,17 +6,25 @@ import (
 )
 
 func (r *Registry) PostRepository() post.Repository {
-	if r.postRepository == nil {
-		r.postRepository = post.NewRepository(r)
-	}
-
-	return r.postRepository
+	return r.postRepository.Get(func() post.Repository {
+		return post.NewRepository(r)
+	})
 }
 
 func (r *Registry) PostService() interfaces.PostService {
-	if r.postService == nil {
-		r.postService = post.NewService(r)
+	return r.postService.Get(func() interfaces.PostService {
+		return post.NewService(r)
+	})
+}
+
+func WithPostRepository(repo post.Repository) TestOption {
+	return func(o *testOptions) {
+		o.r.postRepository.Set(repo)
 	}
+}
 
-	return r.postService
+func WithPostService(s interfaces.PostService) TestOption {
+	return func(o *testOptions) {
+		o.r.postService.Set(s)
+	}
 }
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"github.com/PROJECT_NAME/internal/bus"
 	"github.com/PROJECT_NAME/internal/config"
 	"github.com/PROJECT_NAME/internal/db"
 	"github.com/PROJECT_NAME/internal/domains/health"
@@ -8,17 +9,21 @@ import (
 	"github.com/PROJECT_NAME/internal/domains/post"
 	"github.com/PROJECT_NAME/internal/domains/user"
 	"github.com/PROJECT_NAME/internal/errors"
+	"github.com/PROJECT_NAME/internal/featureflags"
+	"github.com/PROJECT_NAME/internal/lifecycle"
 	"github.com/nayla-finance/go-nayla/clients/rest/kyc"
 	"github.com/nayla-finance/go-nayla/clients/rest/los"
+	nconfig "github.com/nayla-finance/go-nayla/config"
 	"github.com/nayla-finance/go-nayla/logger"
 	"github.com/nayla-finance/go-nayla/nats"
 )
 
 type RegistryProvider interface {
-	interfaces.SignalProvider
+	bus.Provider
 	db.DBProvider
 	config.ConfigProvider
 	logger.Provider
+	lifecycle.ManagerProvider
 
 	// errors
 	errors.ErrorProvider
@@ -26,6 +31,8 @@ type RegistryProvider interface {
 
 	nats.ServiceProvider
 
+	featureflags.FlagsProvider
+
 	// domains
 	// user
 	user.RepositoryProvider
@@ -44,11 +51,19 @@ func (r *Registry) DB() db.Database {
 }
 
 func (r *Registry) Config() *config.Config {
-	return r.config
+	return r.config.Load()
+}
+
+func (r *Registry) DatabaseConfig() nconfig.Database {
+	return r.Config().Database
 }
 
 func (r *Registry) Logger() logger.Logger {
-	return r.logger
+	if l := r.logger.Load(); l != nil {
+		return *l
+	}
+
+	return nil
 }
 
 func (r *Registry) NewError(c errors.ErrorCode, m string) *errors.AppError {
@@ -59,19 +74,15 @@ func (r *Registry) NewError(c errors.ErrorCode, m string) *errors.AppError {
 }
 
 func (r *Registry) ErrorHandler() *errors.Handler {
-	if r.errorHandler == nil {
-		r.errorHandler = errors.NewErrorHandler(r)
-	}
-
-	return r.errorHandler
+	return r.errorHandler.Get(func() *errors.Handler {
+		return errors.NewErrorHandler(r)
+	})
 }
 
 func (r *Registry) HealthService() health.Service {
-	if r.healthService == nil {
-		r.healthService = health.NewService(r)
-	}
-
-	return r.healthService
+	return r.healthService.Get(func() health.Service {
+		return health.NewService(r)
+	})
 }
 
 func (r *Registry) NatsService() nats.Service {
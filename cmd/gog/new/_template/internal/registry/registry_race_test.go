@@ -0,0 +1,63 @@
+package registry_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/PROJECT_NAME/internal/registry"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// TestRegistryProvidersRace calls every getter of the registry, its methods without arguments
+// returning a value, from many goroutines, run it with go test -race. The lazy providers must be
+// created once and shared by every caller.
+func TestRegistryProvidersRace(t *testing.T) {
+	const goroutines = 32
+
+	r := registry.NewTestRegistry(t)
+
+	// the methods returning an error only are actions, e.g. Cleanup
+	methods := reflect.TypeOf(r)
+	var getters []string
+	for i := range methods.NumMethod() {
+		if m := methods.Method(i); m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && m.Type.Out(0) != errorType {
+			getters = append(getters, m.Name)
+		}
+	}
+
+	results := make([][]reflect.Value, goroutines)
+
+	var start, wg sync.WaitGroup
+	start.Add(1)
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start.Wait()
+
+			rv := reflect.ValueOf(r)
+			for _, name := range getters {
+				results[g] = append(results[g], rv.MethodByName(name).Call(nil)[0])
+			}
+		}()
+	}
+
+	// the goroutines call the getters at the same time
+	start.Done()
+	wg.Wait()
+
+	for g := 1; g < goroutines; g++ {
+		for i, name := range getters {
+			want, got := results[0][i], results[g][i]
+			if !want.Comparable() || !got.Comparable() {
+				continue
+			}
+
+			if want.Interface() != got.Interface() {
+				t.Errorf("%s returned different providers to concurrent callers", name)
+			}
+		}
+	}
+}
@@ -6,17 +6,25 @@ import (
 )
 
 func (r *Registry) UserRepository() user.Repository {
-	if r.userRepository == nil {
-		r.userRepository = user.NewRepository(r)
-	}
-
-	return r.userRepository
+	return r.userRepository.Get(func() user.Repository {
+		return user.NewRepository(r)
+	})
 }
 
 func (r *Registry) UserService() interfaces.UserService {
-	if r.userService == nil {
-		r.userService = user.NewService(r)
+	return r.userService.Get(func() interfaces.UserService {
+		return user.NewService(r)
+	})
+}
+
+func WithUserRepository(repo user.Repository) TestOption {
+	return func(o *testOptions) {
+		o.r.userRepository.Set(repo)
 	}
+}
 
-	return r.userService
+func WithUserService(s interfaces.UserService) TestOption {
+	return func(o *testOptions) {
+		o.r.userService.Set(s)
+	}
 }
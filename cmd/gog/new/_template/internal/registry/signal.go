@@ -2,51 +2,61 @@ package registry
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/PROJECT_NAME/internal/bus"
 	"github.com/PROJECT_NAME/internal/domains/model"
+	"github.com/PROJECT_NAME/internal/lifecycle"
 )
 
-func (r *Registry) SendSignal(signal model.SignalPayload) {
-	if r.signal == nil {
-		r.Logger().Debugw(context.Background(), "⚠️ Signal channel is not initialized, skipping signal")
-		return
+func (r *Registry) Bus() bus.Bus {
+	return r.bus
+}
+
+// subscribeSignals subscribes the registry to the signals, add the subscribers of your
+// domains here.
+func (r *Registry) subscribeSignals() error {
+	// a restart registers every consumer again, the signals received meanwhile are dropped
+	if err := model.SignalNatsConsumerRestart.Subscribe(r.Bus(), "registry.restart_consumers", r.restartConsumers,
+		bus.WithDelivery(bus.Drop),
+		bus.WithQueueSize(1),
+	); err != nil {
+		return err
+	}
+
+	if err := model.SignalConfigChanged.Subscribe(r.Bus(), "registry.logger", r.reloadLogger, bus.WithDelivery(bus.Sync)); err != nil {
+		return err
 	}
 
-	select {
-	case r.signal <- signal:
-	default:
-		r.Logger().Warnw(context.Background(), "⚠️  Dropping signal – listener not ready")
+	if err := model.SignalConfigChanged.Subscribe(r.Bus(), "registry.feature_flags", r.reloadFeatureFlags); err != nil {
+		return err
 	}
+
+	return nil
 }
 
-func (r *Registry) RegisterSignalListener() {
-	go func() {
-		for signal := range r.signal {
-			ctx := context.Background()
-
-			r.Logger().Debugw(ctx, "Received signal", "type", signal.Type)
-			switch signal.Type {
-			case model.SignalTypeNatsConsumerRestart:
-				if !r.NatsService().Ping(ctx) {
-					r.Logger().Debugw(ctx, "❌ NATS connection is not healthy, reconnecting")
-					if err := r.NatsService().Reconnect(ctx); err != nil {
-						r.Logger().Errorw(ctx, "❌ Failed to reconnect to NATS", "error", err)
-						continue
-					}
-
-					r.Logger().Debugw(ctx, "✅ NATS connection reestablished")
-				}
-
-				r.Logger().Debugw(ctx, "✅ NATS consumer restart requested")
-				if err := r.RegisterConsumers(); err != nil {
-					r.Logger().Errorw(ctx, "❌ Failed to register consumers", "error", err)
-					continue
-				}
-
-				r.Logger().Debugw(ctx, "✅ NATS consumer restart successful")
-			}
+func (r *Registry) restartConsumers(ctx context.Context, signal model.NatsConsumerRestart) error {
+	// the commands without consumers, and the shutdown
+	if r.Lifecycle().State(ComponentConsumers) != lifecycle.StateRunning {
+		return nil
+	}
+
+	r.Logger().Debugw(ctx, "✅ NATS consumer restart requested", "consumer", signal.Consumer)
+
+	if !r.NatsService().Ping(ctx) {
+		r.Logger().Debugw(ctx, "❌ NATS connection is not healthy, reconnecting")
+		if err := r.NatsService().Reconnect(ctx); err != nil {
+			return fmt.Errorf("❌ Failed to reconnect to NATS: %w", err)
 		}
 
-		r.Logger().Debugw(context.Background(), "✅ Signal listener stopped")
-	}()
+		r.Logger().Debugw(ctx, "✅ NATS connection reestablished")
+	}
+
+	if err := r.RegisterConsumers(); err != nil {
+		return fmt.Errorf("❌ Failed to register consumers: %w", err)
+	}
+
+	r.Logger().Debugw(ctx, "✅ NATS consumer restart successful")
+
+	return nil
 }
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE
+    feature_flags (
+        name TEXT PRIMARY KEY,
+        enabled BOOLEAN NOT NULL DEFAULT false,
+        percentage INT NULL CHECK (percentage BETWEEN 0 AND 100),
+        attributes JSONB NOT NULL DEFAULT '{}',
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE feature_flags;
+
+-- +goose StatementEnd
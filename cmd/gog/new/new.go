@@ -4,29 +4,91 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/nayla-finance/gog/internal/project"
+	"github.com/nayla-finance/gog/internal/report"
 	"github.com/spf13/cobra"
 )
 
-//go:embed _template _template/.* _template/**/.*
+//go:embed _template _template/.* _template/**/.* _presets
 var template embed.FS
 
+const banner = `
+    ______      ______       ______   
+   /      \    /      \     /      \  
+  /$$$$$$  |  /$$$$$$  |   /$$$$$$  | 
+  $$ | _$$/   $$ |  $$ |   $$ | _$$/ 
+  $$ |/    |  $$ |  $$ |   $$ |/    | 
+  $$ |$$$$ |  $$ |  $$ |   $$ |$$$$ | 
+  $$ \__$$ |  $$ \__$$ |   $$ \__$$ | 
+  $$    $$/   $$    $$/    $$    $$/ 
+   $$$$$$/     $$$$$$/      $$$$$$/  
+  `
+
 func NewCmd() *cobra.Command {
+	var presets []string
+	for _, p := range project.Presets {
+		presets = append(presets, fmt.Sprintf("  %-9s %s", p.Name, p.Description))
+	}
+
 	cmd := &cobra.Command{
 		Use:   "new [project name]",
 		Short: "Create a new project",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runNew,
+		Long: `Creates a new project from the template.
+
+With --workspace a go.work repository is created instead, with a shared module holding the
+packages every service would otherwise duplicate (--shared, default errors, db, configbase and
+healthcheck). Services are then added from inside the workspace with --in-workspace, they are
+created in services/<name>, import the shared packages and get their targets in the workspace
+justfile.
+
+configbase (or config) holds the keys every service reads, the Config struct of each service
+embeds it and adds its own keys. healthcheck (or health) runs the readiness and liveness
+checks, the health domain of each service lists its dependencies.
+
+--output json prints newline delimited JSON events instead (file_created, step_started,
+step_succeeded, step_failed, completed, error) for IDE integrations, and --quiet only prints
+errors. The banner is only printed on a terminal.
+
+--preset picks the shape of the service, every preset shares the registry, config, logger
+and errors packages:
+
+` + strings.Join(presets, "\n"),
+		Example: "gog new my-service\ngog new notifier --preset worker\ngog new platform --workspace -u my-org\ngog new payments --in-workspace --preset minimal\ngog new ledger --no-examples\ngog new my-service --output json",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reporter, err := report.FromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			// errors are reported as an event too, the JSON output must stay parsable
+			if err := runNew(cmd, args, reporter); err != nil {
+				reporter.Error("Error creating project", err)
+				os.Exit(1)
+			}
+
+			return nil
+		},
 	}
 
 	cmd.Flags().StringP("directory", "d", "", "The path to create the project in (e.g. ./my-project)")
 	cmd.Flags().StringP("username", "u", "", "Github username to create the project in (e.g. github.com/your-github-username/project-name)")
+	cmd.Flags().Bool("workspace", false, "Create a go.work workspace with a shared module instead of a project")
+	cmd.Flags().Bool("in-workspace", false, "Add the project as a service of the workspace containing the current (or --directory) directory")
+	cmd.Flags().StringSlice("shared", project.SharedPackages, "Packages extracted to the shared module of a new workspace")
+	cmd.Flags().String("preset", project.DefaultPreset, "The shape of the service: api, worker, job or minimal")
+	cmd.Flags().Bool("no-examples", false, "Remove the user and post example domains, the api preset keeps an empty registry ready for new domains")
+	report.AddFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("workspace", "in-workspace")
+	cmd.MarkFlagsMutuallyExclusive("workspace", "preset")
+	cmd.MarkFlagsMutuallyExclusive("workspace", "no-examples")
 
 	return cmd
 }
 
-func runNew(cmd *cobra.Command, args []string) error {
+func runNew(cmd *cobra.Command, args []string, reporter report.Reporter) error {
 	if len(args) < 1 {
 		return fmt.Errorf("❌ Missing project name")
 	}
@@ -42,24 +104,88 @@ func runNew(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("❌ Failed to get username flag: %w", err)
 	}
 
-	fmt.Println(`
-    ______      ______       ______   
-   /      \    /      \     /      \  
-  /$$$$$$  |  /$$$$$$  |   /$$$$$$  | 
-  $$ | _$$/   $$ |  $$ |   $$ | _$$/ 
-  $$ |/    |  $$ |  $$ |   $$ |/    | 
-  $$ |$$$$ |  $$ |  $$ |   $$ |$$$$ | 
-  $$ \__$$ |  $$ \__$$ |   $$ \__$$ | 
-  $$    $$/   $$    $$/    $$    $$/ 
-   $$$$$$/     $$$$$$/      $$$$$$/  
-  `)
+	reporter.Banner(banner)
+
+	workspace, err := cmd.Flags().GetBool("workspace")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get workspace flag: %w", err)
+	}
+
+	inWorkspace, err := cmd.Flags().GetBool("in-workspace")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get in-workspace flag: %w", err)
+	}
 
-	p := project.NewProject(template, name, path, gitHubUsername)
+	shared, err := cmd.Flags().GetStringSlice("shared")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get shared flag: %w", err)
+	}
+
+	presetName, err := cmd.Flags().GetString("preset")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get preset flag: %w", err)
+	}
+
+	preset, err := project.FindPreset(presetName)
+	if err != nil {
+		return err
+	}
+
+	noExamples, err := cmd.Flags().GetBool("no-examples")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get no-examples flag: %w", err)
+	}
+
+	if workspace {
+		w, err := project.NewWorkspace(template, name, path, gitHubUsername, shared)
+		if err != nil {
+			return err
+		}
+
+		w.SetReporter(reporter)
+		if err := w.Create(); err != nil {
+			reporter.Error("Error creating workspace", err)
+			os.Exit(1)
+		}
+
+		return nil
+	}
+
+	opts := []project.Option{project.WithPreset(preset)}
+	var w *project.Workspace
+	if inWorkspace {
+		dir := path
+		if dir == "" {
+			dir = "."
+		}
+
+		if w, err = project.FindWorkspace(template, dir); err != nil {
+			return err
+		}
+		w.SetReporter(reporter)
+
+		path = w.ServiceDir(name)
+		opts = w.ProjectOptions(name, preset)
+	}
+
+	if noExamples {
+		opts = append(opts, project.WithoutExamples())
+	}
+	opts = append(opts, project.WithReporter(reporter))
+
+	p := project.NewProject(template, name, path, gitHubUsername, opts...)
 
 	if err := p.Create(); err != nil {
-		fmt.Println("Error creating project:", err)
+		reporter.Error("Error creating project", err)
 		os.Exit(1)
 	}
 
+	if w != nil {
+		if err := w.AddService(name); err != nil {
+			reporter.Error("Error adding project to workspace", err)
+			os.Exit(1)
+		}
+	}
+
 	return nil
 }
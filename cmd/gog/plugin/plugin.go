@@ -0,0 +1,119 @@
+package plugin_cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/nayla-finance/gog/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+// GroupID groups the plugin commands in gog help.
+const GroupID = "plugins"
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage the gog-<name> executables extending gog",
+		Long: `gog runs the executables named gog-<name> found in ~/.config/gog/plugins
+($XDG_CONFIG_HOME/gog/plugins) or on the PATH as "gog <name> [args]". Built-in commands
+can not be replaced by a plugin.
+
+Plugins get the project context in their environment:
+
+  GOG_CONTEXT       the context as JSON (gog version, project directory, module, template lock)
+  GOG_VERSION       the gog version
+  GOG_PROJECT_DIR   the directory of the go.mod above the working directory
+  GOG_MODULE        the module path of the project
+
+The github.com/nayla-finance/gog/plugin package reads the context and copies templates
+into the project with the placeholders of gog new.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the discovered plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found := plugins.Discover()
+			if len(found) == 0 {
+				fmt.Println("No plugins found, add gog-<name> executables to ~/.config/gog/plugins or the PATH")
+				return nil
+			}
+
+			builtin := map[string]bool{}
+			for _, c := range cmd.Root().Commands() {
+				if c.GroupID != GroupID {
+					builtin[c.Name()] = true
+				}
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tPATH")
+			for _, p := range found {
+				path := p.Path
+				if builtin[p.Name] {
+					path += " (ignored, built-in command)"
+				}
+				fmt.Fprintf(w, "%s\t%s\n", p.Name, path)
+
+				for _, s := range p.Shadowed {
+					fmt.Fprintf(w, "\t%s (shadowed)\n", s)
+				}
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+// Commands returns a command per discovered plugin, except the ones named like a command
+// of root.
+func Commands(root *cobra.Command) []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, p := range plugins.Discover() {
+		if c, _, err := root.Find([]string{p.Name}); err == nil && c != root {
+			continue
+		}
+
+		cmds = append(cmds, newPluginCmd(p))
+	}
+
+	return cmds
+}
+
+func newPluginCmd(p *plugins.Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              fmt.Sprintf("Plugin %s", p.Path),
+		GroupID:            GroupID,
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := plugins.NewContext(".")
+			if err != nil {
+				return err
+			}
+
+			if err := plugins.Run(p, ctx, args); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					// the plugin already reported its error
+					os.Exit(exitErr.ExitCode())
+				}
+
+				return err
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package routes_cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nayla-finance/gog/internal/routes"
+	"github.com/nayla-finance/gog/internal/source"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+const defaultConfigFile = "config.yaml"
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "routes [project directory]",
+		Short: "List the HTTP routes of a project",
+		Long: `Statically follows the fiber app from cmd/serve through the registry into every
+RegisterRoutes method and lists each route with its full path, handler and the
+middlewares that run before it.
+
+Middlewares registered with Use on a shared group also apply to the routes registered
+after them by other handlers, those are marked as leaked.
+
+Routes are marked public when they are listed in api.public_routes of the config file,
+an entry ending with * matches every route below it.`,
+		Example: "gog routes\ngog routes ./my-service -c config.yaml.example\ngog routes --json",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runRoutes,
+	}
+
+	cmd.Flags().StringP("config", "c", "", "Config file to read api.public_routes from (default <project directory>/"+defaultConfigFile+" when present)")
+	cmd.Flags().Bool("json", false, "Print the routes as JSON")
+
+	return cmd
+}
+
+func runRoutes(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get config flag: %w", err)
+	}
+
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get json flag: %w", err)
+	}
+
+	m, err := source.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	inv, err := routes.Build(m)
+	if err != nil {
+		return err
+	}
+
+	if configFile == "" {
+		if _, err := os.Stat(filepath.Join(dir, defaultConfigFile)); err == nil {
+			configFile = filepath.Join(dir, defaultConfigFile)
+		}
+	}
+
+	if configFile != "" {
+		publicRoutes, err := readPublicRoutes(configFile)
+		if err != nil {
+			return err
+		}
+
+		inv.MarkPublic(publicRoutes)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(inv)
+	}
+
+	printTable(inv)
+
+	return nil
+}
+
+func readPublicRoutes(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read config file: %w", err)
+	}
+
+	var c struct {
+		Api struct {
+			PublicRoutes []string `yaml:"public_routes"`
+		} `yaml:"api"`
+	}
+
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", file, err)
+	}
+
+	return c.Api.PublicRoutes, nil
+}
+
+func printTable(inv *routes.Inventory) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tPUBLIC\tHANDLER\tLOCATION\tMIDDLEWARES")
+
+	for _, r := range inv.Routes {
+		public := "?"
+		if r.Public != nil {
+			public = map[bool]string{true: "yes", false: "no"}[*r.Public]
+		}
+
+		var mws []string
+		for _, mw := range r.Middlewares {
+			name := mw.Name
+			if mw.Leaked {
+				name += " (leaked from " + mw.Origin + ")"
+			}
+			mws = append(mws, name)
+		}
+
+		middlewares := "-"
+		if len(mws) > 0 {
+			middlewares = strings.Join(mws, ", ")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, public, r.Handler, r.Position, middlewares)
+	}
+
+	w.Flush()
+
+	if len(inv.GlobalMiddlewares) > 0 {
+		var names []string
+		for _, mw := range inv.GlobalMiddlewares {
+			names = append(names, mw.Name)
+		}
+
+		fmt.Printf("\nGlobal middlewares: %s\n", strings.Join(names, ", "))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/nayla-finance/gog/internal/swagger"
 	"github.com/spf13/cobra"
 	"github.com/urfave/cli/v2"
 
@@ -329,6 +330,32 @@ func NewSwag() *cobra.Command {
 				},
 			},
 		},
+		{
+			Name:    "check",
+			Aliases: []string{"c"},
+			Usage:   "check that docs/ matches the swag annotations of a gog project",
+			Action: func(c *cli.Context) error {
+				stale, err := swagger.Stale(c.String(searchDirFlag))
+				if err != nil {
+					return err
+				}
+
+				if len(stale) > 0 {
+					return cli.Exit(fmt.Sprintf("❌ %s out of date, run just swagger", strings.Join(stale, ", ")), 1)
+				}
+
+				fmt.Println("✅ docs/ is up to date")
+				return nil
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    searchDirFlag,
+					Aliases: []string{"d"},
+					Value:   "./",
+					Usage:   "The project directory, the general API info is read from " + swagger.MainFile,
+				},
+			},
+		},
 	}
 
 	swagCmd := &cobra.Command{
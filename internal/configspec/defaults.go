@@ -0,0 +1,265 @@
+package configspec
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// defaults returns the viper defaults set by pkg: the SetDefault calls of its functions and of
+// the functions they pass the *viper.Viper to, like config.LoadDefaultConfig(v). Values that are
+// not constants, e.g. a variable, are nil.
+func defaults(pkg *packages.Package) map[string]any {
+	d := &defaultsFinder{
+		decls:    map[*types.Func]decl{},
+		visited:  map[*types.Func]bool{},
+		defaults: map[string]any{},
+	}
+
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		for _, file := range p.Syntax {
+			for _, n := range file.Decls {
+				fn, ok := n.(*ast.FuncDecl)
+				if !ok || fn.Body == nil || p.TypesInfo == nil {
+					continue
+				}
+				if obj, ok := p.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+					d.decls[obj] = decl{fn: fn, info: p.TypesInfo}
+				}
+			}
+		}
+	})
+
+	for _, file := range pkg.Syntax {
+		for _, n := range file.Decls {
+			if fn, ok := n.(*ast.FuncDecl); ok && fn.Body != nil {
+				d.inspect(fn.Body, pkg.TypesInfo)
+			}
+		}
+	}
+
+	return d.defaults
+}
+
+type (
+	decl struct {
+		fn   *ast.FuncDecl
+		info *types.Info
+	}
+
+	defaultsFinder struct {
+		decls    map[*types.Func]decl
+		visited  map[*types.Func]bool
+		defaults map[string]any
+	}
+)
+
+func (d *defaultsFinder) inspect(body ast.Node, info *types.Info) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		fn := callee(call, info)
+		if fn == nil {
+			return true
+		}
+
+		if fn.Name() == "SetDefault" && isViper(fn.Type().(*types.Signature).Recv()) && len(call.Args) == 2 {
+			if tv, ok := info.Types[call.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+				v, _ := eval(call.Args[1], info)
+				flatten(d.defaults, strings.ToLower(constant.StringVal(tv.Value)), v)
+			}
+			return true
+		}
+
+		if d.visited[fn] || !takesViper(fn) {
+			return true
+		}
+		d.visited[fn] = true
+
+		if decl, ok := d.decls[fn.Origin()]; ok {
+			d.inspect(decl.fn.Body, decl.info)
+		}
+
+		return true
+	})
+}
+
+func callee(call *ast.CallExpr, info *types.Info) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+// isViper reports whether v is a *viper.Viper.
+func isViper(v *types.Var) bool {
+	if v == nil {
+		return false
+	}
+
+	ptr, ok := v.Type().(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Name() == "Viper" && named.Obj().Pkg() != nil &&
+		strings.HasSuffix(named.Obj().Pkg().Path(), "/viper")
+}
+
+func takesViper(fn *types.Func) bool {
+	params := fn.Type().(*types.Signature).Params()
+	for i := 0; i < params.Len(); i++ {
+		if isViper(params.At(i)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// eval evaluates the constants and the composite literals of constants, the structs are
+// keyed by their mapstructure keys like viper sees them.
+func eval(expr ast.Expr, info *types.Info) (any, bool) {
+	tv, ok := info.Types[expr]
+	if !ok {
+		return nil, false
+	}
+
+	if tv.Value != nil {
+		return constantValue(tv.Value, tv.Type), true
+	}
+
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	switch t := tv.Type.Underlying().(type) {
+	case *types.Map:
+		m := map[string]any{}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return nil, false
+			}
+
+			k, ok := eval(kv.Key, info)
+			if !ok {
+				return nil, false
+			}
+
+			v, ok := eval(kv.Value, info)
+			if !ok {
+				return nil, false
+			}
+
+			m[strings.ToLower(fmt.Sprint(k))] = v
+		}
+		return m, true
+	case *types.Struct:
+		m := map[string]any{}
+		for i, elt := range lit.Elts {
+			field, value := i, elt
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				field, value = fieldIndex(t, kv.Key), kv.Value
+			}
+			if field < 0 {
+				return nil, false
+			}
+
+			v, ok := eval(value, info)
+			if !ok {
+				return nil, false
+			}
+
+			m[fieldKey(t, field)] = v
+		}
+		return m, true
+	case *types.Slice, *types.Array:
+		var s []any
+		for _, elt := range lit.Elts {
+			v, ok := eval(elt, info)
+			if !ok {
+				return nil, false
+			}
+			s = append(s, v)
+		}
+		return s, true
+	}
+
+	return nil, false
+}
+
+func constantValue(v constant.Value, t types.Type) any {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.Int:
+		i, _ := constant.Int64Val(v)
+		if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil &&
+			named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration" {
+			return time.Duration(i).String()
+		}
+		return i
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f
+	}
+
+	return v.ExactString()
+}
+
+func fieldIndex(s *types.Struct, key ast.Expr) int {
+	id, ok := key.(*ast.Ident)
+	if !ok {
+		return -1
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == id.Name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// fieldKey is the mapstructure key of a field of s.
+func fieldKey(s *types.Struct, i int) string {
+	name, _, _ := strings.Cut(reflect.StructTag(s.Tag(i)).Get("mapstructure"), ",")
+	if name == "" {
+		name = s.Field(i).Name()
+	}
+
+	return strings.ToLower(name)
+}
+
+// flatten stores v and its leaves under key, viper resolves the nested keys of a map default.
+func flatten(defaults map[string]any, key string, v any) {
+	defaults[key] = v
+
+	m, _ := v.(map[string]any)
+	for k, child := range m {
+		flatten(defaults, key+"."+k, child)
+	}
+}
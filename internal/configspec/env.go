@@ -0,0 +1,253 @@
+package configspec
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// secretWords mark the keys whose values are secrets, they are matched against the last
+// segment of the key.
+var secretWords = []string{"password", "secret", "token", "api_key", "apikey", "admin_key", "dsn", "private_key"}
+
+// EnvVar is an environment variable overriding a key of the config.
+type EnvVar struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// Default is the viper default, empty when HasDefault is false
+	Default     string `json:"default,omitempty"`
+	HasDefault  bool   `json:"has_default"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	Description string `json:"description,omitempty"`
+}
+
+// EnvVars returns the environment variables of every key that is not an object. The keys
+// of a map are the ones of its default, or a <NAME> placeholder.
+func (s *Spec) EnvVars() []EnvVar {
+	var vars []EnvVar
+	for _, f := range s.expand(s.Root) {
+		v := EnvVar{
+			Name:        EnvName(f.Key),
+			Key:         f.Key,
+			Type:        typeName(f),
+			Required:    f.Required,
+			Secret:      IsSecret(f.Key),
+			Description: strings.Join(strings.Fields(f.Doc), " "),
+		}
+
+		if d, ok := s.Default(f.Key); ok && d != nil {
+			v.Default, v.HasDefault = formatValue(d), true
+		}
+
+		vars = append(vars, v)
+	}
+
+	return vars
+}
+
+// expand returns the leaves of f, the maps are expanded to the keys of their default.
+func (s *Spec) expand(f *Field) []*Field {
+	switch f.Kind {
+	case KindObject:
+		var leaves []*Field
+		for _, c := range f.Fields {
+			leaves = append(leaves, s.expand(c)...)
+		}
+		return leaves
+	case KindMap:
+		d, _ := s.Default(f.Key)
+		m, _ := d.(map[string]any)
+		if len(m) == 0 {
+			return s.expand(withKey(f.Elem, join(f.Key, "<name>")))
+		}
+
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var leaves []*Field
+		for _, name := range names {
+			leaves = append(leaves, s.expand(withKey(f.Elem, join(f.Key, name)))...)
+		}
+		return leaves
+	}
+
+	return []*Field{f}
+}
+
+// IsSecret reports whether the value of a key is a secret, e.g. database.password.
+func IsSecret(key string) bool {
+	name := strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+	if name == "key" {
+		return true
+	}
+
+	for _, w := range secretWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeName(f *Field) string {
+	if f.Kind == KindArray {
+		return "list of " + typeName(f.Elem) + " (comma separated)"
+	}
+
+	return string(f.Kind)
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// envNote explains when the variables of the map entries are read: config.Load binds the
+// variable of every key of the struct, but not of the entries it does not know.
+const envNote = "The <NAME> variables of the map entries, e.g. HEALTH__DEPENDENCIES__<NAME>__LIVENESS_CHECK, are only read when the entry is in the config file or has a default. The feature flags read FEATURE_FLAGS__FLAGS__<NAME>__ENABLED and __PERCENTAGE for any flag."
+
+// WriteMarkdown writes the variables as a markdown table.
+func WriteMarkdown(w io.Writer, vars []EnvVar) error {
+	var b strings.Builder
+	b.WriteString("| Variable | Type | Default | Required | Secret | Description |\n")
+	b.WriteString("| -------- | ---- | ------- | -------- | ------ | ----------- |\n")
+	for _, v := range vars {
+		def := ""
+		if v.HasDefault {
+			def = "`" + v.Default + "`"
+		}
+
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n", v.Name, v.Type, def, yesNo(v.Required), yesNo(v.Secret),
+			strings.ReplaceAll(v.Description, "|", `\|`))
+	}
+	b.WriteString("\n" + envNote + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDotenv writes the variables as a .env example, the secrets are left empty.
+func WriteDotenv(w io.Writer, vars []EnvVar) error {
+	var b strings.Builder
+	b.WriteString("# " + envNote + "\n")
+
+	section := ""
+	for _, v := range vars {
+		if top, _, _ := strings.Cut(v.Key, "."); top != section {
+			section = top
+			fmt.Fprintf(&b, "\n# %s\n", section)
+		}
+
+		b.WriteString(comment(v))
+
+		value := v.Default
+		if v.Secret {
+			value = ""
+		}
+		fmt.Fprintf(&b, "%s%s=%s\n", placeholder(v), v.Name, quoteEnv(value))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteKubernetes writes a ConfigMap with the variables and a Secret with the secrets, both
+// named after name, for the envFrom of a Deployment.
+func WriteKubernetes(w io.Writer, name string, vars []EnvVar) error {
+	var config, secrets []EnvVar
+	for _, v := range vars {
+		if v.Secret {
+			secrets = append(secrets, v)
+		} else {
+			config = append(config, v)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s-config\ndata:\n", envNote, name)
+	writeKubernetesData(&b, config)
+
+	fmt.Fprintf(&b, "---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: %s-secrets\ntype: Opaque\nstringData:\n", name)
+	writeKubernetesData(&b, secrets)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeKubernetesData(b *strings.Builder, vars []EnvVar) {
+	if len(vars) == 0 {
+		b.WriteString("  {}\n")
+		return
+	}
+
+	for _, v := range vars {
+		b.WriteString("  " + comment(v))
+
+		value := v.Default
+		if v.Secret {
+			value = ""
+		}
+		fmt.Fprintf(b, "  %s%s: %s\n", placeholder(v), v.Name, strconv.Quote(value))
+	}
+}
+
+// comment is the comment line describing a variable.
+func comment(v EnvVar) string {
+	details := []string{v.Key, v.Type}
+	if v.Required {
+		details = append(details, "required")
+	}
+
+	c := "# " + strings.Join(details, ", ")
+	if v.Description != "" {
+		c += ": " + v.Description
+	}
+
+	return c + "\n"
+}
+
+// placeholder comments out the variables of the map keys without a default, <NAME> is not a
+// valid variable name.
+func placeholder(v EnvVar) string {
+	if strings.Contains(v.Name, "<") {
+		return "# "
+	}
+
+	return ""
+}
+
+func quoteEnv(value string) string {
+	if strings.ContainsAny(value, " #\"'$") {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return ""
+}
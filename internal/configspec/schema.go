@@ -0,0 +1,110 @@
+package configspec
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// durationPattern matches the strings accepted by time.ParseDuration.
+const durationPattern = `^[-+]?(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+$`
+
+// Schema returns the JSON Schema of the config file, for the completion and validation of
+// config.yaml in editors. Keys with a default are not required.
+func (s *Spec) Schema(title string) ([]byte, error) {
+	schema := s.schema(s.Root)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = title
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (s *Spec) schema(f *Field) map[string]any {
+	schema := map[string]any{}
+	if f.Doc != "" {
+		schema["description"] = f.Doc
+	}
+
+	if v, ok := s.Default(f.Key); ok && v != nil && f.Key != "" {
+		schema["default"] = v
+	}
+
+	switch f.Kind {
+	case KindObject:
+		schema["type"] = "object"
+		properties := map[string]any{}
+		var required []string
+		for _, c := range f.Fields {
+			properties[c.Name] = s.schema(c)
+			if c.Required && !s.HasDefault(c.Key) {
+				required = append(required, c.Name)
+			}
+		}
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case KindMap:
+		schema["type"] = "object"
+		schema["additionalProperties"] = s.schema(f.Elem)
+	case KindArray:
+		schema["type"] = "array"
+		schema["items"] = s.schema(f.Elem)
+	case KindDuration:
+		schema["type"] = []string{"string", "integer"}
+		schema["pattern"] = durationPattern
+	case KindAny:
+	default:
+		schema["type"] = string(f.Kind)
+	}
+
+	for _, rule := range strings.Split(f.Validate, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			var enum []any
+			for _, v := range strings.Fields(param) {
+				enum = append(enum, scalar(f.Kind, v))
+			}
+			schema["enum"] = enum
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			bound := map[string]string{"min": "minimum", "gte": "minimum", "max": "maximum", "lte": "maximum"}[name]
+			switch f.Kind {
+			case KindString:
+				bound = strings.Replace(bound, "imum", "Length", 1)
+			case KindArray:
+				bound = strings.Replace(bound, "imum", "Items", 1)
+			case KindMap:
+				bound = strings.Replace(bound, "imum", "Properties", 1)
+			}
+			schema[bound] = n
+		case "url", "http_url":
+			schema["format"] = "uri"
+		case "email":
+			schema["format"] = "email"
+		}
+	}
+
+	return schema
+}
+
+// scalar converts an oneof value to the type of the key.
+func scalar(kind Kind, v string) any {
+	switch kind {
+	case KindInteger:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case KindNumber:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+
+	return v
+}
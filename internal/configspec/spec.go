@@ -0,0 +1,341 @@
+// Package configspec reads the config struct of a project with its type information: the
+// mapstructure keys, validate tags, doc comments and viper defaults. gog is not linked with the
+// project, so the struct is type checked from source instead of reflected.
+package configspec
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	// DefaultPackage is the package of the config struct, relative to the module.
+	DefaultPackage = "internal/config"
+	// DefaultType is the config struct unmarshalled by config.Load.
+	DefaultType = "Config"
+)
+
+// Kind is the kind of value of a key.
+type Kind string
+
+const (
+	KindObject   Kind = "object"
+	KindMap      Kind = "map"
+	KindArray    Kind = "array"
+	KindString   Kind = "string"
+	KindInteger  Kind = "integer"
+	KindNumber   Kind = "number"
+	KindBoolean  Kind = "boolean"
+	KindDuration Kind = "duration"
+	KindAny      Kind = "any"
+)
+
+// String returns the kind with its article, e.g. an integer.
+func (k Kind) String() string {
+	switch k {
+	case KindObject, KindInteger, KindArray, KindAny:
+		return "an " + string(k)
+	default:
+		return "a " + string(k)
+	}
+}
+
+type (
+	Options struct {
+		// Package is the import path of the config package relative to the module, DefaultPackage when empty
+		Package string
+		// Type is the name of the config struct, DefaultType when empty
+		Type string
+	}
+
+	// Spec is the config of a project.
+	Spec struct {
+		// Root is the config struct, its Key is empty
+		Root *Field
+		// Defaults are the viper defaults by lower case dotted key, maps and structs are
+		// flattened to their leaves
+		Defaults map[string]any
+	}
+
+	// Field is a key of the config.
+	Field struct {
+		// Key is the lower case dotted key, e.g. database.password
+		Key  string
+		Name string
+		Kind Kind
+		// Type is the Go type, e.g. time.Duration
+		Type     string
+		Required bool
+		// Validate is the validate tag
+		Validate string
+		Doc      string
+		// Fields are the keys of an object
+		Fields []*Field
+		// Elem is the value of the items of an array or the values of a map
+		Elem *Field
+	}
+)
+
+// Load type checks the config package of the project in dir and returns its config struct.
+// The dependencies of the project must be downloaded.
+func Load(dir string, opts Options) (*Spec, error) {
+	if opts.Package == "" {
+		opts.Package = DefaultPackage
+	}
+	if opts.Type == "" {
+		opts.Type = DefaultType
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read go.mod in '%s': %w", dir, err)
+	}
+	module := modfile.ModulePath(data)
+
+	pkgPath := module + "/" + strings.Trim(opts.Package, "/")
+	pkgs, err := packages.Load(&packages.Config{
+		Dir: dir,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+	}, pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to load %s: %w", pkgPath, err)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("❌ Failed to load %s", pkgPath)
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("❌ Failed to type check %s, run go mod download: %v", pkgPath, pkg.Errors[0])
+	}
+
+	obj, ok := pkg.Types.Scope().Lookup(opts.Type).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("❌ No type %s in %s", opts.Type, pkgPath)
+	}
+
+	l := &loader{docs: map[token.Pos]string{}}
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		for _, file := range p.Syntax {
+			l.indexDocs(file)
+		}
+	})
+
+	spec := &Spec{
+		Root:     l.field("", "", obj.Type(), "", 0),
+		Defaults: defaults(pkg),
+	}
+
+	return spec, nil
+}
+
+// Lookup returns the field of a dotted key, the keys of a map are matched by its Elem.
+func (s *Spec) Lookup(key string) *Field {
+	f := s.Root
+	for _, part := range strings.Split(strings.ToLower(key), ".") {
+		switch f.Kind {
+		case KindObject:
+			f = f.Child(part)
+		case KindMap:
+			f = f.Elem
+		default:
+			return nil
+		}
+
+		if f == nil {
+			return nil
+		}
+	}
+
+	return f
+}
+
+// Default returns the default of a key, it is set when the key or one of its parents has one.
+func (s *Spec) Default(key string) (any, bool) {
+	v, ok := s.Defaults[strings.ToLower(key)]
+	return v, ok
+}
+
+// HasDefault reports whether a key, one of its parents or one of its children has a default.
+func (s *Spec) HasDefault(key string) bool {
+	key = strings.ToLower(key)
+	for k := range s.Defaults {
+		if k == key || strings.HasPrefix(k, key+".") || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Child returns the field of an object with the given key segment.
+func (f *Field) Child(name string) *Field {
+	for _, c := range f.Fields {
+		if c.Name == strings.ToLower(name) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Leaves returns the fields that are not objects, in key order.
+func (f *Field) Leaves() []*Field {
+	if f.Kind != KindObject {
+		return []*Field{f}
+	}
+
+	var leaves []*Field
+	for _, c := range f.Fields {
+		leaves = append(leaves, c.Leaves()...)
+	}
+
+	return leaves
+}
+
+type loader struct {
+	// docs are the doc comments of the struct fields by position of their name
+	docs map[token.Pos]string
+}
+
+func (l *loader) indexDocs(file *ast.File) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		f, ok := n.(*ast.Field)
+		if !ok || len(f.Names) == 0 {
+			return true
+		}
+
+		doc := f.Doc.Text()
+		if doc == "" {
+			doc = f.Comment.Text()
+		}
+
+		for _, name := range f.Names {
+			l.docs[name.Pos()] = strings.TrimSpace(doc)
+		}
+
+		return true
+	})
+}
+
+// maxDepth stops recursive types.
+const maxDepth = 16
+
+func (l *loader) field(key, name string, t types.Type, doc string, depth int) *Field {
+	f := &Field{Key: key, Name: name, Type: types.TypeString(t, shortQualifier), Doc: doc}
+
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+		case "time.Duration":
+			f.Kind = KindDuration
+			return f
+		case "time.Time":
+			f.Kind = KindString
+			return f
+		}
+	}
+
+	if depth > maxDepth {
+		f.Kind = KindAny
+		return f
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsBoolean != 0:
+			f.Kind = KindBoolean
+		case info&types.IsInteger != 0:
+			f.Kind = KindInteger
+		case info&types.IsFloat != 0:
+			f.Kind = KindNumber
+		default:
+			f.Kind = KindString
+		}
+	case *types.Pointer:
+		elem := l.field(key, name, u.Elem(), doc, depth+1)
+		elem.Type = f.Type
+		return elem
+	case *types.Slice:
+		f.Kind = KindArray
+		f.Elem = l.field(join(key, "*"), "*", u.Elem(), "", depth+1)
+	case *types.Array:
+		f.Kind = KindArray
+		f.Elem = l.field(join(key, "*"), "*", u.Elem(), "", depth+1)
+	case *types.Map:
+		f.Kind = KindMap
+		f.Elem = l.field(join(key, "*"), "*", u.Elem(), "", depth+1)
+	case *types.Struct:
+		f.Kind = KindObject
+		f.Fields = l.fields(key, u, depth)
+	default:
+		f.Kind = KindAny
+	}
+
+	return f
+}
+
+// fields returns the keys of a struct, squashed structs are inlined like mapstructure does.
+func (l *loader) fields(key string, s *types.Struct, depth int) []*Field {
+	var fields []*Field
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		if !v.Exported() {
+			continue
+		}
+
+		tag := reflect.StructTag(s.Tag(i))
+		name, opts, _ := strings.Cut(tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if strings.Contains(opts, "squash") {
+			if st, ok := v.Type().Underlying().(*types.Struct); ok {
+				fields = append(fields, l.fields(key, st, depth+1)...)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = v.Name()
+		}
+		name = strings.ToLower(name)
+
+		f := l.field(join(key, name), name, v.Type(), l.docs[v.Pos()], depth+1)
+		f.Validate = tag.Get("validate")
+		for _, rule := range strings.Split(f.Validate, ",") {
+			if rule == "required" {
+				f.Required = true
+			}
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+func join(key, name string) string {
+	if key == "" {
+		return name
+	}
+
+	return key + "." + name
+}
+
+// shortQualifier prints the types with their package name, e.g. config.Dependency.
+func shortQualifier(p *types.Package) string {
+	return p.Name()
+}
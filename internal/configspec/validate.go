@@ -0,0 +1,460 @@
+package configspec
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+type (
+	ValidateOptions struct {
+		// Env are the environment variables as NAME=value, config.Load reads database.password
+		// from DATABASE__PASSWORD even when the file has no database.password
+		Env []string
+		// Dir is the directory the service runs in, the paths of pathKeys are relative to it.
+		// They are not checked when it is empty.
+		Dir string
+	}
+
+	// Problem is a key of the config file or an environment variable viper would reject,
+	// ignore or that fails validation.
+	Problem struct {
+		// Source is the config file or the environment variable
+		Source  string `json:"source"`
+		Line    int    `json:"line,omitempty"`
+		Key     string `json:"key"`
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+)
+
+func (p Problem) String() string {
+	source := p.Source
+	if p.Line > 0 {
+		source += ":" + strconv.Itoa(p.Line)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", source, p.Key, p.Message)
+}
+
+// EnvName returns the environment variable of a key, config.Load replaces . with __.
+func EnvName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "__"))
+}
+
+// Validate checks a config file and the environment variables against the config struct:
+// unknown keys, values viper can not decode into their field and required keys set neither
+// by the file, the environment nor a default. name is the file name printed in the problems.
+func (s *Spec) Validate(name string, data []byte, opts ValidateOptions) ([]Problem, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", name, err)
+	}
+
+	v := &validator{spec: s, source: name, dir: opts.Dir, set: map[string]int{}}
+	if len(doc.Content) > 0 {
+		v.node(doc.Content[0], s.Root)
+	}
+
+	fileProblems := v.problems
+	sort.SliceStable(fileProblems, func(i, j int) bool { return fileProblems[i].Line < fileProblems[j].Line })
+
+	v.problems = nil
+	v.env(opts.Env)
+	v.required(s.Root)
+
+	return append(fileProblems, v.problems...), nil
+}
+
+// pathKeys are the keys holding a path, config.Load does not check them: the migrate commands
+// and the NATS component fail when they can not read them.
+var pathKeys = map[string]pathKey{
+	"database.migrations_dir": {dir: true, level: LevelError},
+	// secrets/ is mounted at runtime, a checkout has no creds
+	"nats.creds_path": {level: LevelWarning},
+}
+
+type pathKey struct {
+	dir   bool
+	level string
+}
+
+type validator struct {
+	spec   *Spec
+	source string
+	dir    string
+	// set are the keys of the file with their line and the keys set by the environment with 0
+	set      map[string]int
+	problems []Problem
+}
+
+func (v *validator) add(source string, line int, key, level, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Source: source, Line: line, Key: key, Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) node(n *yaml.Node, f *Field) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	if n.Tag == "!!null" {
+		return
+	}
+
+	if f.Key != "" {
+		v.set[f.Key] = n.Line
+	}
+
+	switch f.Kind {
+	case KindObject, KindMap:
+		if n.Kind != yaml.MappingNode {
+			v.add(v.source, n.Line, f.Key, LevelError, "expected an object, got %s", describe(n))
+			return
+		}
+
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, value := n.Content[i], n.Content[i+1]
+			name := strings.ToLower(k.Value)
+
+			if f.Kind == KindMap {
+				v.node(value, withKey(f.Elem, join(f.Key, name)))
+				continue
+			}
+
+			child := f.Child(name)
+			if child == nil {
+				key := join(f.Key, name)
+				if s := suggest(name, f); s != "" {
+					v.add(v.source, k.Line, key, LevelError, "unknown key, did you mean %s?", s)
+				} else {
+					v.add(v.source, k.Line, key, LevelError, "unknown key")
+				}
+				continue
+			}
+
+			v.node(value, child)
+		}
+	case KindArray:
+		switch n.Kind {
+		case yaml.SequenceNode:
+			for i, item := range n.Content {
+				v.node(item, withKey(f.Elem, join(f.Key, strconv.Itoa(i))))
+			}
+		case yaml.ScalarNode:
+			// mapstructure wraps a single value in a slice
+			v.scalar(v.source, n.Line, withKey(f.Elem, f.Key), n.Value)
+		default:
+			v.add(v.source, n.Line, f.Key, LevelError, "expected a list, got %s", describe(n))
+		}
+	case KindAny:
+	default:
+		if n.Kind != yaml.ScalarNode {
+			v.add(v.source, n.Line, f.Key, LevelError, "expected %s, got %s", f.Kind, describe(n))
+			return
+		}
+
+		v.scalar(v.source, n.Line, f, n.Value)
+	}
+}
+
+// scalar checks a value like the weakly typed decoding of viper does.
+func (v *validator) scalar(source string, line int, f *Field, value string) {
+	value = strings.TrimSpace(value)
+
+	// config.Load resolves the file:// and env: references and decrypts the enc: values, their
+	// value is only known then
+	if strings.HasPrefix(value, "file://") || strings.HasPrefix(value, "env:") || strings.HasPrefix(value, "enc:") {
+		return
+	}
+
+	var ok bool
+	switch f.Kind {
+	case KindInteger:
+		_, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			_, err = strconv.ParseFloat(value, 64)
+		}
+		ok = err == nil
+	case KindNumber:
+		_, err := strconv.ParseFloat(value, 64)
+		ok = err == nil
+	case KindBoolean:
+		_, err := strconv.ParseBool(value)
+		ok = err == nil
+	case KindDuration:
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil && value != "0" {
+			v.add(source, line, f.Key, LevelWarning, "%s is in nanoseconds, add a unit, e.g. %ss", value, value)
+			return
+		}
+		_, err := time.ParseDuration(value)
+		ok = err == nil || value == "0"
+	case KindObject, KindMap, KindArray:
+		v.add(source, line, f.Key, LevelError, "expected %s, got a value", f.Kind)
+		return
+	default:
+		ok = true
+	}
+
+	if !ok {
+		v.add(source, line, f.Key, LevelError, "expected %s, got %q", f.Kind, value)
+		return
+	}
+
+	for _, rule := range strings.Split(f.Validate, ",") {
+		if param, found := strings.CutPrefix(rule, "oneof="); found && !slices.Contains(strings.Fields(param), value) {
+			v.add(source, line, f.Key, LevelError, "must be one of %s, got %q", strings.Join(strings.Fields(param), ", "), value)
+		}
+	}
+
+	if pk, ok := pathKeys[f.Key]; ok && v.dir != "" && value != "" {
+		v.path(source, line, f.Key, value, pk)
+	}
+}
+
+// path checks that the path of a key can be read, relative to the directory of the service.
+func (v *validator) path(source string, line int, key, value string, pk pathKey) {
+	p := value
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(v.dir, p)
+	}
+
+	info, err := os.Stat(p)
+	switch {
+	case err != nil:
+		v.add(source, line, key, pk.level, "can not read %s: %v", value, errors.Unwrap(err))
+	case pk.dir && !info.IsDir():
+		v.add(source, line, key, pk.level, "%s is not a directory", value)
+	}
+}
+
+// env checks the environment variables of the top level keys, e.g. APP__PORT.
+func (v *validator) env(env []string) {
+	sort.Strings(env)
+
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		if !strings.Contains(name, "__") {
+			continue
+		}
+
+		key := strings.ToLower(strings.ReplaceAll(name, "__", "."))
+		top, _, _ := strings.Cut(key, ".")
+		if v.spec.Root.Child(top) == nil {
+			continue
+		}
+
+		f := v.spec.Lookup(key)
+		if f == nil {
+			if s := v.spec.mapKey(key); s != "" {
+				v.add(name, 0, key, LevelError, "unknown key, did you mean %s?", EnvName(s))
+				continue
+			}
+
+			parentKey, last := cutLast(key)
+			parent := v.spec.Root
+			if parentKey != "" {
+				parent = v.spec.Lookup(parentKey)
+			}
+
+			// the fields of the map values are keyed with *, the suggestion is keyed after key
+			if s := suggest(last, parent); s != "" {
+				_, field := cutLast(s)
+				v.add(name, 0, key, LevelError, "unknown key, did you mean %s?", EnvName(join(parentKey, field)))
+			} else {
+				v.add(name, 0, key, LevelError, "unknown key")
+			}
+			continue
+		}
+
+		f = withKey(f, key)
+		if f.Kind == KindObject || f.Kind == KindMap {
+			v.add(name, 0, key, LevelError, "is an object, set its keys instead")
+			continue
+		}
+
+		if f.Kind == KindArray {
+			// the comma separated values are split by mapstructure
+			for _, item := range strings.Split(value, ",") {
+				v.scalar(name, 0, withKey(f.Elem, key), item)
+			}
+		} else {
+			v.scalar(name, 0, f, value)
+		}
+
+		// config.Load binds the variable of every key, it sets the keys missing from the file and
+		// their parents
+		for k := key; k != ""; k, _ = cutLast(k) {
+			if _, ok := v.set[k]; !ok {
+				v.set[k] = 0
+			}
+		}
+	}
+}
+
+// mapKey returns the key of a map entry key is missing the map of, e.g.
+// feature_flags.flags.new_kyc_flow.enabled for feature_flags.new_kyc_flow.enabled.
+func (s *Spec) mapKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i := range parts {
+		parent := s.Root
+		if i > 0 {
+			parent = s.Lookup(strings.Join(parts[:i], "."))
+		}
+		if parent == nil || parent.Kind != KindObject {
+			return ""
+		}
+
+		for _, c := range parent.Fields {
+			if c.Kind != KindMap {
+				continue
+			}
+
+			candidate := join(c.Key, strings.Join(parts[i:], "."))
+			if s.Lookup(candidate) != nil {
+				return candidate
+			}
+		}
+	}
+
+	return ""
+}
+
+// cutLast cuts the last segment of key, e.g. database.host into database and host.
+func cutLast(key string) (string, string) {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+
+	return "", key
+}
+
+// required reports the required keys of f missing from the file, the environment and the
+// defaults. The validator checks the nested structs even when their key is missing.
+func (v *validator) required(f *Field) {
+	for _, c := range f.Fields {
+		_, set := v.set[c.Key]
+		if c.Required && !set && !v.spec.HasDefault(c.Key) {
+			v.add(v.source, v.set[f.Key], c.Key, LevelError, "missing required key, set it or %s", EnvName(c.Key))
+		}
+
+		if c.Kind == KindObject {
+			v.required(c)
+		}
+	}
+}
+
+// withKey returns a copy of f and its children with key, for the values of maps and arrays
+// whose keys are * in the spec.
+func withKey(f *Field, key string) *Field {
+	c := *f
+	c.Key = key
+
+	c.Fields = make([]*Field, len(f.Fields))
+	for i, child := range f.Fields {
+		c.Fields[i] = withKey(child, join(key, child.Name))
+	}
+
+	if f.Elem != nil {
+		c.Elem = withKey(f.Elem, join(key, "*"))
+	}
+
+	return &c
+}
+
+func describe(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return strconv.Quote(n.Value)
+	}
+}
+
+// suggest returns the key of the field of parent closest to name.
+func suggest(name string, parent *Field) string {
+	if parent == nil {
+		return ""
+	}
+
+	best, bestDistance := "", len(name)/3+2
+	for _, c := range parent.Fields {
+		if d := distance(name, c.Name); d < bestDistance {
+			best, bestDistance = c.Key, d
+		}
+	}
+
+	return best
+}
+
+// distance is the Levenshtein distance of a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// ReadEnvFile reads the NAME=value lines of a dotenv file.
+func ReadEnvFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read %s: %w", path, err)
+	}
+
+	var env []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, "'")
+		}
+
+		env = append(env, strings.TrimSpace(name)+"="+value)
+	}
+
+	return env, scanner.Err()
+}
@@ -0,0 +1,320 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	configFile        = "config.yaml"
+	configExampleFile = "config.yaml.example"
+)
+
+var configKeysCheck = Check{
+	Name:        "config-keys",
+	Description: "config.yaml has every key of config.yaml.example",
+	Run: func(dir string) Result {
+		example, err := readYAML(filepath.Join(dir, configExampleFile))
+		if err != nil {
+			return fail(err.Error(), "restore "+configExampleFile+" from the template")
+		}
+
+		config, err := readYAML(filepath.Join(dir, configFile))
+		if os.IsNotExist(err) {
+			return warn(configFile+" does not exist", "cp "+configExampleFile+" "+configFile)
+		}
+		if err != nil {
+			return fail(err.Error(), "fix the syntax of "+configFile)
+		}
+
+		have := map[string]bool{}
+		for _, k := range leafKeys(config, "") {
+			have[k] = true
+		}
+
+		var missing []string
+		for _, k := range leafKeys(example, "") {
+			if !have[k] && !coveredByParent(k, have) {
+				missing = append(missing, k)
+			}
+		}
+
+		if len(missing) > 0 {
+			return fail(
+				fmt.Sprintf("%d keys of %s are missing from %s", len(missing), configExampleFile, configFile),
+				"add the keys to "+configFile+" or run gog doctor --fix to copy them with their example values",
+				missing...,
+			)
+		}
+
+		return pass(configFile + " has every key of " + configExampleFile)
+	},
+	Fix: func(dir string) error {
+		examplePath, configPath := filepath.Join(dir, configExampleFile), filepath.Join(dir, configFile)
+
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			data, err := os.ReadFile(examplePath)
+			if err != nil {
+				return err
+			}
+
+			return os.WriteFile(configPath, data, 0644)
+		}
+
+		example, err := readYAML(examplePath)
+		if err != nil {
+			return err
+		}
+
+		config, err := readYAML(configPath)
+		if err != nil {
+			return err
+		}
+
+		mergeMissing(config.Content[0], example.Content[0])
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(config); err != nil {
+			return err
+		}
+
+		return os.WriteFile(configPath, buf.Bytes(), 0644)
+	},
+}
+
+var streamSubjectsCheck = Check{
+	Name:        "stream-subjects",
+	Description: "nats.default_stream_subjects covers the subjects of the const.go files",
+	Run: func(dir string) Result {
+		subjects, err := subjectConstants(dir)
+		if err != nil {
+			return fail(err.Error(), "fix the syntax of the const.go files")
+		}
+
+		if len(subjects) == 0 {
+			return pass("no subject constants in const.go files")
+		}
+
+		var details []string
+		checked := 0
+		for _, file := range []string{configFile, configExampleFile} {
+			doc, err := readYAML(filepath.Join(dir, file))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fail(err.Error(), "fix the syntax of "+file)
+			}
+			checked++
+
+			var cfg struct {
+				Nats struct {
+					DefaultStreamSubjects []string `yaml:"default_stream_subjects"`
+				} `yaml:"nats"`
+			}
+			if err := doc.Decode(&cfg); err != nil {
+				return fail(fmt.Sprintf("❌ Failed to read %s: %v", file, err), "fix nats.default_stream_subjects in "+file)
+			}
+
+			for _, s := range subjects {
+				if !coversSubject(cfg.Nats.DefaultStreamSubjects, s.value) {
+					details = append(details, fmt.Sprintf("%s: %s = %q (%s)", file, s.name, s.value, s.position))
+				}
+			}
+		}
+
+		if checked == 0 {
+			return warn("no config file to read nats.default_stream_subjects from", "cp "+configExampleFile+" "+configFile)
+		}
+
+		if len(details) > 0 {
+			return fail(
+				fmt.Sprintf("%d subjects are not in the default stream", len(details)),
+				"add the subjects, or a wildcard like <prefix>.>, to nats.default_stream_subjects",
+				details...,
+			)
+		}
+
+		return pass(fmt.Sprintf("the %d subject constants are in the default stream", len(subjects)))
+	},
+}
+
+type subjectConstant struct {
+	name     string
+	value    string
+	position string
+}
+
+// subjectConstants returns the string constants named Subject* declared in the const.go
+// files of the project.
+func subjectConstants(dir string) ([]subjectConstant, error) {
+	var subjects []subjectConstant
+
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(filepath.Join(dir, "internal"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		if d.IsDir() || d.Name() != "const.go" {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if !strings.HasPrefix(name.Name, "Subject") || i >= len(vs.Values) {
+						continue
+					}
+
+					lit, ok := vs.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+
+					value, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						continue
+					}
+
+					pos := fset.Position(name.Pos())
+					rel, _ := filepath.Rel(dir, pos.Filename)
+					subjects = append(subjects, subjectConstant{
+						name:     file.Name.Name + "." + name.Name,
+						value:    value,
+						position: fmt.Sprintf("%s:%d", filepath.ToSlash(rel), pos.Line),
+					})
+				}
+			}
+		}
+
+		return nil
+	})
+
+	return subjects, err
+}
+
+// coversSubject reports whether one of the stream subjects matches a subject, with the
+// NATS wildcards: * matches a token and > the remaining tokens.
+func coversSubject(patterns []string, subject string) bool {
+	tokens := strings.Split(subject, ".")
+
+	for _, p := range patterns {
+		pt := strings.Split(p, ".")
+		for i, t := range pt {
+			if t == ">" && i < len(tokens) {
+				return true
+			}
+
+			if i >= len(tokens) || (t != "*" && t != tokens[i]) {
+				break
+			}
+
+			if i == len(pt)-1 && len(pt) == len(tokens) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func readYAML(file string) (*yaml.Node, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", filepath.Base(file), err)
+	}
+
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	return doc, nil
+}
+
+// leafKeys returns the dotted paths of the non mapping values of a document.
+func leafKeys(n *yaml.Node, prefix string) []string {
+	if n.Kind == yaml.DocumentNode {
+		return leafKeys(n.Content[0], prefix)
+	}
+
+	var keys []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := prefix+n.Content[i].Value, n.Content[i+1]
+
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			keys = append(keys, leafKeys(value, key+".")...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// coveredByParent reports whether a parent of the key is set to a non mapping value, e.g.
+// an empty map instead of the example entries.
+func coveredByParent(key string, have map[string]bool) bool {
+	for i := strings.LastIndexByte(key, '.'); i > 0; i = strings.LastIndexByte(key[:i], '.') {
+		if have[key[:i]] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeMissing adds the entries of the src mapping that dst does not have.
+func mergeMissing(dst, src *yaml.Node) {
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == src.Content[i].Value {
+				mergeMissing(dst.Content[j+1], src.Content[i+1])
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package doctor checks a project for drift from the template conventions.
+package doctor
+
+type (
+	Status string
+
+	// Result is the outcome of a check on a project.
+	Result struct {
+		Check   string   `json:"check"`
+		Status  Status   `json:"status"`
+		Message string   `json:"message"`
+		Details []string `json:"details,omitempty"`
+		// Hint tells how to fix a warning or a failure
+		Hint string `json:"hint,omitempty"`
+		// Fixed is set when --fix repaired the issue, Status is then the status after the fix
+		Fixed bool `json:"fixed,omitempty"`
+	}
+
+	// Check is an offline check of a project directory.
+	Check struct {
+		Name        string
+		Description string
+		Run         func(dir string) Result
+		// Fix repairs the issues reported by Run when they are safe to repair automatically,
+		// it is nil for checks that need a human.
+		Fix func(dir string) error
+	}
+
+	Summary struct {
+		Pass int `json:"pass"`
+		Warn int `json:"warn"`
+		Fail int `json:"fail"`
+	}
+
+	Report struct {
+		Dir     string   `json:"dir"`
+		Results []Result `json:"results"`
+		Summary Summary  `json:"summary"`
+	}
+)
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Checks are the checks run by Run in order.
+var Checks = []Check{
+	configKeysCheck,
+	streamSubjectsCheck,
+	migrationsCheck,
+	registryAssertionCheck,
+	docsCheck,
+	preCommitHookCheck,
+}
+
+// Run runs every check on the project in dir. With fix, the fixable issues are repaired and
+// checked again.
+func Run(dir string, fix bool) *Report {
+	report := &Report{Dir: dir}
+
+	for _, c := range Checks {
+		r := c.Run(dir)
+		r.Check = c.Name
+
+		if fix && c.Fix != nil && r.Status != StatusPass {
+			if err := c.Fix(dir); err != nil {
+				r.Details = append(r.Details, "fix failed: "+err.Error())
+			} else {
+				r = c.Run(dir)
+				r.Check = c.Name
+				r.Fixed = true
+			}
+		}
+
+		switch r.Status {
+		case StatusPass:
+			report.Summary.Pass++
+		case StatusWarn:
+			report.Summary.Warn++
+		case StatusFail:
+			report.Summary.Fail++
+		}
+
+		report.Results = append(report.Results, r)
+	}
+
+	return report
+}
+
+func pass(message string) Result {
+	return Result{Status: StatusPass, Message: message}
+}
+
+func warn(message, hint string, details ...string) Result {
+	return Result{Status: StatusWarn, Message: message, Hint: hint, Details: details}
+}
+
+func fail(message, hint string, details ...string) Result {
+	return Result{Status: StatusFail, Message: message, Hint: hint, Details: details}
+}
@@ -0,0 +1,34 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nayla-finance/gog/internal/migrations"
+)
+
+var migrationsCheck = Check{
+	Name:        "migrations",
+	Description: "the goose migrations have unique versions and balanced annotations",
+	Run: func(dir string) Result {
+		migrationsDir := migrations.Dir(dir)
+
+		count, problems, err := migrations.Lint(dir)
+		if os.IsNotExist(err) {
+			return warn(migrationsDir+" does not exist", "create it with just migrate-new <name>")
+		}
+		if err != nil {
+			return fail(err.Error(), "")
+		}
+
+		if len(problems) > 0 {
+			return fail(
+				fmt.Sprintf("%d problems in %s", len(problems), migrationsDir),
+				"fix the files, each needs a -- +goose Up section and StatementBegin/StatementEnd pairs",
+				problems...,
+			)
+		}
+
+		return pass(fmt.Sprintf("the %d migrations parse", count))
+	},
+}
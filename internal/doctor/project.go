@@ -0,0 +1,172 @@
+package doctor
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/hooks"
+	"github.com/nayla-finance/gog/internal/swagger"
+)
+
+const (
+	registryDir          = "internal/registry"
+	registryProviderFile = "registry_provider.go"
+	registryAssertion    = "var _ RegistryProvider = new(Registry)"
+)
+
+var registryAssertionCheck = Check{
+	Name:        "registry-assertion",
+	Description: "the registry asserts that it implements RegistryProvider",
+	Run: func(dir string) Result {
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, filepath.Join(dir, registryDir), nil, parser.SkipObjectResolution)
+		if os.IsNotExist(err) {
+			return warn(registryDir+" does not exist", "")
+		}
+		if err != nil {
+			return fail(err.Error(), "fix the syntax of the registry")
+		}
+
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				if assertsProvider(file) {
+					return pass("Registry is asserted to implement RegistryProvider")
+				}
+			}
+		}
+
+		return fail(
+			"the RegistryProvider assertion was removed, a provider method missing from Registry only breaks where it is used",
+			"add `"+registryAssertion+"` to "+registryDir+", or run gog doctor --fix",
+		)
+	},
+	Fix: func(dir string) error {
+		file := filepath.Join(dir, registryDir, registryProviderFile)
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		out, err := format.Source([]byte(strings.TrimRight(string(src), "\n") + "\n\n" + registryAssertion + "\n"))
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(file, out, 0644)
+	},
+}
+
+// assertsProvider reports whether a file declares var _ RegistryProvider = new(Registry),
+// (*Registry)(nil) or &Registry{}.
+func assertsProvider(file *ast.File) bool {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if id, ok := vs.Type.(*ast.Ident); !ok || id.Name != "RegistryProvider" || len(vs.Values) != 1 {
+				continue
+			}
+
+			if isRegistryValue(vs.Values[0]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isRegistryValue(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		if fn, ok := e.Fun.(*ast.Ident); ok && fn.Name == "new" && len(e.Args) == 1 {
+			id, ok := e.Args[0].(*ast.Ident)
+			return ok && id.Name == "Registry"
+		}
+
+		if paren, ok := e.Fun.(*ast.ParenExpr); ok {
+			star, ok := paren.X.(*ast.StarExpr)
+			if !ok {
+				return false
+			}
+			id, ok := star.X.(*ast.Ident)
+			return ok && id.Name == "Registry"
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok && e.Op == token.AND {
+			id, ok := lit.Type.(*ast.Ident)
+			return ok && id.Name == "Registry"
+		}
+	}
+
+	return false
+}
+
+var docsCheck = Check{
+	Name:        "docs",
+	Description: "docs/ matches the swag annotations",
+	Run: func(dir string) Result {
+		if _, err := os.Stat(filepath.Join(dir, swagger.MainFile)); os.IsNotExist(err) {
+			// worker and job presets have no HTTP API
+			return pass("no " + swagger.MainFile + ", the project has no HTTP API to document")
+		}
+
+		stale, err := swagger.Stale(dir)
+		if err != nil {
+			return warn(err.Error(), "run go mod download, swag parses the dependencies with go list")
+		}
+
+		if len(stale) > 0 {
+			return warn("docs/ is out of date", "run just swagger, or gog doctor --fix", stale...)
+		}
+
+		return pass("docs/ is up to date")
+	},
+	Fix: func(dir string) error {
+		return swagger.Generate(dir, filepath.Join(dir, swagger.DocsDir))
+	},
+}
+
+var preCommitHookCheck = Check{
+	Name:        "pre-commit-hook",
+	Description: "the git pre-commit hook of " + hooks.File + " is installed",
+	Run: func(dir string) Result {
+		hook, err := hooks.HookPath(dir, "pre-commit")
+		if err != nil {
+			return warn("not a git repository", "run git init")
+		}
+
+		info, err := os.Stat(hook)
+		if os.IsNotExist(err) {
+			return warn("the pre-commit hook is missing", "run gog hooks install, or gog doctor --fix")
+		}
+		if err != nil {
+			return fail(err.Error(), "")
+		}
+
+		if !hooks.Managed(hook) {
+			return warn("the pre-commit hook is not managed by gog, its steps are not versioned with the project", "run gog hooks install --force, or gog doctor --fix")
+		}
+
+		if info.Mode()&0111 == 0 {
+			return warn("the pre-commit hook is not executable, git skips it", "chmod +x "+hook)
+		}
+
+		return pass("the pre-commit hook is installed")
+	},
+	Fix: func(dir string) error {
+		// the replaced hook is kept with a .bak suffix
+		_, err := hooks.Install(dir, hooks.ProjectDefaults, true)
+		return err
+	},
+}
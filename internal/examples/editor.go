@@ -0,0 +1,239 @@
+package examples
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// editor deletes whole lines of nodes from the source of a file.
+type editor struct {
+	fset   *token.FileSet
+	file   *ast.File
+	src    []byte
+	words  *regexp.Regexp
+	ranges [][2]int
+}
+
+func (e *editor) offset(p token.Pos) int {
+	return e.fset.Position(p).Offset
+}
+
+func (e *editor) line(p token.Pos) int {
+	return e.fset.Position(p).Line
+}
+
+// removeDecl removes a top level declaration with its doc comment.
+func (e *editor) removeDecl(n ast.Node, doc *ast.CommentGroup) {
+	e.removeNode(n, doc, true)
+}
+
+// removeNode removes a node, with its doc comment when ownsDoc is set. Otherwise only the
+// comment lines right above it naming a removed domain are removed, e.g. "// user routes",
+// while a section comment like "// domains" is kept.
+func (e *editor) removeNode(n ast.Node, doc *ast.CommentGroup, ownsDoc bool) {
+	start := n.Pos()
+
+	switch {
+	case doc != nil && ownsDoc:
+		start = doc.Pos()
+	default:
+		group := doc
+		if group == nil {
+			group = e.commentAbove(n.Pos())
+		}
+
+		if group != nil {
+			expected := e.line(n.Pos()) - 1
+			for i := len(group.List) - 1; i >= 0; i-- {
+				c := group.List[i]
+				if e.line(c.End()) != expected || !e.words.MatchString(c.Text) {
+					break
+				}
+
+				start = c.Pos()
+				expected = e.line(c.Pos()) - 1
+			}
+		}
+	}
+
+	from, to := e.offset(start), e.offset(n.End())
+
+	// delete whole lines when the node is alone on them
+	lineFrom := strings.LastIndexByte(string(e.src[:from]), '\n') + 1
+	lineTo := to
+	if i := strings.IndexByte(string(e.src[to:]), '\n'); i >= 0 {
+		lineTo = to + i + 1
+	} else {
+		lineTo = len(e.src)
+	}
+
+	before := strings.TrimSpace(string(e.src[lineFrom:from]))
+	after := strings.TrimSpace(string(e.src[to:lineTo]))
+	if before == "" && (after == "" || strings.HasPrefix(after, "//")) {
+		from, to = lineFrom, lineTo
+	}
+
+	e.ranges = append(e.ranges, [2]int{from, to})
+}
+
+// commentAbove returns the comment group ending on the line above pos.
+func (e *editor) commentAbove(pos token.Pos) *ast.CommentGroup {
+	line := e.line(pos)
+	for _, cg := range e.file.Comments {
+		if e.line(cg.End()) == line-1 && cg.End() < pos {
+			return cg
+		}
+	}
+
+	return nil
+}
+
+// removeMembers removes the fields or methods of a struct or interface type referencing a
+// removed domain, it reports whether the type is a struct or an interface.
+func (e *editor) removeMembers(expr ast.Expr, refers func(ast.Node) bool) bool {
+	var fields *ast.FieldList
+	switch t := expr.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+	default:
+		return false
+	}
+
+	for _, f := range fields.List {
+		if refers(f) {
+			e.removeNode(f, f.Doc, false)
+		}
+	}
+
+	return true
+}
+
+// removeStmts removes the statements referencing a removed domain, compound statements
+// only referencing it in their bodies are kept.
+func (e *editor) removeStmts(list []ast.Stmt, refers func(ast.Node) bool) {
+	for _, s := range list {
+		if !refers(s) {
+			continue
+		}
+
+		switch s := s.(type) {
+		case *ast.BlockStmt:
+			e.removeStmts(s.List, refers)
+			continue
+		case *ast.IfStmt:
+			if !refersAny(refers, s.Init, s.Cond) {
+				e.removeStmts(s.Body.List, refers)
+				if s.Else != nil {
+					e.removeStmts([]ast.Stmt{s.Else}, refers)
+				}
+				continue
+			}
+		case *ast.ForStmt:
+			if !refersAny(refers, s.Init, s.Cond, s.Post) {
+				e.removeStmts(s.Body.List, refers)
+				continue
+			}
+		case *ast.RangeStmt:
+			if !refersAny(refers, s.Key, s.Value, s.X) {
+				e.removeStmts(s.Body.List, refers)
+				continue
+			}
+		case *ast.SwitchStmt:
+			if !refersAny(refers, s.Init, s.Tag) {
+				e.removeClauses(s.Body, refers)
+				continue
+			}
+		case *ast.TypeSwitchStmt:
+			if !refersAny(refers, s.Init, s.Assign) {
+				e.removeClauses(s.Body, refers)
+				continue
+			}
+		case *ast.SelectStmt:
+			e.removeClauses(s.Body, refers)
+			continue
+		}
+
+		e.removeNode(s, nil, false)
+	}
+}
+
+func (e *editor) removeClauses(body *ast.BlockStmt, refers func(ast.Node) bool) {
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			var header []ast.Node
+			for _, x := range c.List {
+				header = append(header, x)
+			}
+
+			if refersAny(refers, header...) {
+				e.removeNode(c, nil, false)
+				continue
+			}
+			e.removeStmts(c.Body, refers)
+		case *ast.CommClause:
+			if c.Comm != nil && refers(c.Comm) {
+				e.removeNode(c, nil, false)
+				continue
+			}
+			e.removeStmts(c.Body, refers)
+		}
+	}
+}
+
+func refersAny(refers func(ast.Node) bool, nodes ...ast.Node) bool {
+	for _, n := range nodes {
+		if n != nil && refers(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apply returns the source without the removed ranges, the blank lines left behind are
+// collapsed.
+func (e *editor) apply() []byte {
+	sort.Slice(e.ranges, func(i, j int) bool { return e.ranges[i][0] < e.ranges[j][0] })
+
+	var b strings.Builder
+	last := 0
+	for _, r := range e.ranges {
+		if r[0] < last {
+			r[0] = last
+		}
+		if r[1] <= last {
+			continue
+		}
+
+		b.WriteString(string(e.src[last:r[0]]))
+		last = r[1]
+	}
+	b.WriteString(string(e.src[last:]))
+
+	lines := strings.Split(b.String(), "\n")
+	out := make([]string, 0, len(lines))
+	for i, l := range lines {
+		blank := strings.TrimSpace(l) == ""
+		if blank && len(out) > 0 {
+			prev := strings.TrimSpace(out[len(out)-1])
+			next := ""
+			if i+1 < len(lines) {
+				next = strings.TrimSpace(lines[i+1])
+			}
+
+			if prev == "" || strings.HasSuffix(prev, "{") || strings.HasSuffix(prev, "(") || next == "}" || next == ")" {
+				continue
+			}
+		}
+
+		out = append(out, l)
+	}
+
+	return []byte(strings.Join(out, "\n"))
+}
@@ -0,0 +1,351 @@
+// Package examples removes the example domains of the template from a project, with their
+// references in the remaining code.
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Domains are the example domains of the template.
+var Domains = []string{"user", "post", "userposts", "tracker"}
+
+type (
+	Options struct {
+		// Module is the module path of the project, read from go.mod when empty
+		Module string
+		// DryRun reports the changes without writing them
+		DryRun bool
+	}
+
+	Result struct {
+		// Removed are the project relative files and directories deleted
+		Removed []string
+		// Edited are the project relative files whose references were removed
+		Edited []string
+	}
+
+	// removal is what references are removed from the remaining files.
+	removal struct {
+		// packages are the import paths of the removed domains
+		packages map[string]bool
+		// names are the declarations of the removed files of a kept package, by import path
+		names map[string]map[string]bool
+		// words are the domain names, comments naming one are removed with their node
+		words *regexp.Regexp
+	}
+)
+
+// Remove deletes the example domains of the project in dir, their interfaces, models and
+// registry files, then removes every reference to them from the remaining Go files. The
+// swagger docs are not regenerated.
+func Remove(dir string, opts Options) (*Result, error) {
+	module := opts.Module
+	if module == "" {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read go.mod: %w", err)
+		}
+
+		if module = modfile.ModulePath(data); module == "" {
+			return nil, fmt.Errorf("❌ No module path in %s", filepath.Join(dir, "go.mod"))
+		}
+	}
+
+	rm := &removal{
+		packages: map[string]bool{},
+		names:    map[string]map[string]bool{},
+		words:    regexp.MustCompile(`(?i)\b(` + strings.Join(Domains, "|") + `)s?\b`),
+	}
+
+	result := &Result{}
+	removed := map[string]bool{}
+
+	for _, d := range Domains {
+		domainDir := path.Join("internal/domains", d)
+		if exists(dir, domainDir) {
+			rm.packages[module+"/"+domainDir] = true
+			result.Removed = append(result.Removed, domainDir)
+			removed[domainDir] = true
+		}
+
+		for _, kept := range []string{"internal/domains/interfaces", "internal/domains/model"} {
+			file := path.Join(kept, d+".go")
+			if !exists(dir, file) {
+				continue
+			}
+
+			names, err := declaredNames(filepath.Join(dir, file))
+			if err != nil {
+				return nil, err
+			}
+
+			pkg := module + "/" + kept
+			if rm.names[pkg] == nil {
+				rm.names[pkg] = map[string]bool{}
+			}
+			for n := range names {
+				rm.names[pkg][n] = true
+			}
+
+			result.Removed = append(result.Removed, file)
+			removed[file] = true
+		}
+
+		if file := path.Join("internal/registry", "registry_"+d+".go"); exists(dir, file) {
+			result.Removed = append(result.Removed, file)
+			removed[file] = true
+		}
+	}
+
+	if len(result.Removed) == 0 {
+		return result, nil
+	}
+
+	edits := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if removed[rel] || rel == "vendor" || (rel != "." && strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if removed[rel] || !strings.HasSuffix(rel, ".go") {
+			return nil
+		}
+
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		out, changed, err := rm.edit(rel, src)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to remove the example references from %s: %w", rel, err)
+		}
+
+		if changed {
+			edits[p] = out
+			result.Edited = append(result.Edited, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(result.Removed)
+	sort.Strings(result.Edited)
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, rel := range result.Removed {
+		if err := os.RemoveAll(filepath.Join(dir, rel)); err != nil {
+			return nil, err
+		}
+	}
+
+	for file, out := range edits {
+		if err := os.WriteFile(file, out, 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func exists(dir, rel string) bool {
+	_, err := os.Stat(filepath.Join(dir, rel))
+	return err == nil
+}
+
+// declaredNames returns the top level declarations of a file.
+func declaredNames(file string) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names[d.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						names[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// edit removes the nodes of a file referencing the removed domains.
+func (rm *removal) edit(rel string, src []byte) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, rel, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// local names of the imports of removed packages, and of the kept packages with removed names
+	removedImports := map[string]bool{}
+	keptImports := map[string]map[string]bool{}
+	for _, imp := range file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		if rm.packages[p] {
+			removedImports[name] = true
+		} else if names := rm.names[p]; names != nil {
+			keptImports[name] = names
+		}
+	}
+
+	if len(removedImports) == 0 && len(keptImports) == 0 {
+		return src, false, nil
+	}
+
+	refers := func(n ast.Node) bool {
+		found := false
+		ast.Inspect(n, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || found {
+				return !found
+			}
+
+			if id, ok := sel.X.(*ast.Ident); ok {
+				found = removedImports[id.Name] || keptImports[id.Name][sel.Sel.Name]
+			}
+
+			return !found
+		})
+
+		return found
+	}
+
+	e := &editor{fset: fset, file: file, src: src, words: rm.words}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if (d.Recv != nil && refers(d.Recv)) || refers(d.Type) {
+				e.removeDecl(d, d.Doc)
+				continue
+			}
+
+			if d.Body != nil {
+				e.removeStmts(d.Body.List, refers)
+			}
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+
+			var kept int
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if e.removeMembers(s.Type, refers) {
+						kept++
+						continue
+					}
+
+					if refers(s) {
+						e.removeNode(s, s.Doc, true)
+						continue
+					}
+				case *ast.ValueSpec:
+					if refers(s) {
+						e.removeNode(s, s.Doc, true)
+						continue
+					}
+				}
+				kept++
+			}
+
+			if kept == 0 {
+				e.removeDecl(d, d.Doc)
+			}
+		}
+	}
+
+	if len(e.ranges) == 0 {
+		return src, false, nil
+	}
+
+	out := e.apply()
+
+	// drop the imports left unused, the removed packages and the kept ones no longer needed
+	fset = token.NewFileSet()
+	file, err = parser.ParseFile(fset, rel, out, parser.ParseComments)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// DeleteNamedImport edits file.Imports
+	imports := append([]*ast.ImportSpec{}, file.Imports...)
+	for _, imp := range imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if !rm.packages[p] && rm.names[p] == nil {
+			continue
+		}
+
+		if !astutil.UsesImport(file, p) {
+			name := ""
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			astutil.DeleteNamedImport(fset, file, name, p)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, false, err
+	}
+
+	return buf.Bytes(), true, nil
+}
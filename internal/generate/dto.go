@@ -0,0 +1,492 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	dtoGeneratedLine = "// Code generated by gog generate dto from %s. DO NOT EDIT.\n\n"
+	importValidator  = "github.com/nayla-finance/go-nayla/validator"
+)
+
+// readonlyFields are set by the service, they are readonly without a gog tag.
+var readonlyFields = []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"}
+
+type (
+	DTOOptions struct {
+		// Dir is the project directory
+		Dir string
+		// Model is the model to generate the DTOs of, e.g. model.User or User
+		Model string
+		// Create generates Create<Model>DTO and its To<Model> mapper
+		Create bool
+		// Update generates Update<Model>DTO and its ApplyUpdate mapper
+		Update bool
+		// Response generates <Model>Response and New<Model>Response
+		Response bool
+	}
+
+	dtoField struct {
+		name string
+		typ  string
+		// pointer is set when the model field is a pointer
+		pointer  bool
+		json     string
+		validate string
+		// the gog tag options
+		skip, readonly, createOnly, writeonly, optional bool
+	}
+
+	dtoModel struct {
+		name    string
+		pkg     string
+		dir     string
+		fields  []dtoField
+		imports map[string]string
+	}
+)
+
+// DTO generates the request and response types of a model in <model>_dto_gen.go, next to the
+// model:
+//
+//   - Create<Model>DTO with the writable fields, required unless they are pointers, and
+//     To<Model> returning a new model
+//   - Update<Model>DTO with pointers to the fields that can be updated, and ApplyUpdate setting
+//     the fields of a model that are set in the DTO
+//   - <Model>Response with the fields that are not writeonly, and New<Model>Response
+//
+// The validate tags of the model fields are added to the DTO fields. The gog tag of a field
+// picks the DTOs it is in: gog:"-" skips it, gog:"readonly" only puts it in the response, as
+// for ID, CreatedAt, UpdatedAt and DeletedAt, gog:"create-only" leaves it out of the update DTO,
+// gog:"writeonly" leaves it out of the response and gog:"optional" does not require it on
+// create. The fields holding other models are relations, they are skipped.
+func DTO(opts DTOOptions) (*Result, error) {
+	if !opts.Create && !opts.Update && !opts.Response {
+		opts.Create, opts.Update, opts.Response = true, true, true
+	}
+
+	m, err := findModel(opts.Dir, opts.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{dir: opts.Dir, result: &Result{}}
+
+	generated := filepath.Join(m.dir, snake(m.name)+"_dto_gen.go")
+	var names []string
+	if opts.Create {
+		names = append(names, "Create"+m.name+"DTO")
+	}
+	if opts.Update {
+		names = append(names, "Update"+m.name+"DTO")
+	}
+	if opts.Response {
+		names = append(names, m.name+"Response", "New"+m.name+"Response")
+	}
+
+	rel, name, err := findDeclaration(opts.Dir, m.dir, generated, names)
+	if err != nil {
+		return nil, err
+	}
+	if rel != "" {
+		return nil, fmt.Errorf("❌ %s already declares %s which is generated in %s, remove it to generate it", rel, name, generated)
+	}
+
+	src := m.render(opts)
+	if err := g.writeGenerated(generated, src); err != nil {
+		return nil, err
+	}
+
+	return g.result, nil
+}
+
+// findModel finds the struct named by model, <package>.<Type> or <Type>, in the packages of
+// the project.
+func findModel(dir, model string) (*dtoModel, error) {
+	pkgName, typeName, ok := strings.Cut(model, ".")
+	if !ok {
+		pkgName, typeName = "", model
+	}
+
+	var found []*dtoModel
+	err := filepath.WalkDir(filepath.Join(dir, "internal"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		m, err := parseModel(dir, rel, pkgName, typeName)
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			found = append(found, m)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read the packages: %w", err)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("❌ No struct %s in the packages of internal/", model)
+	case 1:
+		return found[0], nil
+	}
+
+	var dirs []string
+	for _, m := range found {
+		dirs = append(dirs, m.dir)
+	}
+
+	return nil, fmt.Errorf("❌ %s is declared in %s, name its package, e.g. %s.%s", model, strings.Join(dirs, ", "), found[0].pkg, typeName)
+}
+
+// parseModel returns the struct typeName of the package in rel, nil when the package does not
+// declare it or is not named pkgName.
+func parseModel(dir, rel, pkgName, typeName string) (*dtoModel, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, rel))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := &modelPackage{types: map[string]*ast.TypeSpec{}}
+	var model *dtoModel
+	var modelFile *ast.File
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, rel, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to parse %s: %w", filepath.Join(rel, name), err)
+		}
+
+		if pkgName != "" && file.Name.Name != pkgName {
+			return nil, nil
+		}
+
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+
+				pkg.types[ts.Name.Name] = ts
+				if _, isStruct := ts.Type.(*ast.StructType); isStruct && ts.Name.Name == typeName {
+					model = &dtoModel{name: typeName, pkg: file.Name.Name, dir: rel}
+					modelFile = file
+				}
+			}
+		}
+	}
+
+	if model == nil {
+		return nil, nil
+	}
+
+	model.imports = map[string]string{}
+	for _, imp := range modelFile.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		model.imports[name] = path
+	}
+
+	model.fields = pkg.dtoFields(pkg.types[typeName].Type.(*ast.StructType), map[string]bool{typeName: true})
+
+	return model, nil
+}
+
+// dtoFields returns the fields of a model, the embedded structs are flattened.
+func (p *modelPackage) dtoFields(st *ast.StructType, seen map[string]bool) []dtoField {
+	var fields []dtoField
+	for _, field := range st.Fields.List {
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			if s, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(s)
+			}
+		}
+
+		if len(field.Names) == 0 {
+			if id, ok := field.Type.(*ast.Ident); ok && !seen[id.Name] {
+				if ts, ok := p.types[id.Name]; ok {
+					if embedded, ok := ts.Type.(*ast.StructType); ok {
+						seen[id.Name] = true
+						fields = append(fields, p.dtoFields(embedded, seen)...)
+					}
+				}
+			}
+			continue
+		}
+
+		// relations are loaded by the repositories, they are not part of the DTOs
+		if p.isRelation(field.Type) {
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+
+			f := dtoField{
+				name:     name.Name,
+				typ:      exprString(field.Type),
+				json:     tag.Get("json"),
+				validate: tag.Get("validate"),
+				readonly: slices.Contains(readonlyFields, name.Name),
+			}
+			f.pointer = strings.HasPrefix(f.typ, "*")
+
+			for _, option := range strings.Split(tag.Get("gog"), ",") {
+				switch strings.TrimSpace(option) {
+				case "-":
+					f.skip = true
+				case "readonly":
+					f.readonly = true
+				case "create-only":
+					f.createOnly = true
+				case "writeonly":
+					f.writeonly = true
+				case "optional":
+					f.optional = true
+				}
+			}
+
+			if !f.skip {
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	return fields
+}
+
+// isRelation reports whether a field type holds structs of the package, e.g. Author User or
+// Posts []Post.
+func (p *modelPackage) isRelation(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return p.isRelation(expr.X)
+	case *ast.ArrayType:
+		return p.isRelation(expr.Elt)
+	case *ast.Ident:
+		ts, ok := p.types[expr.Name]
+		if !ok {
+			return false
+		}
+		_, isStruct := ts.Type.(*ast.StructType)
+		return isStruct
+	}
+
+	return false
+}
+
+func (m *dtoModel) render(opts DTOOptions) []byte {
+	var b strings.Builder
+	used := map[string]bool{}
+	useType := func(typ string) {
+		if pkg, _, ok := strings.Cut(strings.TrimLeft(typ, "*[]"), "."); ok {
+			if path, ok := m.imports[pkg]; ok {
+				used[path] = true
+			}
+		}
+	}
+
+	var create, update, response []dtoField
+	for _, f := range m.fields {
+		if !f.readonly {
+			create = append(create, f)
+			if !f.createOnly {
+				update = append(update, f)
+			}
+		}
+
+		if !f.writeonly {
+			response = append(response, f)
+		}
+	}
+
+	if opts.Create {
+		for _, f := range create {
+			useType(f.typ)
+		}
+		used[importValidator] = true
+		m.renderCreate(&b, create)
+	}
+
+	if opts.Update {
+		for _, f := range update {
+			useType(f.typ)
+		}
+		used[importValidator] = true
+		m.renderUpdate(&b, update)
+	}
+
+	if opts.Response {
+		for _, f := range response {
+			useType(f.typ)
+		}
+		m.renderResponse(&b, response)
+	}
+
+	header := fmt.Sprintf(dtoGeneratedLine, m.pkg+"."+m.name) + "package " + m.pkg + "\n\n"
+	if len(used) > 0 {
+		header += importBlock(used) + "\n"
+	}
+
+	return []byte(header + b.String())
+}
+
+func (m *dtoModel) renderCreate(b *strings.Builder, fields []dtoField) {
+	name := "Create" + m.name + "DTO"
+
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, f := range fields {
+		rules := f.validate
+		if !f.pointer && !f.optional {
+			rules = joinRules("required", rules)
+		} else if rules != "" {
+			rules = joinRules("omitempty", rules)
+		}
+		fmt.Fprintf(b, "\t%s %s%s\n", f.name, f.typ, fieldTag(f.json, rules))
+	}
+	b.WriteString("}\n\n")
+
+	writeValidate(b, name)
+
+	fmt.Fprintf(b, "// To%[1]s returns a new %[1]s with the fields of the DTO, the readonly fields are left empty.\n", m.name)
+	fmt.Fprintf(b, "func (dto *%s) To%s() *%s {\n\treturn &%s{\n", name, m.name, m.name, m.name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t\t%s: dto.%s,\n", f.name, f.name)
+	}
+	b.WriteString("\t}\n}\n\n")
+}
+
+func (m *dtoModel) renderUpdate(b *strings.Builder, fields []dtoField) {
+	name := "Update" + m.name + "DTO"
+
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, f := range fields {
+		rules := ""
+		if f.validate != "" {
+			rules = joinRules("omitempty", f.validate)
+		}
+		fmt.Fprintf(b, "\t%s %s%s\n", f.name, pointerType(f.typ), fieldTag(f.json, rules))
+	}
+	b.WriteString("}\n\n")
+
+	writeValidate(b, name)
+
+	fmt.Fprintf(b, "// ApplyUpdate sets the fields of %s that are set in the DTO.\n", strings.ToLower(m.name[:1]))
+	fmt.Fprintf(b, "func (dto *%s) ApplyUpdate(%s *%s) {\n", name, strings.ToLower(m.name[:1]), m.name)
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		value := "dto." + f.name
+		if pointerType(f.typ) != f.typ {
+			value = "*" + value
+		}
+		fmt.Fprintf(b, "\tif dto.%s != nil {\n\t\t%s.%s = %s\n\t}\n", f.name, strings.ToLower(m.name[:1]), f.name, value)
+	}
+	b.WriteString("}\n\n")
+}
+
+func (m *dtoModel) renderResponse(b *strings.Builder, fields []dtoField) {
+	name := m.name + "Response"
+
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t%s %s%s\n", f.name, f.typ, fieldTag(f.json, ""))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "// New%[1]s returns the response of %[2]s, without its writeonly fields.\n", name, strings.ToLower(m.name[:1]))
+	fmt.Fprintf(b, "func New%s(%s *%s) *%s {\n\treturn &%s{\n", name, strings.ToLower(m.name[:1]), m.name, name, name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t\t%s: %s.%s,\n", f.name, strings.ToLower(m.name[:1]), f.name)
+	}
+	b.WriteString("\t}\n}\n")
+}
+
+func writeValidate(b *strings.Builder, name string) {
+	fmt.Fprintf(b, "func (dto *%s) Validate() error {\n\treturn validator.Validate(dto)\n}\n\n", name)
+}
+
+func pointerType(typ string) string {
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") {
+		return typ
+	}
+
+	return "*" + typ
+}
+
+func joinRules(first, rules string) string {
+	if rules == "" {
+		return first
+	}
+
+	for _, r := range strings.Split(rules, ",") {
+		if r == first {
+			return rules
+		}
+	}
+
+	return first + "," + rules
+}
+
+func fieldTag(json, validate string) string {
+	var parts []string
+	if json != "" {
+		parts = append(parts, fmt.Sprintf("json:%q", json))
+	}
+	if validate != "" {
+		parts = append(parts, fmt.Sprintf("validate:%q", validate))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " `" + strings.Join(parts, " ") + "`"
+}
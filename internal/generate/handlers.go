@@ -0,0 +1,817 @@
+// Package generate holds the code generators of `gog generate`.
+package generate
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/openapi"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	domainsDir    = "internal/domains"
+	generatedLine = "// Code generated by gog generate handlers from %s. DO NOT EDIT.\n\n"
+
+	importFiber  = "github.com/gofiber/fiber/v2"
+	importUUID   = "github.com/google/uuid"
+	importLogger = "github.com/nayla-finance/go-nayla/logger"
+)
+
+type (
+	HandlersOptions struct {
+		// Dir is the project directory
+		Dir string
+		// Spec is the OpenAPI document
+		Spec string
+	}
+
+	// Result lists what a generation changed.
+	Result struct {
+		Created []string
+		Updated []string
+		// NewDomains are the domains that must be wired in the registry
+		NewDomains []Domain
+		Warnings   []string
+	}
+
+	Domain struct {
+		// Package is the domain package, e.g. pets
+		Package string
+		// Name prefixes the service interfaces, e.g. Pets for PetsService
+		Name string
+	}
+
+	domain struct {
+		pkg string
+		// name is the Go name used for the service, e.g. Pets for PetsService
+		name string
+		tag  string
+		ops  []*operation
+	}
+
+	operation struct {
+		method      string
+		path        string
+		name        string
+		summary     string
+		description string
+		tag         string
+		pathParams  []param
+		// params is the generated struct of the query and header parameters
+		params       string
+		queryParams  []param
+		headerParams []param
+		body         string
+		bodyDesc     string
+		bodyRequired bool
+		result       string
+		status       int
+		failures     []int
+	}
+
+	param struct {
+		name        string
+		goName      string
+		typ         string
+		description string
+		required    bool
+		schema      *openapi.Schema
+	}
+
+	generator struct {
+		spec     *openapi.Spec
+		specName string
+		module   string
+		dir      string
+		models   *models
+		domains  map[string]*domain
+		result   *Result
+	}
+)
+
+// reservedParams are the names used by the generated code.
+var reservedParams = map[string]bool{"c": true, "h": true, "s": true, "ctx": true, "dto": true, "params": true, "err": true, "result": true}
+
+// Handlers generates the domains described by an OpenAPI document.
+//
+// The routes, DTOs and service interfaces are generated in *_gen.go files that are rewritten
+// on every run. Handler and service stubs are added to handler.go and service.go, on the next
+// runs only their signatures and swag annotations are updated so the bodies are kept.
+func Handlers(opts HandlersOptions) (*Result, error) {
+	spec, err := openapi.Load(opts.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(opts.Dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read go.mod: %w", err)
+	}
+
+	g := &generator{
+		spec:     spec,
+		specName: filepath.Base(opts.Spec),
+		module:   modfile.ModulePath(data),
+		dir:      opts.Dir,
+		models:   newModels(spec),
+		domains:  map[string]*domain{},
+		result:   &Result{},
+	}
+
+	if err := g.models.components(); err != nil {
+		return nil, err
+	}
+
+	if err := g.collect(); err != nil {
+		return nil, err
+	}
+
+	if err := g.write(); err != nil {
+		return nil, err
+	}
+
+	return g.result, nil
+}
+
+// collect groups the operations by their first tag (or first path segment).
+func (g *generator) collect() error {
+	names := map[string]string{}
+
+	for _, path := range g.spec.SortedPaths() {
+		item := g.spec.Paths[path]
+		ops := item.Operations()
+
+		for _, method := range openapi.Methods {
+			op, ok := ops[method]
+			if !ok {
+				continue
+			}
+
+			tag := firstSegment(path)
+			if len(op.Tags) > 0 {
+				tag = op.Tags[0]
+			}
+
+			pkg := packageName(tag)
+			d, ok := g.domains[pkg]
+			if !ok {
+				d = &domain{pkg: pkg, name: exported(tag), tag: tag}
+				g.domains[pkg] = d
+			}
+
+			o, err := g.operation(method, path, tag, item, op)
+			if err != nil {
+				return fmt.Errorf("❌ %s %s: %w", strings.ToUpper(method), path, err)
+			}
+
+			if other, ok := names[pkg+"."+o.name]; ok {
+				return fmt.Errorf("❌ %s %s and %s generate the same method %s, set a unique operationId", strings.ToUpper(method), path, other, o.name)
+			}
+			names[pkg+"."+o.name] = strings.ToUpper(method) + " " + path
+
+			d.ops = append(d.ops, o)
+		}
+	}
+
+	if len(g.domains) == 0 {
+		return fmt.Errorf("❌ %s has no operations", g.specName)
+	}
+
+	return nil
+}
+
+func (g *generator) operation(method, path, tag string, item *openapi.PathItem, op *openapi.Operation) (*operation, error) {
+	name := op.OperationID
+	if name == "" {
+		name = method + " " + strings.NewReplacer("{", "by ", "}", "").Replace(path)
+	}
+
+	o := &operation{
+		method:      strings.ToUpper(method),
+		path:        path,
+		name:        exported(name),
+		summary:     firstLine(op.Summary),
+		description: firstLine(op.Description),
+		tag:         tag,
+	}
+
+	// operation parameters override the path item ones with the same name and location
+	params := map[string]*openapi.Parameter{}
+	var order []string
+	for _, p := range append(append([]*openapi.Parameter{}, item.Parameters...), op.Parameters...) {
+		p, err := g.spec.Parameter(p)
+		if err != nil {
+			return nil, err
+		}
+
+		key := p.In + ":" + p.Name
+		if _, ok := params[key]; !ok {
+			order = append(order, key)
+		}
+		params[key] = p
+	}
+
+	used := map[string]bool{}
+	for _, key := range order {
+		p := params[key]
+
+		typ, schema := "string", &openapi.Schema{Type: "string"}
+		if p.Schema != nil {
+			var err error
+			if typ, err = g.models.goType(p.Schema, o.name+exported(p.Name)); err != nil {
+				return nil, err
+			}
+
+			if schema, err = g.spec.Schema(p.Schema); err != nil {
+				return nil, err
+			}
+		}
+
+		pr := param{
+			name:        p.Name,
+			typ:         typ,
+			description: firstLine(p.Description),
+			required:    p.Required || p.In == "path",
+			schema:      schema,
+		}
+
+		switch p.In {
+		case "path":
+			if schema.Format == "uuid" {
+				pr.typ = "uuid.UUID"
+			}
+
+			switch pr.typ {
+			case "string", "int", "uuid.UUID":
+			case "int32", "int64":
+				pr.typ = "int"
+			default:
+				pr.typ = "string"
+			}
+
+			pr.goName = unexported(p.Name)
+			if reservedParams[pr.goName] || used[pr.goName] {
+				pr.goName += "Param"
+			}
+			used[pr.goName] = true
+
+			o.pathParams = append(o.pathParams, pr)
+		case "query":
+			o.queryParams = append(o.queryParams, pr)
+		case "header":
+			o.headerParams = append(o.headerParams, pr)
+		}
+	}
+
+	if len(o.queryParams)+len(o.headerParams) > 0 {
+		o.params = g.paramsStruct(o)
+	}
+
+	if op.RequestBody != nil {
+		body, err := g.spec.RequestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+
+		if schema := openapi.JSON(body.Content); schema != nil {
+			typ, err := g.namedType(schema, o.name+"Request")
+			if err != nil {
+				return nil, err
+			}
+
+			o.body, o.bodyDesc, o.bodyRequired = typ, firstLine(body.Description), body.Required
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			// default and 2XX like ranges are documented by the spec only
+			continue
+		}
+
+		if status >= 400 {
+			o.failures = append(o.failures, status)
+			continue
+		}
+
+		if o.status != 0 || status < 200 || status >= 300 {
+			continue
+		}
+
+		o.status = status
+
+		resp, err := g.spec.Response(op.Responses[code])
+		if err != nil {
+			return nil, err
+		}
+
+		if schema := openapi.JSON(resp.Content); schema != nil {
+			typ, err := g.models.goType(schema, o.name+"Response")
+			if err != nil {
+				return nil, err
+			}
+
+			if g.models.isStruct(typ) {
+				typ = "*" + typ
+			}
+
+			o.result = typ
+		}
+	}
+
+	if o.status == 0 {
+		o.status = 200
+	}
+
+	return o, nil
+}
+
+// namedType returns a model type for a request body, declaring one for inline schemas.
+func (g *generator) namedType(schema *openapi.Schema, hint string) (string, error) {
+	typ, err := g.models.goType(schema, hint)
+	if err != nil || strings.HasPrefix(typ, modelPrefix) {
+		return typ, err
+	}
+
+	return g.models.declare(hint, schema)
+}
+
+// paramsStruct declares the struct the query and header parameters are parsed into.
+func (g *generator) paramsStruct(o *operation) string {
+	name := o.name + "Params"
+	d := &typeDecl{name: name, doc: fmt.Sprintf("%s holds the query and header parameters of %s %s", name, o.method, o.path)}
+
+	for _, group := range []struct {
+		tag    string
+		params []param
+	}{{"query", o.queryParams}, {"reqHeader", o.headerParams}} {
+		for _, p := range group.params {
+			typ := p.typ
+			if !p.required && isPointable(typ, g.models) {
+				typ = "*" + typ
+			}
+
+			f := field{name: exported(p.name), typ: typ, doc: p.description, tags: []string{fmt.Sprintf(`%s:"%s"`, group.tag, p.name)}}
+			if v := g.models.validateTag(p.schema, p.required, typ); v != "" {
+				f.tags = append(f.tags, fmt.Sprintf(`validate:"%s"`, v))
+			}
+
+			d.fields = append(d.fields, f)
+		}
+	}
+
+	for i := 2; g.models.decls[d.name] != nil; i++ {
+		d.name = name + strconv.Itoa(i)
+	}
+
+	g.models.decls[d.name] = d
+	g.models.order = append(g.models.order, d.name)
+
+	return modelPrefix + d.name
+}
+
+func (g *generator) write() error {
+	header := fmt.Sprintf(generatedLine, g.specName)
+
+	modelFile := filepath.Join(domainsDir, "model", strings.TrimSuffix(g.specName, filepath.Ext(g.specName))+"_gen.go")
+	if err := g.checkConflicts("model", modelFile, g.models.order); err != nil {
+		return err
+	}
+
+	if err := g.writeGenerated(modelFile, g.models.render(header)); err != nil {
+		return err
+	}
+
+	pkgs := make([]string, 0, len(g.domains))
+	for pkg := range g.domains {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	for _, pkg := range pkgs {
+		d := g.domains[pkg]
+
+		ifaceFile := filepath.Join(domainsDir, "interfaces", d.pkg+"_gen.go")
+		if err := g.checkConflicts("interfaces", ifaceFile, []string{d.name + "Service", d.name + "ServiceProvider"}); err != nil {
+			return err
+		}
+
+		if err := g.writeGenerated(ifaceFile, g.renderInterface(header, d)); err != nil {
+			return err
+		}
+
+		if err := g.checkConflicts(d.pkg, filepath.Join(domainsDir, d.pkg, "routes_gen.go"), []string{"RegisterRoutes"}); err != nil {
+			return err
+		}
+
+		if err := g.writeGenerated(filepath.Join(domainsDir, d.pkg, "routes_gen.go"), g.renderRoutes(header, d)); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(filepath.Join(g.dir, domainsDir, d.pkg, "handler.go")); os.IsNotExist(err) {
+			g.result.NewDomains = append(g.result.NewDomains, Domain{Package: d.pkg, Name: d.name})
+		}
+
+		if err := g.merge(handlerFile(g, d)); err != nil {
+			return err
+		}
+
+		if err := g.merge(serviceFile(g, d)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeGenerated formats and writes a generated file.
+func (g *generator) writeGenerated(rel string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to format %s: %w\n%s", rel, err, src)
+	}
+
+	return g.writeFile(rel, formatted)
+}
+
+func (g *generator) writeFile(rel string, data []byte) error {
+	path := filepath.Join(g.dir, rel)
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && string(existing) == string(data):
+		return nil
+	case err == nil:
+		g.result.Updated = append(g.result.Updated, rel)
+	default:
+		g.result.Created = append(g.result.Created, rel)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// imports returns the import paths used by a type expression.
+func (g *generator) imports(typ string) []string {
+	var out []string
+	if strings.Contains(typ, modelPrefix) {
+		out = append(out, g.module+"/"+domainsDir+"/model")
+	}
+	if strings.Contains(typ, "uuid.") {
+		out = append(out, importUUID)
+	}
+	if strings.Contains(typ, "time.") {
+		out = append(out, "time")
+	}
+
+	return out
+}
+
+func (g *generator) renderInterface(header string, d *domain) []byte {
+	var b strings.Builder
+	imports := map[string]bool{"context": true}
+
+	fmt.Fprintf(&b, "type (\n\t%sService interface {\n", d.name)
+	for _, o := range d.ops {
+		sig := o.serviceSignature()
+		for _, imp := range g.imports(sig) {
+			imports[imp] = true
+		}
+
+		fmt.Fprintf(&b, "\t\t%s%s\n", o.name, sig)
+	}
+	fmt.Fprintf(&b, "\t}\n\n\t%sServiceProvider interface {\n\t\t%sService() %sService\n\t}\n)\n", d.name, d.name, d.name)
+
+	return []byte(header + "package interfaces\n\n" + importBlock(imports) + b.String())
+}
+
+func (g *generator) renderRoutes(header string, d *domain) []byte {
+	var b strings.Builder
+	imports := map[string]bool{importFiber: true}
+
+	b.WriteString("func (h *Handler) RegisterRoutes(api fiber.Router) {\n")
+	for _, o := range d.ops {
+		fmt.Fprintf(&b, "\tapi.%s(%q, h.handle%s)\n", fiberMethod(o.method), fiberPath(o.path), o.name)
+	}
+	b.WriteString("}\n")
+
+	for _, o := range d.ops {
+		fmt.Fprintf(&b, "\n// handle%s parses and validates the request of %s %s before calling %s.\n", o.name, o.method, o.path, unexported(o.name))
+		fmt.Fprintf(&b, "func (h *Handler) handle%s(c *fiber.Ctx) error {\n", o.name)
+
+		var args []string
+		for _, p := range o.pathParams {
+			imports[g.module+"/internal/errors"] = true
+			args = append(args, p.goName)
+
+			switch p.typ {
+			case "int":
+				fmt.Fprintf(&b, "\t%s, err := c.ParamsInt(%q)\n", p.goName, p.name)
+			case "uuid.UUID":
+				imports[importUUID] = true
+				fmt.Fprintf(&b, "\t%s, err := uuid.Parse(c.Params(%q))\n", p.goName, p.name)
+			default:
+				fmt.Fprintf(&b, "\t%s := c.Params(%q)\n", p.goName, p.name)
+				fmt.Fprintf(&b, "\tif %s == \"\" {\n\t\treturn h.d.NewError(errors.ErrBadRequest, \"missing %s\")\n\t}\n\n", p.goName, p.name)
+				continue
+			}
+			fmt.Fprintf(&b, "\tif err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, \"invalid %s: \"+err.Error())\n\t}\n\n", p.name)
+		}
+
+		if o.params != "" {
+			imports[g.module+"/internal/errors"] = true
+			imports[g.module+"/"+domainsDir+"/model"] = true
+			args = append(args, "params")
+
+			fmt.Fprintf(&b, "\tparams := &%s{}\n", o.params)
+			if len(o.queryParams) > 0 {
+				b.WriteString("\tif err := c.QueryParser(params); err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, err.Error())\n\t}\n\n")
+			}
+			if len(o.headerParams) > 0 {
+				b.WriteString("\tif err := c.ReqHeaderParser(params); err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, err.Error())\n\t}\n\n")
+			}
+			b.WriteString("\tif err := params.Validate(); err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, err.Error())\n\t}\n\n")
+		}
+
+		if o.body != "" {
+			imports[g.module+"/internal/errors"] = true
+			for _, imp := range g.imports(o.body) {
+				imports[imp] = true
+			}
+			args = append(args, "dto")
+
+			fmt.Fprintf(&b, "\tdto := new(%s)\n", o.body)
+			b.WriteString("\tif err := c.BodyParser(dto); err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, err.Error())\n\t}\n\n")
+			if g.models.isStruct(o.body) {
+				b.WriteString("\tif err := dto.Validate(); err != nil {\n\t\treturn h.d.NewError(errors.ErrBadRequest, err.Error())\n\t}\n\n")
+			}
+		}
+
+		fmt.Fprintf(&b, "\treturn h.%s(%s)\n}\n", unexported(o.name), strings.Join(append([]string{"c"}, args...), ", "))
+	}
+
+	imports[g.module+"/internal/errors"] = true
+	b.WriteString(serviceErrorFunc)
+
+	specs := []importSpec{{name: "stderrors", path: "errors"}, {path: "database/sql"}}
+	for p := range imports {
+		specs = append(specs, importSpec{path: p})
+	}
+
+	return []byte(header + "package " + d.pkg + "\n\n" + renderImports(specs) + "\n" + b.String())
+}
+
+// serviceErrorFunc maps the errors of the service stubs to the response: the errors.AppError of
+// the service as is, e.g. ErrResourceNotFound or ErrInvalidInput, sql.ErrNoRows to a 404, and
+// the other errors to a 500 without their text, it is only logged.
+const serviceErrorFunc = `
+// serviceError returns the error of a service call for the error handler: an errors.AppError is
+// returned as is, sql.ErrNoRows is a not found and the other errors are logged and hidden from
+// the client.
+func (h *Handler) serviceError(c *fiber.Ctx, err error) error {
+	if appErr, ok := err.(*errors.AppError); ok {
+		return appErr
+	}
+
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return h.d.NewError(errors.ErrResourceNotFound, "resource not found")
+	}
+
+	h.d.Logger().Errorw(c.UserContext(), "❌ Failed to handle the request", "path", c.Path(), "error", err)
+
+	return h.d.NewError(errors.ErrInternal, "internal server error")
+}
+`
+
+// args returns the typed parameters of the handler and service methods of an operation.
+func (o *operation) args() []string {
+	var args []string
+	for _, p := range o.pathParams {
+		args = append(args, p.goName+" "+p.typ)
+	}
+
+	if o.params != "" {
+		args = append(args, "params *"+o.params)
+	}
+
+	if o.body != "" {
+		args = append(args, "dto *"+o.body)
+	}
+
+	return args
+}
+
+func (o *operation) handlerSignature() string {
+	return "(" + strings.Join(append([]string{"c *fiber.Ctx"}, o.args()...), ", ") + ") error"
+}
+
+func (o *operation) serviceSignature() string {
+	sig := "(" + strings.Join(append([]string{"ctx context.Context"}, o.args()...), ", ") + ")"
+	if o.result != "" {
+		return sig + " (" + o.result + ", error)"
+	}
+
+	return sig + " error"
+}
+
+// swag returns the swag annotations of the handler stub.
+func (o *operation) swag() []string {
+	var lines []string
+
+	if o.summary != "" {
+		lines = append(lines, "@Summary\t\t"+o.summary)
+	}
+	if o.description != "" {
+		lines = append(lines, "@Description\t"+o.description)
+	}
+
+	lines = append(lines, "@Tags\t\t\t"+o.tag, "@Accept\t\t\tjson", "@Produce\t\tjson")
+
+	for _, p := range o.pathParams {
+		lines = append(lines, fmt.Sprintf("@Param\t\t\t%s\tpath\t%s\ttrue\t%q", p.name, swagType(p.typ), paramDescription(p)))
+	}
+
+	for _, group := range []struct {
+		in     string
+		params []param
+	}{{"query", o.queryParams}, {"header", o.headerParams}} {
+		for _, p := range group.params {
+			lines = append(lines, fmt.Sprintf("@Param\t\t\t%s\t%s\t%s\t%t\t%q", p.name, group.in, swagType(p.typ), p.required, paramDescription(p)))
+		}
+	}
+
+	if o.body != "" {
+		desc := o.bodyDesc
+		if desc == "" {
+			desc = "Request body"
+		}
+
+		lines = append(lines, fmt.Sprintf("@Param\t\t\tdto\tbody\t%s\t%t\t%q", strings.TrimPrefix(o.body, "*"), o.bodyRequired, desc))
+	}
+
+	switch {
+	case o.result == "":
+		lines = append(lines, fmt.Sprintf("@Success\t\t%d\t%q", o.status, statusText(o.status)))
+	case strings.HasPrefix(o.result, "[]"):
+		lines = append(lines, fmt.Sprintf("@Success\t\t%d\t{array}\t%s", o.status, swagType(strings.TrimPrefix(o.result, "[]"))))
+	case strings.HasPrefix(strings.TrimPrefix(o.result, "*"), modelPrefix):
+		lines = append(lines, fmt.Sprintf("@Success\t\t%d\t{object}\t%s", o.status, strings.TrimPrefix(o.result, "*")))
+	default:
+		lines = append(lines, fmt.Sprintf("@Success\t\t%d\t{%s}\t%s", o.status, swagType(o.result), swagType(o.result)))
+	}
+
+	for _, code := range o.failureCodes() {
+		lines = append(lines, fmt.Sprintf("@Failure\t\t%d\t{object}\terrors.ErrorResponse", code))
+	}
+
+	lines = append(lines, fmt.Sprintf("@Router\t\t\t%s [%s]", o.path, strings.ToLower(o.method)))
+
+	for i, l := range lines {
+		lines[i] = "// " + l
+	}
+
+	return lines
+}
+
+// failureCodes returns the error statuses of the spec with the ones of the generated code, like
+// the handlers of the template: 400 for an invalid request, 404 for a path parameter the service
+// does not find and 500.
+func (o *operation) failureCodes() []int {
+	codes := slices.Clone(o.failures)
+	if len(o.pathParams) > 0 || o.params != "" || o.body != "" {
+		codes = append(codes, 400)
+	}
+
+	if len(o.pathParams) > 0 {
+		codes = append(codes, 404)
+	}
+	codes = append(codes, 500)
+
+	slices.Sort(codes)
+
+	return slices.Compact(codes)
+}
+
+func paramDescription(p param) string {
+	if p.description != "" {
+		return p.description
+	}
+
+	return p.name
+}
+
+func swagType(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	switch typ {
+	case "int", "int32", "int64":
+		return "integer"
+	case "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "uuid.UUID", "time.Time":
+		return "string"
+	case "any", "map[string]any":
+		return "object"
+	}
+
+	if strings.HasPrefix(typ, "[]") {
+		return "[]" + swagType(typ[2:])
+	}
+
+	return typ
+}
+
+func statusText(status int) string {
+	switch status {
+	case 200:
+		return "OK"
+	case 201:
+		return "Created"
+	case 202:
+		return "Accepted"
+	case 204:
+		return "No Content"
+	}
+
+	return strconv.Itoa(status)
+}
+
+func fiberMethod(method string) string {
+	return strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+}
+
+// fiberPath converts /pets/{petId} to /pets/:petId.
+func fiberPath(path string) string {
+	return strings.NewReplacer("{", ":", "}", "").Replace(path)
+}
+
+func firstSegment(path string) string {
+	for _, s := range strings.Split(path, "/") {
+		if s != "" && !strings.HasPrefix(s, "{") {
+			return s
+		}
+	}
+
+	return "api"
+}
+
+func importBlock(imports map[string]bool) string {
+	specs := make([]importSpec, 0, len(imports))
+	for p := range imports {
+		specs = append(specs, importSpec{path: p})
+	}
+
+	return renderImports(specs) + "\n"
+}
+
+type importSpec struct {
+	name string
+	path string
+}
+
+// renderImports renders an import declaration with the standard library imports first, the
+// way goimports groups them.
+func renderImports(specs []importSpec) string {
+	sort.Slice(specs, func(i, j int) bool { return specs[i].path < specs[j].path })
+
+	var std, other []string
+	for _, s := range specs {
+		line := fmt.Sprintf("\t%q", s.path)
+		if s.name != "" {
+			line = fmt.Sprintf("\t%s %q", s.name, s.path)
+		}
+
+		if strings.Contains(strings.SplitN(s.path, "/", 2)[0], ".") {
+			other = append(other, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+
+	groups := []string{}
+	for _, g := range [][]string{std, other} {
+		if len(g) > 0 {
+			groups = append(groups, strings.Join(g, "\n"))
+		}
+	}
+
+	return "import (\n" + strings.Join(groups, "\n\n") + "\n)\n"
+}
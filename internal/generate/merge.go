@@ -0,0 +1,464 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+type (
+	// userFile is a file owned by the developer (handler.go, service.go) the generator only adds
+	// stubs to and keeps the signatures and swag annotations of in sync with the spec.
+	userFile struct {
+		rel string
+		// recv is the receiver of the methods, e.g. "h *Handler"
+		recv     string
+		scaffold string
+		stubs    []stub
+		// imports are added when missing
+		imports []string
+	}
+
+	stub struct {
+		name string
+		sig  string
+		// doc are the swag annotations, nil for service methods
+		doc  []string
+		body string
+	}
+
+	edit struct {
+		start, end int
+		text       string
+	}
+)
+
+func handlerFile(g *generator, d *domain) *userFile {
+	f := &userFile{
+		rel:     filepath.Join(domainsDir, d.pkg, "handler.go"),
+		recv:    "h *Handler",
+		imports: []string{importFiber, g.module + "/internal/errors"},
+		scaffold: fmt.Sprintf(`package %s
+
+import (
+	"%s/%s/interfaces"
+	"%s/internal/errors"
+	"%s"
+)
+
+type (
+	handlerDependencies interface {
+		logger.Provider
+		interfaces.%sServiceProvider
+		errors.ErrorProvider
+	}
+
+	Handler struct {
+		d handlerDependencies
+	}
+)
+
+func NewHandler(d handlerDependencies) *Handler {
+	return &Handler{
+		d: d,
+	}
+}
+`, d.pkg, g.module, domainsDir, g.module, importLogger, d.name),
+	}
+
+	for _, o := range d.ops {
+		args := []string{"c.UserContext()"}
+		for _, p := range o.pathParams {
+			args = append(args, p.goName)
+		}
+		if o.params != "" {
+			args = append(args, "params")
+		}
+		if o.body != "" {
+			args = append(args, "dto")
+		}
+
+		call := fmt.Sprintf("h.d.%sService().%s(%s)", d.name, o.name, strings.Join(args, ", "))
+
+		var body string
+		if o.result != "" {
+			body = fmt.Sprintf("\tresult, err := %s\n\tif err != nil {\n\t\treturn h.serviceError(c, err)\n\t}\n\n", call)
+			if o.status == 200 {
+				body += "\treturn c.JSON(result)\n"
+			} else {
+				body += fmt.Sprintf("\treturn c.Status(%s).JSON(result)\n", fiberStatus(o.status))
+			}
+		} else {
+			body = fmt.Sprintf("\tif err := %s; err != nil {\n\t\treturn h.serviceError(c, err)\n\t}\n\n\treturn c.SendStatus(%s)\n", call, fiberStatus(o.status))
+		}
+
+		f.stubs = append(f.stubs, stub{name: unexported(o.name), sig: o.handlerSignature(), doc: o.swag(), body: body})
+		f.imports = append(f.imports, g.imports(o.handlerSignature())...)
+	}
+
+	return f
+}
+
+func serviceFile(g *generator, d *domain) *userFile {
+	f := &userFile{
+		rel:     filepath.Join(domainsDir, d.pkg, "service.go"),
+		recv:    "s *svc",
+		imports: []string{"context", "fmt"},
+		scaffold: fmt.Sprintf(`package %s
+
+import (
+	"%s/%s/interfaces"
+	"%s"
+)
+
+var _ interfaces.%sService = new(svc)
+
+type (
+	serviceDependencies interface {
+		logger.Provider
+	}
+
+	svc struct {
+		d serviceDependencies
+	}
+)
+
+func NewService(d serviceDependencies) *svc {
+	return &svc{
+		d: d,
+	}
+}
+`, d.pkg, g.module, domainsDir, importLogger, d.name),
+	}
+
+	for _, o := range d.ops {
+		ret := fmt.Sprintf("fmt.Errorf(\"%s is not implemented\")", o.name)
+		if o.result != "" {
+			ret = g.models.zero(o.result) + ", " + ret
+		}
+
+		f.stubs = append(f.stubs, stub{
+			name: o.name,
+			sig:  o.serviceSignature(),
+			body: fmt.Sprintf("\t// TODO: implement\n\treturn %s\n", ret),
+		})
+		f.imports = append(f.imports, g.imports(o.serviceSignature())...)
+	}
+
+	return f
+}
+
+// merge adds the missing stubs to a user file and updates the signatures and swag annotations
+// of the existing ones, leaving everything else (bodies, other methods, comments) untouched.
+func (g *generator) merge(uf *userFile) error {
+	path := filepath.Join(g.dir, uf.rel)
+
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		src = []byte(uf.scaffold)
+	} else if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, uf.rel, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to parse %s: %w", uf.rel, err)
+	}
+
+	recvType := uf.recv[strings.LastIndex(uf.recv, " ")+1:]
+	recvType = strings.TrimPrefix(recvType, "*")
+
+	methods := map[string]*ast.FuncDecl{}
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && receiverType(fd) == recvType {
+			methods[fd.Name.Name] = fd
+		}
+	}
+
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	var edits []edit
+	var appended strings.Builder
+	wanted := map[string]bool{}
+
+	for _, s := range uf.stubs {
+		wanted[s.name] = true
+
+		fd, ok := methods[s.name]
+		if !ok {
+			appended.WriteString("\n")
+			for _, l := range s.doc {
+				appended.WriteString(l + "\n")
+			}
+			fmt.Fprintf(&appended, "func (%s) %s%s {\n%s}\n", uf.recv, s.name, s.sig, s.body)
+			continue
+		}
+
+		start, end := offset(fd.Type.Params.Opening), offset(fd.Type.End())
+		if string(src[start:end]) != s.sig {
+			edits = append(edits, edit{start: start, end: end, text: s.sig})
+		}
+
+		if s.doc == nil {
+			continue
+		}
+
+		if fd.Doc == nil {
+			edits = append(edits, edit{start: offset(fd.Pos()), end: offset(fd.Pos()), text: strings.Join(s.doc, "\n") + "\n"})
+			continue
+		}
+
+		// keep the developer's own comment lines, replace the annotations
+		var lines []string
+		for _, c := range fd.Doc.List {
+			if !strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(c.Text, "//")), "@") {
+				lines = append(lines, c.Text)
+			}
+		}
+		lines = append(lines, s.doc...)
+
+		start, end = offset(fd.Doc.Pos()), offset(fd.Doc.End())
+		if text := strings.Join(lines, "\n"); string(src[start:end]) != text {
+			edits = append(edits, edit{start: start, end: end, text: text})
+		}
+	}
+
+	for name, fd := range methods {
+		if !wanted[name] && fd.Doc != nil && strings.Contains(fd.Doc.Text(), "@Router") {
+			g.result.Warnings = append(g.result.Warnings, fmt.Sprintf("%s: %s no longer matches an operation of %s", uf.rel, name, g.specName))
+		}
+	}
+	sort.Strings(g.result.Warnings)
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte{}, src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	out = append(bytes.TrimRight(out, "\n"), '\n')
+	out = append(out, appended.String()...)
+
+	// a type import may be left unused when a parameter type changed in the spec
+	removable := append([]string{g.module + "/" + domainsDir + "/model", importUUID, "time"}, uf.imports...)
+
+	out, err = fixImports(uf.rel, out, uf.imports, removable)
+	if err != nil {
+		return err
+	}
+
+	return g.writeFile(uf.rel, out)
+}
+
+// fixImports adds the imports the stubs need and removes the removable ones left unused.
+func fixImports(rel string, src []byte, imports, removable []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, rel, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse the merged %s: %w", rel, err)
+	}
+
+	for _, imp := range imports {
+		astutil.AddImport(fset, file, imp)
+	}
+
+	for _, imp := range removable {
+		// UsesImport guesses the package name from the last path element
+		if !strings.Contains(filepath.Base(imp), ".") && !isVersion(filepath.Base(imp)) && !astutil.UsesImport(file, imp) {
+			astutil.DeleteImport(fset, file, imp)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+
+	return groupImports(rel, buf.Bytes())
+}
+
+// groupImports regroups a single import declaration without comments into the standard
+// library and the other imports, AddImport appends to the closest group only.
+func groupImports(rel string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, rel, src, parser.ParseComments|parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			if decl != nil {
+				return format.Source(src)
+			}
+			decl = gd
+		}
+	}
+
+	if decl == nil || !decl.Lparen.IsValid() {
+		return format.Source(src)
+	}
+
+	for _, c := range file.Comments {
+		if c.Pos() > decl.Pos() && c.End() < decl.End() {
+			return format.Source(src)
+		}
+	}
+
+	var specs []importSpec
+	for _, s := range decl.Specs {
+		is := s.(*ast.ImportSpec)
+
+		spec := importSpec{path: strings.Trim(is.Path.Value, `"`)}
+		if is.Name != nil {
+			spec.name = is.Name.Name
+		}
+		specs = append(specs, spec)
+	}
+
+	start, end := fset.Position(decl.Pos()).Offset, fset.Position(decl.End()).Offset
+	out := append([]byte{}, src[:start]...)
+	out = append(out, strings.TrimSuffix(renderImports(specs), "\n")...)
+	out = append(out, src[end:]...)
+
+	return format.Source(out)
+}
+
+func isVersion(s string) bool {
+	return len(s) > 1 && s[0] == 'v' && strings.Trim(s[1:], "0123456789") == ""
+}
+
+func receiverType(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+
+	t := fd.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return ""
+}
+
+// checkConflicts fails when a hand written file of the package declares a name the generated
+// file declares.
+func (g *generator) checkConflicts(pkg, generated string, names []string) error {
+	rel, name, err := findDeclaration(g.dir, filepath.Join(domainsDir, pkg), generated, names)
+	if err != nil {
+		return err
+	}
+
+	if rel != "" {
+		return fmt.Errorf("❌ %s already declares %s which is generated in %s, remove it or use another tag in the spec", rel, name, generated)
+	}
+
+	return nil
+}
+
+// findDeclaration returns the first file of the package in pkgDir, other than generated, that
+// declares one of names as a type or a function, and the name it declares. Both paths are
+// relative to dir.
+func findDeclaration(dir, pkgDir, generated string, names []string) (string, string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, pkgDir))
+	if os.IsNotExist(err) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	want := map[string]bool{}
+	for _, n := range names {
+		want[n] = true
+	}
+
+	for _, e := range entries {
+		rel := filepath.Join(pkgDir, e.Name())
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") || rel == generated {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, rel), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", "", fmt.Errorf("❌ Failed to parse %s: %w", rel, err)
+		}
+
+		for _, decl := range file.Decls {
+			var declared []string
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				declared = append(declared, decl.Name.Name)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						declared = append(declared, ts.Name.Name)
+					}
+				}
+			}
+
+			for _, name := range declared {
+				if want[name] {
+					return rel, name, nil
+				}
+			}
+		}
+	}
+
+	return "", "", nil
+}
+
+// zero returns the zero value expression of a type.
+func (m *models) zero(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["), typ == "any":
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case typ == "time.Time":
+		return "time.Time{}"
+	case strings.HasPrefix(typ, modelPrefix):
+		d := m.decls[strings.TrimPrefix(typ, modelPrefix)]
+		if d == nil || d.underlying == "" {
+			return typ + "{}"
+		}
+
+		if z := m.zero(d.underlying); z == "nil" || z == "false" || z == `""` || z == "0" {
+			return z
+		}
+
+		return typ + "{}"
+	}
+
+	return "0"
+}
+
+func fiberStatus(status int) string {
+	switch status {
+	case 200:
+		return "fiber.StatusOK"
+	case 201:
+		return "fiber.StatusCreated"
+	case 202:
+		return "fiber.StatusAccepted"
+	case 204:
+		return "fiber.StatusNoContent"
+	}
+
+	return fmt.Sprint(status)
+}
@@ -0,0 +1,716 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nayla-finance/gog/internal/migrations"
+)
+
+// DefaultModelsDir is where the models are looked for, every struct with a TableName method
+// is a model.
+const DefaultModelsDir = "internal"
+
+type (
+	MigrationOptions struct {
+		// Dir is the project directory
+		Dir string
+		// Models is the directory of the models relative to Dir, DefaultModelsDir when empty
+		Models string
+		// Name is the name of the migration, e.g. add_user_avatar
+		Name string
+		// AllowDestructive allows dropping columns and changing their type
+		AllowDestructive bool
+		// DryRun returns the migration without writing it
+		DryRun bool
+	}
+
+	MigrationResult struct {
+		// File is the migration written, relative to Dir, empty when the models match the schema
+		File string
+		SQL  string
+		// Changes describe the statements of the migration
+		Changes  []string
+		Warnings []string
+	}
+
+	modelTable struct {
+		name    string
+		model   string
+		columns []modelColumn
+	}
+
+	modelColumn struct {
+		name     string
+		sqlType  string
+		nullable bool
+	}
+
+	schemaChange struct {
+		description string
+		up          string
+		down        string
+		destructive bool
+		// uncastable is set when Postgres can not convert the values, e.g. SERIAL to UUID
+		uncastable bool
+	}
+)
+
+// Migration compares the models with the schema left by the migrations and generates a goose
+// migration adding the missing tables and columns, dropping the columns no model has and
+// changing the type and nullability of the others. Dropping and changing the type of a column
+// is refused unless opts.AllowDestructive is set, the type changes Postgres can not cast, e.g.
+// an integer to a UUID, are always refused.
+//
+// The schema is replayed from the CREATE, ALTER and DROP TABLE statements of the SQL
+// migrations, the tables without a model are left alone.
+func Migration(opts MigrationOptions) (*MigrationResult, error) {
+	if opts.Models == "" {
+		opts.Models = DefaultModelsDir
+	}
+	if opts.Name == "" {
+		opts.Name = "update_models"
+	}
+
+	schema, err := migrations.Replay(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{Warnings: schema.Warnings}
+
+	tables, warnings, err := loadModels(filepath.Join(opts.Dir, opts.Models))
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+
+	var changes []schemaChange
+	for _, t := range tables {
+		changes = append(changes, diffTable(t, schema.Tables[t.name])...)
+	}
+
+	if len(changes) == 0 {
+		return result, nil
+	}
+
+	var destructive, uncastable []string
+	for _, c := range changes {
+		result.Changes = append(result.Changes, c.description)
+		if c.destructive {
+			destructive = append(destructive, c.description)
+		}
+		if c.uncastable {
+			uncastable = append(uncastable, c.description)
+		}
+	}
+
+	if len(uncastable) > 0 {
+		return nil, fmt.Errorf("❌ Postgres can not cast the columns to the type of the models, write the migration by hand, e.g. add a column of the new type, fill it and drop the old one:\n  - %s", strings.Join(uncastable, "\n  - "))
+	}
+
+	if len(destructive) > 0 && !opts.AllowDestructive {
+		return nil, fmt.Errorf("❌ The models need destructive changes, check them and rerun with --allow-destructive:\n  - %s", strings.Join(destructive, "\n  - "))
+	}
+
+	result.SQL = renderMigration(changes)
+	if opts.DryRun {
+		return result, nil
+	}
+
+	migrationsDir := migrations.Dir(opts.Dir)
+	version, err := nextVersion(filepath.Join(opts.Dir, migrationsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	result.File = filepath.Join(migrationsDir, fmt.Sprintf("%s_%s.sql", version, snake(opts.Name)))
+	if err := os.MkdirAll(filepath.Join(opts.Dir, migrationsDir), 0755); err != nil {
+		return nil, fmt.Errorf("❌ Failed to create migrations directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(opts.Dir, result.File), []byte(result.SQL), 0644); err != nil {
+		return nil, fmt.Errorf("❌ Failed to write migration: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadModels finds the structs with a TableName method returning a string literal in the
+// packages under dir, sorted by table name.
+func loadModels(dir string) ([]*modelTable, []string, error) {
+	var tables []*modelTable
+	var warnings []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata" || d.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+
+		pkgTables, pkgWarnings, err := packageModels(path)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, pkgTables...)
+		warnings = append(warnings, pkgWarnings...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Failed to read models: %w", err)
+	}
+
+	sort.Slice(tables, func(i, j int) bool { return tables[i].name < tables[j].name })
+
+	return tables, warnings, nil
+}
+
+// modelPackage holds the declarations of a package needed to map its models to tables.
+type modelPackage struct {
+	types    map[string]*ast.TypeSpec
+	warnings []string
+}
+
+func packageModels(dir string) ([]*modelTable, []string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkg := &modelPackage{types: map[string]*ast.TypeSpec{}}
+	tableNames := map[string]string{}
+	fset := token.NewFileSet()
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						pkg.types[ts.Name.Name] = ts
+					}
+				}
+			case *ast.FuncDecl:
+				if model, table := tableName(decl); model != "" {
+					tableNames[model] = table
+				}
+			}
+		}
+	}
+
+	var tables []*modelTable
+	for model, table := range tableNames {
+		ts, ok := pkg.types[model]
+		if !ok {
+			continue
+		}
+
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		t := &modelTable{name: table, model: model}
+		t.columns = pkg.columns(model, st, map[string]bool{model: true})
+		tables = append(tables, t)
+	}
+
+	return tables, pkg.warnings, nil
+}
+
+// tableName returns the receiver and the result of a TableName method returning a string
+// literal.
+func tableName(fn *ast.FuncDecl) (string, string) {
+	if fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", ""
+	}
+
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", ""
+	}
+
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", ""
+	}
+
+	table, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", ""
+	}
+
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+
+	id, ok := recv.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+
+	return id.Name, table
+}
+
+// columns maps the fields of a model to columns like sqlx does: the db tag or the lower case
+// field name, embedded structs are flattened. The fields holding other models are relations,
+// they are skipped.
+func (p *modelPackage) columns(model string, st *ast.StructType, seen map[string]bool) []modelColumn {
+	var columns []modelColumn
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			if s, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag, _, _ = strings.Cut(reflect.StructTag(s).Get("db"), ",")
+			}
+		}
+		if tag == "-" {
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			if id, ok := field.Type.(*ast.Ident); ok && !seen[id.Name] {
+				if ts, ok := p.types[id.Name]; ok {
+					if embedded, ok := ts.Type.(*ast.StructType); ok {
+						seen[id.Name] = true
+						columns = append(columns, p.columns(model, embedded, seen)...)
+					}
+				}
+			}
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+
+			column := tag
+			if column == "" {
+				column = strings.ToLower(name.Name)
+			}
+
+			sqlType, nullable, ok := p.sqlType(field.Type)
+			switch {
+			case sqlType == "" && ok:
+				// a relation
+			case !ok:
+				p.warnings = append(p.warnings, fmt.Sprintf("%s.%s: no SQL type for %s, the column is skipped", model, name.Name, exprString(field.Type)))
+			default:
+				columns = append(columns, modelColumn{name: column, sqlType: sqlType, nullable: nullable})
+			}
+		}
+	}
+
+	return columns
+}
+
+// sqlTypes are the Postgres types of the Go types, they follow the init migration.
+var sqlTypes = map[string]string{
+	"string":          "VARCHAR(255)",
+	"int":             "INTEGER",
+	"int8":            "SMALLINT",
+	"int16":           "SMALLINT",
+	"int32":           "INTEGER",
+	"int64":           "BIGINT",
+	"uint":            "BIGINT",
+	"uint8":           "SMALLINT",
+	"uint16":          "INTEGER",
+	"uint32":          "BIGINT",
+	"uint64":          "NUMERIC(20)",
+	"bool":            "BOOLEAN",
+	"float32":         "REAL",
+	"float64":         "DOUBLE PRECISION",
+	"time.Time":       "TIMESTAMPTZ",
+	"time.Duration":   "BIGINT",
+	"uuid.UUID":       "UUID",
+	"decimal.Decimal": "NUMERIC",
+	"json.RawMessage": "JSONB",
+	"[]byte":          "BYTEA",
+	"pq.StringArray":  "TEXT[]",
+	"pq.Int64Array":   "BIGINT[]",
+}
+
+// nullTypes are the nullable types of database/sql and their value type.
+var nullTypes = map[string]string{
+	"sql.NullString":  "string",
+	"sql.NullInt16":   "int16",
+	"sql.NullInt32":   "int32",
+	"sql.NullInt64":   "int64",
+	"sql.NullBool":    "bool",
+	"sql.NullFloat64": "float64",
+	"sql.NullTime":    "time.Time",
+	"uuid.NullUUID":   "uuid.UUID",
+}
+
+// sqlType returns the column type of a field type. The type is empty and ok is true for the
+// relations, the structs and slices of structs of the package.
+func (p *modelPackage) sqlType(expr ast.Expr) (sqlType string, nullable bool, ok bool) {
+	if star, isStar := expr.(*ast.StarExpr); isStar {
+		sqlType, _, ok = p.sqlType(star.X)
+		return sqlType, true, ok
+	}
+
+	name := exprString(expr)
+	if t, found := sqlTypes[name]; found {
+		return t, false, true
+	}
+
+	if value, found := nullTypes[name]; found {
+		return sqlTypes[value], true, true
+	}
+
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		ts, found := p.types[expr.Name]
+		if !found {
+			return "", false, false
+		}
+		if _, isStruct := ts.Type.(*ast.StructType); isStruct {
+			return "", false, true
+		}
+		return p.sqlType(ts.Type)
+	case *ast.ArrayType:
+		if expr.Len != nil {
+			return "", false, false
+		}
+		elem, _, found := p.sqlType(expr.Elt)
+		if elem == "" || strings.HasSuffix(elem, "[]") {
+			return "", false, found
+		}
+		if elem == sqlTypes["string"] {
+			elem = "TEXT"
+		}
+		return elem + "[]", false, true
+	}
+
+	return "", false, false
+}
+
+func exprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return exprString(expr.X) + "." + expr.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(expr.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(expr.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(expr.Key) + "]" + exprString(expr.Value)
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+// diffTable returns the changes turning table into the table of the model, table is nil when
+// no migration creates it.
+func diffTable(t *modelTable, table *migrations.Table) []schemaChange {
+	if table == nil {
+		return []schemaChange{createTable(t)}
+	}
+
+	var changes []schemaChange
+	for _, c := range t.columns {
+		existing := table.Column(c.name)
+		if existing == nil {
+			changes = append(changes, schemaChange{
+				description: fmt.Sprintf("%s: add column %s %s", t.name, c.name, c.sqlType),
+				up:          fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", t.name, addColumnDefinition(c)),
+				down:        fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", t.name, c.name),
+			})
+			continue
+		}
+
+		if !compatibleTypes(c.sqlType, existing.Type) {
+			changes = append(changes, schemaChange{
+				description: fmt.Sprintf("%s: change the type of %s from %s to %s", t.name, c.name, existing.Type, c.sqlType),
+				up:          fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", t.name, c.name, c.sqlType, c.name, c.sqlType),
+				down:        fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", t.name, c.name, storageType(existing.Type), c.name, storageType(existing.Type)),
+				destructive: true,
+				uncastable:  !castable(existing.Type, c.sqlType),
+			})
+		}
+
+		switch {
+		case c.nullable && existing.NotNull && !isPrimaryKey(c):
+			changes = append(changes, schemaChange{
+				description: fmt.Sprintf("%s: make %s nullable", t.name, c.name),
+				up:          fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", t.name, c.name),
+				down:        fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", t.name, c.name),
+			})
+		case !c.nullable && !existing.NotNull:
+			changes = append(changes, schemaChange{
+				description: fmt.Sprintf("%s: make %s not null", t.name, c.name),
+				up:          fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", t.name, c.name),
+				down:        fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", t.name, c.name),
+			})
+		}
+	}
+
+	for _, existing := range table.Columns {
+		if slicesIndex(t.columns, existing.Name) >= 0 {
+			continue
+		}
+
+		definition := existing.Name + " " + storageType(existing.Type)
+		changes = append(changes, schemaChange{
+			description: fmt.Sprintf("%s: drop column %s, the model %s has no field for it", t.name, existing.Name, t.model),
+			up:          fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", t.name, existing.Name),
+			// the values are lost, the column is restored nullable
+			down:        fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", t.name, definition),
+			destructive: true,
+		})
+	}
+
+	return changes
+}
+
+func createTable(t *modelTable) schemaChange {
+	var columns []string
+	for _, c := range t.columns {
+		columns = append(columns, "        "+columnDefinition(c))
+	}
+
+	return schemaChange{
+		description: fmt.Sprintf("create table %s for the model %s", t.name, t.model),
+		up:          fmt.Sprintf("CREATE TABLE\n    %s (\n%s\n    );", t.name, strings.Join(columns, ",\n")),
+		down:        fmt.Sprintf("DROP TABLE %s;", t.name),
+	}
+}
+
+// storageType returns the type of a serial column, SERIAL is only valid in CREATE TABLE.
+func storageType(sqlType string) string {
+	switch strings.ToUpper(sqlType) {
+	case "SMALLSERIAL", "SERIAL2":
+		return "SMALLINT"
+	case "SERIAL", "SERIAL4":
+		return "INTEGER"
+	case "BIGSERIAL", "SERIAL8":
+		return "BIGINT"
+	}
+
+	return sqlType
+}
+
+func isPrimaryKey(c modelColumn) bool {
+	return c.name == "id"
+}
+
+// columnDefinition is the definition of a column of a new table, id is the primary key and
+// the timestamps default to the current time.
+func columnDefinition(c modelColumn) string {
+	if isPrimaryKey(c) {
+		switch c.sqlType {
+		case "UUID":
+			return "id UUID PRIMARY KEY DEFAULT gen_random_uuid()"
+		case "INTEGER":
+			return "id SERIAL PRIMARY KEY"
+		case "BIGINT":
+			return "id BIGSERIAL PRIMARY KEY"
+		default:
+			return "id " + c.sqlType + " PRIMARY KEY"
+		}
+	}
+
+	definition := c.name + " " + c.sqlType
+	if !c.nullable {
+		definition += " NOT NULL"
+	}
+
+	if (c.name == "created_at" || c.name == "updated_at") && c.sqlType == "TIMESTAMPTZ" {
+		definition += " DEFAULT CURRENT_TIMESTAMP"
+	}
+
+	return definition
+}
+
+// addColumnDefinition is the definition of a column added to an existing table, a not null
+// column needs a default for the existing rows.
+func addColumnDefinition(c modelColumn) string {
+	definition := columnDefinition(c)
+	if c.nullable || strings.Contains(definition, "DEFAULT") {
+		return definition
+	}
+
+	if zero := zeroValue(c.sqlType); zero != "" {
+		definition += " DEFAULT " + zero
+	}
+
+	return definition
+}
+
+func zeroValue(sqlType string) string {
+	switch family(sqlType) {
+	case "string":
+		return "''"
+	case "integer", "number":
+		return "0"
+	case "boolean":
+		return "FALSE"
+	case "time":
+		return "CURRENT_TIMESTAMP"
+	case "uuid":
+		return "gen_random_uuid()"
+	case "json":
+		return "'{}'"
+	}
+
+	if strings.HasSuffix(sqlType, "[]") {
+		return "'{}'"
+	}
+
+	return ""
+}
+
+// compatibleTypes reports whether a column of type existing scans into the field of the
+// model, e.g. TEXT into a string. The types gog does not know, e.g. enums, are compatible.
+func compatibleTypes(model, existing string) bool {
+	f := family(existing)
+	if f == "" {
+		return true
+	}
+
+	return family(model) == f
+}
+
+// castable reports whether Postgres casts a column of type from to the type to, a UUID only
+// casts from and to a string.
+func castable(from, to string) bool {
+	f, t := family(from), family(to)
+	if f == "uuid" || t == "uuid" {
+		return f == t || f == "string" || t == "string"
+	}
+
+	return true
+}
+
+// family groups the SQL types scanning into the same Go types, it is empty for the unknown
+// types.
+func family(sqlType string) string {
+	t := strings.ToUpper(strings.TrimSpace(sqlType))
+	if elem, ok := strings.CutSuffix(t, "[]"); ok {
+		if f := family(elem); f != "" {
+			return "array of " + f
+		}
+		return ""
+	}
+
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = strings.TrimSpace(t[:i] + t[strings.LastIndexByte(t, ')')+1:])
+	}
+
+	switch t {
+	case "VARCHAR", "CHARACTER VARYING", "TEXT", "CHAR", "CHARACTER", "BPCHAR", "CITEXT":
+		return "string"
+	case "SMALLINT", "INT2", "INTEGER", "INT", "INT4", "BIGINT", "INT8", "SERIAL", "SERIAL4", "BIGSERIAL", "SERIAL8", "SMALLSERIAL":
+		return "integer"
+	case "REAL", "FLOAT4", "DOUBLE PRECISION", "FLOAT8", "FLOAT", "NUMERIC", "DECIMAL":
+		return "number"
+	case "BOOLEAN", "BOOL":
+		return "boolean"
+	case "TIMESTAMPTZ", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITHOUT TIME ZONE", "DATE":
+		return "time"
+	case "UUID":
+		return "uuid"
+	case "BYTEA":
+		return "bytes"
+	case "JSON", "JSONB":
+		return "json"
+	}
+
+	return ""
+}
+
+func slicesIndex(columns []modelColumn, name string) int {
+	for i, c := range columns {
+		if c.name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// renderMigration writes the changes as a goose migration, the Down section reverts them in
+// reverse order.
+func renderMigration(changes []schemaChange) string {
+	var up, down []string
+	for i, c := range changes {
+		up = append(up, c.up)
+		down = append(down, changes[len(changes)-1-i].down)
+	}
+
+	return fmt.Sprintf(`-- +goose Up
+-- +goose StatementBegin
+%s
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+%s
+
+-- +goose StatementEnd
+`, strings.Join(up, "\n\n"), strings.Join(down, "\n\n"))
+}
+
+// nextVersion returns the goose timestamp version of a new migration, after the existing ones
+// when the clock is behind them.
+func nextVersion(dir string) (string, error) {
+	version := time.Now().UTC().Format("20060102150405")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("❌ Failed to read migrations: %w", err)
+	}
+
+	latest := int64(0)
+	for _, e := range entries {
+		if m := migrations.FileName.FindStringSubmatch(e.Name()); m != nil {
+			if v, err := strconv.ParseInt(m[1], 10, 64); err == nil && v > latest {
+				latest = v
+			}
+		}
+	}
+
+	if v, _ := strconv.ParseInt(version, 10, 64); v <= latest {
+		version = strconv.FormatInt(latest+1, 10)
+	}
+
+	return version, nil
+}
+
+// snake turns a migration name into the goose file name, e.g. Add avatar to add_avatar.
+func snake(name string) string {
+	return strings.ToLower(strings.Join(words(name), "_"))
+}
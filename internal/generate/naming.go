@@ -0,0 +1,109 @@
+package generate
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+var initialisms = map[string]string{
+	"Id":   "ID",
+	"Ids":  "IDs",
+	"Url":  "URL",
+	"Uri":  "URI",
+	"Api":  "API",
+	"Uuid": "UUID",
+	"Http": "HTTP",
+	"Json": "JSON",
+	"Sql":  "SQL",
+	"Ip":   "IP",
+}
+
+// words splits identifiers like pet_id, petId or X-Request-ID into words.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 &&
+			(unicode.IsLower(cur[len(cur)-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	return out
+}
+
+// exported returns the exported Go name of an identifier, e.g. pet_id becomes PetID.
+func exported(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		w = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+		if i, ok := initialisms[w]; ok {
+			w = i
+		}
+		b.WriteString(w)
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+
+	return name
+}
+
+// unexported returns the unexported Go name of an identifier, e.g. PetID becomes petID.
+func unexported(s string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return "x"
+	}
+
+	name := strings.ToLower(ws[0])
+	if len(ws) > 1 {
+		name += exported(strings.Join(ws[1:], "_"))
+	}
+
+	if unicode.IsDigit(rune(name[0])) {
+		name = "x" + name
+	}
+
+	if token.IsKeyword(name) {
+		name += "_"
+	}
+
+	return name
+}
+
+// packageName returns a Go package name for an OpenAPI tag, e.g. "Pet Store" becomes petstore.
+func packageName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) || token.IsKeyword(name) {
+		name = "api" + name
+	}
+
+	return name
+}
@@ -0,0 +1,438 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/openapi"
+)
+
+// modelPrefix qualifies the generated types, it is stripped when rendering the model package.
+const modelPrefix = "model."
+
+type (
+	// models collects the Go types generated in the model package.
+	models struct {
+		spec    *openapi.Spec
+		decls   map[string]*typeDecl
+		order   []string
+		schemas map[*openapi.Schema]string
+		imports map[string]bool
+	}
+
+	typeDecl struct {
+		name string
+		doc  string
+		// underlying is the type of non struct declarations (e.g. []model.Pet)
+		underlying string
+		fields     []field
+	}
+
+	field struct {
+		name string
+		typ  string
+		tags []string
+		doc  string
+	}
+)
+
+func newModels(spec *openapi.Spec) *models {
+	return &models{
+		spec:    spec,
+		decls:   map[string]*typeDecl{},
+		schemas: map[*openapi.Schema]string{},
+		imports: map[string]bool{},
+	}
+}
+
+// components declares every component schema so unreferenced schemas are generated too.
+func (m *models) components() error {
+	names := make([]string, 0, len(m.spec.Components.Schemas))
+	for name := range m.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := m.declare(exported(name), m.spec.Components.Schemas[name]); err != nil {
+			return fmt.Errorf("❌ Failed to generate schema %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isStruct reports whether a qualified type (e.g. model.Pet) is a generated struct.
+func (m *models) isStruct(typ string) bool {
+	d, ok := m.decls[strings.TrimPrefix(typ, modelPrefix)]
+	return ok && strings.HasPrefix(typ, modelPrefix) && d.underlying == ""
+}
+
+// declare generates a named type for a schema and returns its qualified name.
+func (m *models) declare(name string, s *openapi.Schema) (string, error) {
+	if existing, ok := m.schemas[s]; ok {
+		return modelPrefix + existing, nil
+	}
+
+	base := name
+	for i := 2; m.decls[name] != nil; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	d := &typeDecl{name: name, doc: firstLine(s.Description)}
+	m.decls[name] = d
+	m.order = append(m.order, name)
+	m.schemas[s] = name
+
+	if !isObject(s) {
+		typ, err := m.goType(s, name+"Item")
+		if err != nil {
+			return "", err
+		}
+
+		d.underlying = typ
+		return modelPrefix + name, nil
+	}
+
+	keys, props, required, err := m.properties(s)
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range keys {
+		f, err := m.field(key, props[key], required[key], name+exported(key))
+		if err != nil {
+			return "", err
+		}
+
+		d.fields = append(d.fields, f)
+	}
+
+	return modelPrefix + name, nil
+}
+
+// properties merges the properties of a schema and of its allOf parts.
+func (m *models) properties(s *openapi.Schema) ([]string, map[string]*openapi.Schema, map[string]bool, error) {
+	var keys []string
+	props := map[string]*openapi.Schema{}
+	required := map[string]bool{}
+
+	parts := append([]*openapi.Schema{}, s.AllOf...)
+	parts = append(parts, s)
+
+	for _, part := range parts {
+		part, err := m.spec.Schema(part)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if part != s && len(part.AllOf) > 0 {
+			k, p, r, err := m.properties(part)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+
+			for _, key := range k {
+				if _, ok := props[key]; !ok {
+					keys = append(keys, key)
+				}
+				props[key] = p[key]
+				required[key] = required[key] || r[key]
+			}
+		}
+
+		for _, key := range part.Properties.Keys {
+			if _, ok := props[key]; !ok {
+				keys = append(keys, key)
+			}
+			props[key] = part.Properties.Values[key]
+		}
+
+		for _, r := range part.Required {
+			required[r] = true
+		}
+	}
+
+	return keys, props, required, nil
+}
+
+func (m *models) field(key string, s *openapi.Schema, required bool, hint string) (field, error) {
+	typ, err := m.goType(s, hint)
+	if err != nil {
+		return field{}, err
+	}
+
+	resolved, err := m.spec.Schema(s)
+	if err != nil {
+		return field{}, err
+	}
+
+	jsonTag := key
+	if !required {
+		jsonTag += ",omitempty"
+		if isPointable(typ, m) || (resolved.Nullable && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[")) {
+			typ = "*" + typ
+		}
+	}
+
+	f := field{
+		name: exported(key),
+		typ:  typ,
+		tags: []string{fmt.Sprintf(`json:"%s"`, jsonTag)},
+		doc:  firstLine(resolved.Description),
+	}
+
+	if v := m.validateTag(resolved, required, typ); v != "" {
+		f.tags = append(f.tags, fmt.Sprintf(`validate:"%s"`, v))
+	}
+
+	return f, nil
+}
+
+// goType returns the Go type of a schema, declaring named types for inline objects.
+func (m *models) goType(s *openapi.Schema, hint string) (string, error) {
+	if s == nil {
+		return "any", nil
+	}
+
+	if s.Ref != "" {
+		resolved, err := m.spec.Schema(s)
+		if err != nil {
+			return "", err
+		}
+
+		return m.declare(exported(openapi.RefName(s.Ref)), resolved)
+	}
+
+	switch {
+	case len(s.AllOf) == 1 && len(s.Properties.Keys) == 0:
+		return m.goType(s.AllOf[0], hint)
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		// unions have no Go equivalent, the handler decodes them itself
+		return "any", nil
+	case isObject(s) && (len(s.Properties.Keys) > 0 || len(s.AllOf) > 0):
+		return m.declare(hint, s)
+	}
+
+	switch s.Type {
+	case "array":
+		item, err := m.goType(s.Items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + item, nil
+	case "object":
+		if s.AdditionalProperties != nil {
+			value, err := m.goType(s.AdditionalProperties, hint+"Value")
+			if err != nil {
+				return "", err
+			}
+
+			return "map[string]" + value, nil
+		}
+
+		return "map[string]any", nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			m.imports["time"] = true
+			return "time.Time", nil
+		case "binary", "byte":
+			return "[]byte", nil
+		}
+
+		return "string", nil
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		}
+
+		return "int", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+
+	return "any", nil
+}
+
+// validateTag derives the go-playground/validator tag of a field from the schema constraints.
+func (m *models) validateTag(s *openapi.Schema, required bool, typ string) string {
+	var rules []string
+
+	switch s.Type {
+	case "string":
+		if s.MinLength != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinLength))
+		}
+		if s.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxLength))
+		}
+
+		switch s.Format {
+		case "email":
+			rules = append(rules, "email")
+		case "uuid":
+			rules = append(rules, "uuid")
+		case "uri", "url":
+			rules = append(rules, "url")
+		case "date":
+			rules = append(rules, "datetime=2006-01-02")
+		case "ipv4":
+			rules = append(rules, "ipv4")
+		case "ipv6":
+			rules = append(rules, "ipv6")
+		case "hostname":
+			rules = append(rules, "hostname")
+		}
+	case "integer", "number":
+		if min, exclusive := openapi.Bound(s.Minimum, s.ExclusiveMinimum); min != nil {
+			rules = append(rules, fmt.Sprintf("%s=%s", map[bool]string{true: "gt", false: "gte"}[exclusive], formatNumber(*min)))
+		}
+		if max, exclusive := openapi.Bound(s.Maximum, s.ExclusiveMaximum); max != nil {
+			rules = append(rules, fmt.Sprintf("%s=%s", map[bool]string{true: "lt", false: "lte"}[exclusive], formatNumber(*max)))
+		}
+	case "array":
+		if s.MinItems != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinItems))
+		}
+		if s.MaxItems != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxItems))
+		}
+
+		if s.Items != nil {
+			if item, err := m.spec.Schema(s.Items); err == nil {
+				// nested structs are validated by Validate, slices only when told to dive
+				if dive := m.validateTag(item, false, ""); dive != "" || isObject(item) {
+					rules = append(rules, "dive")
+					if dive != "" {
+						rules = append(rules, strings.TrimPrefix(dive, "omitempty,"))
+					}
+				}
+			}
+		}
+	}
+
+	if oneOf := enumRule(s.Enum); oneOf != "" {
+		rules = append(rules, oneOf)
+	}
+
+	switch {
+	case required && typ != "bool":
+		rules = append([]string{"required"}, rules...)
+	case len(rules) > 0 && rules[0] != "dive":
+		rules = append([]string{"omitempty"}, rules...)
+	}
+
+	return strings.Join(rules, ",")
+}
+
+func enumRule(values []any) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	var out []string
+	for _, v := range values {
+		s := fmt.Sprint(v)
+		if v == nil || s == "" || strings.ContainsAny(s, " ,|'") {
+			// validator oneof can not express these values
+			return ""
+		}
+
+		out = append(out, s)
+	}
+
+	return "oneof=" + strings.Join(out, " ")
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func isObject(s *openapi.Schema) bool {
+	return s.Type == "object" || (s.Type == "" && (len(s.Properties.Keys) > 0 || len(s.AllOf) > 0))
+}
+
+// isPointable reports whether an optional field of this type needs a pointer to tell a
+// missing value from the zero value.
+func isPointable(typ string, m *models) bool {
+	switch {
+	case typ == "any", strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return false
+	case strings.HasPrefix(typ, modelPrefix):
+		d := m.decls[strings.TrimPrefix(typ, modelPrefix)]
+		return d == nil || d.underlying == "" || !(strings.HasPrefix(d.underlying, "[]") || strings.HasPrefix(d.underlying, "map["))
+	}
+
+	return true
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+
+	return s
+}
+
+// render returns the source of the model file.
+func (m *models) render(header string) []byte {
+	var b strings.Builder
+
+	b.WriteString(header)
+	b.WriteString("package model\n\nimport (\n")
+	if m.imports["time"] {
+		b.WriteString("\t\"time\"\n\n")
+	}
+	b.WriteString("\t\"github.com/nayla-finance/go-nayla/validator\"\n)\n")
+
+	for _, name := range m.order {
+		d := m.decls[name]
+
+		b.WriteString("\n")
+		if d.doc != "" && strings.HasPrefix(d.doc, d.name+" ") {
+			fmt.Fprintf(&b, "// %s\n", d.doc)
+		} else if d.doc != "" {
+			fmt.Fprintf(&b, "// %s is %s\n", d.name, lowerFirst(d.doc))
+		}
+
+		if d.underlying != "" {
+			fmt.Fprintf(&b, "type %s %s\n", d.name, strings.ReplaceAll(d.underlying, modelPrefix, ""))
+			continue
+		}
+
+		fmt.Fprintf(&b, "type %s struct {\n", d.name)
+		for _, f := range d.fields {
+			if f.doc != "" {
+				fmt.Fprintf(&b, "\t// %s\n", f.doc)
+			}
+			fmt.Fprintf(&b, "\t%s %s `%s`\n", f.name, strings.ReplaceAll(f.typ, modelPrefix, ""), strings.Join(f.tags, " "))
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\nfunc (dto *%s) Validate() error {\n\treturn validator.Validate(dto)\n}\n", d.name)
+	}
+
+	return []byte(b.String())
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,109 @@
+package graph
+
+import "sort"
+
+// findCycles returns every strongly connected component with more than one node (or a node
+// depending on itself) using Tarjan's algorithm. Each cycle is reported by node name.
+func findCycles(g *Graph) [][]string {
+	adj := map[string][]string{}
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+	}
+
+	var (
+		index   int
+		stack   []string
+		onStack = map[string]bool{}
+		indices = map[string]int{}
+		lowlink = map[string]int{}
+		cycles  [][]string
+	)
+
+	var connect func(v string)
+	connect = func(v string) {
+		indices[v] = index
+		lowlink[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range adj[v] {
+			if _, visited := indices[w]; !visited {
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], indices[w])
+			}
+		}
+
+		if lowlink[v] != indices[v] {
+			return
+		}
+
+		var component []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+
+			if w == v {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfLoop(adj, v) {
+			sort.Strings(component)
+
+			names := make([]string, 0, len(component))
+			for _, id := range component {
+				names = append(names, g.nodes[id].Name)
+			}
+
+			cycles = append(cycles, names)
+		}
+	}
+
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if _, visited := indices[id]; !visited {
+			connect(id)
+		}
+	}
+
+	return cycles
+}
+
+func selfLoop(adj map[string][]string, v string) bool {
+	for _, w := range adj[v] {
+		if w == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inCycle returns the set of node ids taking part in a cycle, used to highlight them.
+func (g *Graph) inCycle() map[string]bool {
+	names := map[string]bool{}
+	for _, c := range g.Cycles {
+		for _, n := range c {
+			names[n] = true
+		}
+	}
+
+	ids := map[string]bool{}
+	for _, n := range g.Nodes {
+		if names[n.Name] {
+			ids[n.ID] = true
+		}
+	}
+
+	return ids
+}
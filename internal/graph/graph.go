@@ -0,0 +1,594 @@
+package graph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/source"
+)
+
+type (
+	NodeKind string
+	EdgeKind string
+
+	Node struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Kind     NodeKind `json:"kind"`
+		Package  string   `json:"package"`
+		Position string   `json:"position,omitempty"`
+		External bool     `json:"external,omitempty"`
+	}
+
+	Edge struct {
+		From string   `json:"from"`
+		To   string   `json:"to"`
+		Kind EdgeKind `json:"kind"`
+	}
+
+	// Unsatisfied is a dependency interface the registry does not implement.
+	Unsatisfied struct {
+		Interface string   `json:"interface"`
+		Position  string   `json:"position"`
+		Missing   []string `json:"missing"`
+	}
+
+	Graph struct {
+		Nodes           []*Node       `json:"nodes"`
+		Edges           []Edge        `json:"edges"`
+		Cycles          [][]string    `json:"cycles"`
+		UnusedProviders []string      `json:"unused_providers"`
+		Unsatisfied     []Unsatisfied `json:"unsatisfied"`
+
+		nodes map[string]*Node
+	}
+)
+
+const (
+	KindProvider    NodeKind = "provider"
+	KindConstructor NodeKind = "constructor"
+
+	EdgeDependsOn  EdgeKind = "depends_on"
+	EdgeProvidedBy EdgeKind = "provided_by"
+)
+
+// iface is an interface declared in the project with its embedded interfaces resolved to refs.
+type iface struct {
+	ref     string
+	name    string
+	pkg     *source.Package
+	pos     token.Pos
+	embeds  []string
+	methods []string
+}
+
+type analyzer struct {
+	m      *source.Module
+	g      *Graph
+	ifaces map[string]*iface
+	ctors  map[string]bool
+}
+
+// Build analyzes every *Dependencies interface and constructor of the module and links them
+// to the providers exposed by the registry.
+func Build(m *source.Module) (*Graph, error) {
+	a := &analyzer{
+		m:      m,
+		g:      &Graph{nodes: map[string]*Node{}},
+		ifaces: map[string]*iface{},
+		ctors:  map[string]bool{},
+	}
+
+	a.collectInterfaces()
+	deps := a.collectConstructors()
+
+	if err := a.linkRegistry(deps); err != nil {
+		return nil, err
+	}
+
+	a.g.Cycles = findCycles(a.g)
+
+	sort.Slice(a.g.Nodes, func(i, j int) bool {
+		return a.g.Nodes[i].ID < a.g.Nodes[j].ID
+	})
+
+	return a.g, nil
+}
+
+func (a *analyzer) collectInterfaces() {
+	for _, pkg := range a.m.SortedPackages() {
+		for _, f := range pkg.Files {
+			imports := f.Imports()
+
+			ast.Inspect(f.AST, func(n ast.Node) bool {
+				ts, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+
+				it, ok := ts.Type.(*ast.InterfaceType)
+				if !ok {
+					return false
+				}
+
+				i := &iface{
+					ref:  pkg.ImportPath + "." + ts.Name.Name,
+					name: pkg.Name + "." + ts.Name.Name,
+					pkg:  pkg,
+					pos:  ts.Pos(),
+				}
+
+				for _, field := range it.Methods.List {
+					if len(field.Names) > 0 {
+						for _, name := range field.Names {
+							i.methods = append(i.methods, name.Name)
+						}
+						continue
+					}
+
+					if ref := resolveRef(pkg, imports, field.Type); ref != "" {
+						i.embeds = append(i.embeds, ref)
+					}
+				}
+
+				a.ifaces[i.ref] = i
+				return false
+			})
+		}
+	}
+}
+
+// collectConstructors adds a node for every function that receives a dependency interface and
+// returns the dependency interfaces found.
+func (a *analyzer) collectConstructors() []*iface {
+	var deps []*iface
+
+	for _, i := range a.sortedIfaces() {
+		if isDependencies(i.ref) {
+			deps = append(deps, i)
+		}
+	}
+
+	for _, pkg := range a.m.SortedPackages() {
+		for _, fd := range pkg.Funcs() {
+			if fd.Decl.Recv != nil {
+				continue
+			}
+
+			for _, param := range fd.Decl.Type.Params.List {
+				id, ok := param.Type.(*ast.Ident)
+				if !ok {
+					continue
+				}
+
+				d, ok := a.ifaces[pkg.ImportPath+"."+id.Name]
+				if !ok || !isDependencies(d.ref) {
+					continue
+				}
+
+				ref := pkg.ImportPath + "." + fd.Decl.Name.Name
+				a.ctors[ref] = true
+				a.addNode(&Node{
+					ID:       ref,
+					Name:     pkg.Name + "." + fd.Decl.Name.Name,
+					Kind:     KindConstructor,
+					Package:  pkg.ImportPath,
+					Position: a.position(fd.Decl.Pos()),
+				})
+
+				for _, dep := range a.flatten(d.ref) {
+					a.addProvider(dep)
+					a.addEdge(ref, dep, EdgeDependsOn)
+				}
+			}
+		}
+	}
+
+	return deps
+}
+
+func (a *analyzer) linkRegistry(deps []*iface) error {
+	var registry *iface
+	for _, i := range a.sortedIfaces() {
+		if strings.HasSuffix(i.ref, "/registry.RegistryProvider") {
+			registry = i
+			break
+		}
+	}
+
+	if registry == nil {
+		return fmt.Errorf("❌ Could not find the RegistryProvider interface in %s/internal/registry", a.m.Path)
+	}
+
+	provided := map[string]bool{}
+	for _, ref := range a.flatten(registry.ref) {
+		provided[ref] = true
+		a.addProvider(ref)
+	}
+
+	methods, methodResults, methodCtors := a.registryMethods(registry.pkg)
+
+	// link every provider to the constructor behind the registry getter
+	for _, n := range a.sortedNodes() {
+		if n.Kind != KindProvider {
+			continue
+		}
+
+		method := a.providerMethod(n.ID, methodResults)
+		ctor, ok := methodCtors[method]
+		if method == "" || !ok {
+			continue
+		}
+
+		if !a.ctors[ctor] {
+			a.addNode(&Node{
+				ID:       ctor,
+				Name:     a.displayName(ctor),
+				Kind:     KindConstructor,
+				Package:  packageOf(ctor),
+				External: !a.m.IsLocal(packageOf(ctor)),
+			})
+		}
+
+		a.addEdge(n.ID, ctor, EdgeProvidedBy)
+	}
+
+	consumed := map[string]bool{}
+	for _, d := range deps {
+		for _, ref := range a.flatten(d.ref) {
+			consumed[ref] = true
+		}
+	}
+
+	for _, ref := range a.flatten(registry.ref) {
+		if !consumed[ref] {
+			a.g.UnusedProviders = append(a.g.UnusedProviders, a.displayName(ref))
+		}
+	}
+
+	for _, d := range deps {
+		var missing []string
+
+		for _, ref := range a.flatten(d.ref) {
+			if provided[ref] {
+				continue
+			}
+
+			if i, ok := a.ifaces[ref]; ok && len(i.methods) > 0 && hasAll(methods, i.methods) {
+				continue
+			}
+
+			missing = append(missing, a.displayName(ref))
+		}
+
+		for _, method := range a.directMethods(d.ref) {
+			if !methods[method] {
+				missing = append(missing, method+"()")
+			}
+		}
+
+		if len(missing) > 0 {
+			a.g.Unsatisfied = append(a.g.Unsatisfied, Unsatisfied{
+				Interface: d.name,
+				Position:  a.position(d.pos),
+				Missing:   missing,
+			})
+		}
+	}
+
+	return nil
+}
+
+// registryMethods returns the method set of Registry, the result type of each method and the
+// constructor each getter delegates to (directly or through the field it returns).
+func (a *analyzer) registryMethods(pkg *source.Package) (map[string]bool, map[string]string, map[string]string) {
+	methods := map[string]bool{}
+	results := map[string]string{}
+	ctors := map[string]string{}
+	fieldCtors := map[string]string{}
+
+	var getters []source.FuncDecl
+	for _, fd := range pkg.Funcs() {
+		if fd.Receiver() != "Registry" {
+			continue
+		}
+
+		getters = append(getters, fd)
+		name := fd.Decl.Name.Name
+		methods[name] = true
+
+		imports := fd.File.Imports()
+		if res := fd.Decl.Type.Results; res != nil && len(res.List) == 1 {
+			results[name] = resolveRef(pkg, imports, res.List[0].Type)
+		}
+
+		recv := fd.ReceiverName()
+		if fd.Decl.Body == nil || recv == "" {
+			continue
+		}
+
+		ast.Inspect(fd.Decl.Body, func(n ast.Node) bool {
+			as, ok := n.(*ast.AssignStmt)
+			if !ok || len(as.Rhs) != 1 {
+				return true
+			}
+
+			sel, ok := as.Lhs[0].(*ast.SelectorExpr)
+			if !ok || !isIdent(sel.X, recv) {
+				return true
+			}
+
+			if call, ok := as.Rhs[0].(*ast.CallExpr); ok {
+				if ref := resolveRef(pkg, imports, call.Fun); ref != "" {
+					fieldCtors[sel.Sel.Name] = ref
+				}
+			}
+
+			return true
+		})
+	}
+
+	for _, fd := range getters {
+		recv := fd.ReceiverName()
+		if fd.Decl.Body == nil || recv == "" {
+			continue
+		}
+
+		name := fd.Decl.Name.Name
+		imports := fd.File.Imports()
+
+		ast.Inspect(fd.Decl.Body, func(n ast.Node) bool {
+			if _, found := ctors[name]; found {
+				return false
+			}
+
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				ref := resolveRef(pkg, imports, n.Fun)
+				if ref == "" || !a.ctors[ref] {
+					return true
+				}
+
+				for _, arg := range n.Args {
+					if isIdent(arg, recv) {
+						ctors[name] = ref
+						return false
+					}
+				}
+			case *ast.ReturnStmt:
+				if len(n.Results) != 1 {
+					return true
+				}
+
+				if sel, ok := n.Results[0].(*ast.SelectorExpr); ok && isIdent(sel.X, recv) {
+					if ref, ok := fieldCtors[sel.Sel.Name]; ok {
+						ctors[name] = ref
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	return methods, results, ctors
+}
+
+// providerMethod returns the registry method that satisfies a provider. Local providers declare
+// it; for external ones (e.g. nats.ServiceProvider) the method returning nats.Service is used.
+func (a *analyzer) providerMethod(ref string, results map[string]string) string {
+	if i, ok := a.ifaces[ref]; ok {
+		if len(i.methods) == 1 {
+			return i.methods[0]
+		}
+
+		return ""
+	}
+
+	pkg, name := packageOf(ref), nameOf(ref)
+	if !strings.HasSuffix(name, "Provider") || name == "Provider" {
+		return ""
+	}
+
+	provided := pkg + "." + strings.TrimSuffix(name, "Provider")
+
+	var match string
+	for method, result := range results {
+		if result == provided && (match == "" || method < match) {
+			match = method
+		}
+	}
+
+	return match
+}
+
+// flatten expands embedded interfaces that are not providers themselves
+// (e.g. RegistryProvider or a shared dependencies interface) into the providers they embed.
+func (a *analyzer) flatten(ref string) []string {
+	var out []string
+	seen := map[string]bool{ref: true}
+
+	var walk func(ref string)
+	walk = func(ref string) {
+		i, ok := a.ifaces[ref]
+		if !ok {
+			return
+		}
+
+		for _, e := range i.embeds {
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+
+			if inner, ok := a.ifaces[e]; ok && len(inner.embeds) > 0 {
+				walk(e)
+				continue
+			}
+
+			out = append(out, e)
+		}
+	}
+
+	walk(ref)
+
+	return out
+}
+
+func (a *analyzer) directMethods(ref string) []string {
+	var out []string
+	seen := map[string]bool{}
+
+	var walk func(ref string)
+	walk = func(ref string) {
+		i, ok := a.ifaces[ref]
+		if !ok || seen[ref] {
+			return
+		}
+		seen[ref] = true
+
+		out = append(out, i.methods...)
+		for _, e := range i.embeds {
+			if inner, ok := a.ifaces[e]; ok && len(inner.embeds) > 0 {
+				walk(e)
+			}
+		}
+	}
+
+	walk(ref)
+
+	return out
+}
+
+func (a *analyzer) addProvider(ref string) {
+	if _, ok := a.g.nodes[ref]; ok {
+		return
+	}
+
+	n := &Node{
+		ID:       ref,
+		Name:     a.displayName(ref),
+		Kind:     KindProvider,
+		Package:  packageOf(ref),
+		External: !a.m.IsLocal(packageOf(ref)),
+	}
+
+	if i, ok := a.ifaces[ref]; ok {
+		n.Position = a.position(i.pos)
+	}
+
+	a.addNode(n)
+}
+
+func (a *analyzer) addNode(n *Node) {
+	if _, ok := a.g.nodes[n.ID]; ok {
+		return
+	}
+
+	a.g.nodes[n.ID] = n
+	a.g.Nodes = append(a.g.Nodes, n)
+}
+
+func (a *analyzer) addEdge(from, to string, kind EdgeKind) {
+	for _, e := range a.g.Edges {
+		if e.From == from && e.To == to {
+			return
+		}
+	}
+
+	a.g.Edges = append(a.g.Edges, Edge{From: from, To: to, Kind: kind})
+}
+
+func (a *analyzer) position(pos token.Pos) string {
+	p := a.m.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
+
+func (a *analyzer) sortedIfaces() []*iface {
+	out := make([]*iface, 0, len(a.ifaces))
+	for _, i := range a.ifaces {
+		out = append(out, i)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ref < out[j].ref
+	})
+
+	return out
+}
+
+func (a *analyzer) sortedNodes() []*Node {
+	out := append([]*Node(nil), a.g.Nodes...)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
+// HasIssues reports whether the analysis found cycles, unused providers or unsatisfied dependencies.
+func (g *Graph) HasIssues() bool {
+	return len(g.Cycles) > 0 || len(g.UnusedProviders) > 0 || len(g.Unsatisfied) > 0
+}
+
+// resolveRef turns an identifier or qualified identifier into importPath.Name.
+func resolveRef(pkg *source.Package, imports map[string]string, expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return resolveRef(pkg, imports, e.X)
+	case *ast.Ident:
+		return pkg.ImportPath + "." + e.Name
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok {
+			return ""
+		}
+
+		importPath, ok := imports[x.Name]
+		if !ok {
+			return ""
+		}
+
+		return importPath + "." + e.Sel.Name
+	}
+
+	return ""
+}
+
+func isDependencies(ref string) bool {
+	return strings.HasSuffix(strings.ToLower(nameOf(ref)), "dependencies")
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+func hasAll(set map[string]bool, names []string) bool {
+	for _, n := range names {
+		if !set[n] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func packageOf(ref string) string {
+	return ref[:strings.LastIndex(ref, ".")]
+}
+
+func nameOf(ref string) string {
+	return ref[strings.LastIndex(ref, ".")+1:]
+}
+
+func (a *analyzer) displayName(ref string) string {
+	if pkg, ok := a.m.Packages[packageOf(ref)]; ok {
+		return pkg.Name + "." + nameOf(ref)
+	}
+
+	return source.ImportName(packageOf(ref)) + "." + nameOf(ref)
+}
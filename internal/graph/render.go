@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+// Render writes the graph in the given format (dot, mermaid or json).
+func (g *Graph) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return g.renderDOT(w)
+	case FormatMermaid:
+		return g.renderMermaid(w)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	default:
+		return fmt.Errorf("❌ Unsupported format '%s' (supported: %s, %s, %s)", format, FormatDOT, FormatMermaid, FormatJSON)
+	}
+}
+
+func (g *Graph) renderDOT(w io.Writer) error {
+	ids := g.shortIDs()
+	cyclic := g.inCycle()
+
+	var b strings.Builder
+	b.WriteString("digraph providers {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, n := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", n.Name)}
+
+		if n.Kind == KindProvider {
+			attrs = append(attrs, "shape=box")
+		} else {
+			attrs = append(attrs, "shape=ellipse")
+		}
+
+		if n.External {
+			attrs = append(attrs, "style=dashed")
+		}
+
+		if cyclic[n.ID] {
+			attrs = append(attrs, "color=red")
+		}
+
+		fmt.Fprintf(&b, "  %s [%s];\n", ids[n.ID], strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Kind == EdgeProvidedBy {
+			attrs = append(attrs, "style=dashed", `label="provided by"`)
+		}
+
+		if cyclic[e.From] && cyclic[e.To] {
+			attrs = append(attrs, "color=red")
+		}
+
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  %s -> %s;\n", ids[e.From], ids[e.To])
+			continue
+		}
+
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", ids[e.From], ids[e.To], strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) renderMermaid(w io.Writer) error {
+	ids := g.shortIDs()
+	cyclic := g.inCycle()
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	for _, n := range g.Nodes {
+		if n.Kind == KindProvider {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.ID], n.Name)
+		} else {
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", ids[n.ID], n.Name)
+		}
+	}
+
+	for _, e := range g.Edges {
+		if e.Kind == EdgeProvidedBy {
+			fmt.Fprintf(&b, "  %s -.->|provided by| %s\n", ids[e.From], ids[e.To])
+			continue
+		}
+
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	var cycleNodes []string
+	for _, n := range g.Nodes {
+		if cyclic[n.ID] {
+			cycleNodes = append(cycleNodes, ids[n.ID])
+		}
+	}
+
+	if len(cycleNodes) > 0 {
+		b.WriteString("  classDef cycle stroke:#e00,stroke-width:2px\n")
+		fmt.Fprintf(&b, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteIssues prints a human readable summary of the problems found in the graph.
+func (g *Graph) WriteIssues(w io.Writer) {
+	for _, c := range g.Cycles {
+		fmt.Fprintf(w, "❌ Dependency cycle between: %s\n", strings.Join(c, ", "))
+	}
+
+	for _, u := range g.Unsatisfied {
+		fmt.Fprintf(w, "❌ %s (%s) is not satisfied by the registry, missing: %s\n", u.Interface, u.Position, strings.Join(u.Missing, ", "))
+	}
+
+	for _, p := range g.UnusedProviders {
+		fmt.Fprintf(w, "⚠️  %s is embedded in RegistryProvider but no dependency interface uses it\n", p)
+	}
+}
+
+// shortIDs assigns DOT/Mermaid safe identifiers to the nodes.
+func (g *Graph) shortIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	return ids
+}
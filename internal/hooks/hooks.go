@@ -0,0 +1,260 @@
+// Package hooks installs the git hooks of a project from .gog/hooks.yaml and runs their steps.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// File lists the steps of every hook, relative to the project directory.
+const File = ".gog/hooks.yaml"
+
+// managedMarker is the line identifying the hook scripts written by Install.
+const managedMarker = "# Managed by gog"
+
+// Supported are the git hooks that can be configured.
+var Supported = []string{"pre-commit", "prepare-commit-msg", "commit-msg", "pre-push", "post-checkout", "post-merge"}
+
+type (
+	// Config holds the steps of each hook, by hook name.
+	Config map[string][]Step
+
+	// Step runs a shell command or a built-in check, see CheckNames.
+	Step struct {
+		Name string `yaml:"name,omitempty"`
+		// Run is a shell command run in the project directory, the hook arguments are $1, $2...
+		Run string `yaml:"run,omitempty"`
+		// Check is the name of a built-in check
+		Check string `yaml:"check,omitempty"`
+		// Fix lets the check repair what it can, e.g. gofmt formats and restages the files
+		Fix bool `yaml:"fix,omitempty"`
+	}
+)
+
+const header = `# Git hooks managed by gog, run gog hooks install after editing this file and
+# gog hooks run <hook> --all to run them in CI.
+#
+# A step either runs a shell command (run) or a built-in check (check):
+#   gofmt       the Go files are formatted, with fix: true they are formatted and restaged
+#   vet         go vet ./...
+#   swag        docs/ matches the swag annotations, like gog swag check
+#   migrations  the goose migration file names and annotations
+#   secrets     config.yaml, secrets/, private keys and tokens are not committed
+`
+
+// ProjectDefaults are the hooks of a new project.
+var ProjectDefaults = header + `pre-commit:
+  - check: gofmt
+    fix: true
+  - check: vet
+  - check: swag
+  - check: migrations
+  - check: secrets
+`
+
+// WorkspaceDefaults are the hooks of a new workspace, go vet and swag are per service.
+var WorkspaceDefaults = header + `pre-commit:
+  - check: gofmt
+    fix: true
+  - check: secrets
+`
+
+// String returns the name printed for the step.
+func (s Step) String() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Check != "":
+		return s.Check
+	default:
+		return s.Run
+	}
+}
+
+// Load reads and validates the hooks of the project in dir.
+func Load(dir string) (Config, error) {
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Config{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", File, err)
+	}
+
+	for hook, steps := range cfg {
+		if !slices.Contains(Supported, hook) {
+			return nil, fmt.Errorf("❌ Unknown hook '%s' in %s, available: %s", hook, File, strings.Join(Supported, ", "))
+		}
+
+		for i, s := range steps {
+			switch {
+			case (s.Run == "") == (s.Check == ""):
+				return nil, fmt.Errorf("❌ Step %d of %s in %s needs either run or check", i+1, hook, File)
+			case s.Check != "" && checks[s.Check] == nil:
+				return nil, fmt.Errorf("❌ Unknown check '%s' in %s, available: %s", s.Check, File, strings.Join(CheckNames(), ", "))
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// HookPath returns the path of a hook of the repository holding dir, git resolves worktrees
+// and core.hooksPath.
+func HookPath(dir, hook string) (string, error) {
+	out, err := git(dir, "rev-parse", "--path-format=absolute", "--git-path", "hooks/"+hook)
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to find the git repository: %w", err)
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Managed reports whether the hook script at path was written by Install.
+func Managed(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), managedMarker)
+}
+
+// Install writes defaults to the hooks file when the project has none, then writes a script
+// running gog hooks run for every configured hook and removes the managed scripts of the hooks
+// no longer configured. A hook script not written by gog is only replaced with force, it is
+// kept next to it with a .bak suffix. It returns the files written.
+func Install(dir, defaults string, force bool) ([]string, error) {
+	var written []string
+
+	file := filepath.Join(dir, File)
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(file, []byte(defaults), 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, file)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := projectPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hook := range Supported {
+		path, err := HookPath(dir, hook)
+		if err != nil {
+			return nil, err
+		}
+
+		_, exists := os.Stat(path)
+		if _, ok := cfg[hook]; !ok {
+			if exists == nil && Managed(path) {
+				if err := os.Remove(path); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if exists == nil && !Managed(path) {
+			if !force {
+				return nil, fmt.Errorf("❌ %s is not managed by gog, use --force to replace it (it is kept as %s.bak)", path, path)
+			}
+
+			if err := os.Rename(path, path+".bak"); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(path, []byte(script(hook, rel)), 0755); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// Uninstall removes the hook scripts written by Install and restores the scripts they
+// replaced. It returns the files removed.
+func Uninstall(dir string) ([]string, error) {
+	var removed []string
+
+	for _, hook := range Supported {
+		path, err := HookPath(dir, hook)
+		if err != nil {
+			return nil, err
+		}
+
+		if !Managed(path) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+		removed = append(removed, path)
+
+		if _, err := os.Stat(path + ".bak"); err == nil {
+			if err := os.Rename(path+".bak", path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// script is the hook script, git runs it from the root of the repository.
+func script(hook, dir string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s, edit %s and run gog hooks install instead of this file.
+if ! command -v gog >/dev/null 2>&1; then
+  echo "gog is not installed, install it to run the %s hook or skip it with --no-verify" >&2
+  exit 1
+fi
+exec gog hooks run %s -d %q -- "$@"
+`, managedMarker, File, hook, hook, dir)
+}
+
+// projectPath returns dir relative to the root of its repository.
+func projectPath(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", fmt.Errorf("❌ Failed to find the git repository: %w", err)
+	}
+
+	if prefix := strings.TrimSuffix(strings.TrimSpace(out), "/"); prefix != "" {
+		return prefix, nil
+	}
+
+	return ".", nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+
+	return string(out), err
+}
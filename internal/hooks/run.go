@@ -0,0 +1,314 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/migrations"
+	"github.com/nayla-finance/gog/internal/report"
+	"github.com/nayla-finance/gog/internal/swagger"
+)
+
+type (
+	RunOptions struct {
+		// All checks every tracked file instead of the staged ones, e.g. in CI
+		All bool
+		// Args are the arguments git passed to the hook
+		Args     []string
+		Reporter report.Reporter
+	}
+
+	// check is a built-in check, it returns the output explaining a failure.
+	check func(c *checkContext) ([]byte, error)
+
+	checkContext struct {
+		dir string
+		// files are the staged files, or every tracked file with RunOptions.All, relative to dir
+		files []string
+		all   bool
+		fix   bool
+	}
+)
+
+// checks are the built-in checks of the steps, by name.
+var checks = map[string]check{
+	"gofmt":      gofmtCheck,
+	"vet":        vetCheck,
+	"swag":       swagCheck,
+	"migrations": migrationsCheck,
+	"secrets":    secretsCheck,
+}
+
+// CheckNames returns the names of the built-in checks.
+func CheckNames() []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Run runs the steps of a hook of the project in dir, every step runs even when one fails.
+func Run(dir, hook string, opts RunOptions) error {
+	cfg, err := Load(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("❌ No %s in '%s', create it with gog hooks install", File, dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	steps, ok := cfg[hook]
+	if !ok {
+		return fmt.Errorf("❌ No %s steps in %s", hook, File)
+	}
+
+	files, err := changedFiles(dir, opts.All)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, s := range steps {
+		opts.Reporter.StepStarted("🪝", s.String())
+
+		var output []byte
+		if s.Check != "" {
+			output, err = checks[s.Check](&checkContext{dir: dir, files: files, all: opts.All, fix: s.Fix})
+		} else {
+			cmd := exec.Command("sh", append([]string{"-c", s.Run, "sh"}, opts.Args...)...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOG_HOOK="+hook)
+			output, err = cmd.CombinedOutput()
+		}
+
+		if err != nil {
+			failed++
+			opts.Reporter.StepFailed(s.String(), output, err)
+			continue
+		}
+
+		opts.Reporter.StepSucceeded(s.String())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("❌ %d of the %d %s steps failed", failed, len(steps), hook)
+	}
+
+	return nil
+}
+
+// changedFiles returns the staged files, or every tracked file with all, relative to dir.
+func changedFiles(dir string, all bool) ([]string, error) {
+	args := []string{"diff", "--cached", "--name-only", "--diff-filter=ACMR", "--relative"}
+	if all {
+		args = []string{"ls-files"}
+	}
+
+	out, err := git(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(out), nil
+}
+
+// read returns the content of a file as it would be committed: the staged content, or the
+// working tree with RunOptions.All.
+func (c *checkContext) read(f string) ([]byte, error) {
+	if c.all {
+		return os.ReadFile(filepath.Join(c.dir, f))
+	}
+
+	// :./<path> is the staged blob of a path relative to dir, :<path> is relative to the root
+	out, err := git(c.dir, "show", ":./"+f)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+func (c *checkContext) goFiles() []string {
+	var files []string
+	for _, f := range c.files {
+		if strings.HasSuffix(f, ".go") && !strings.HasPrefix(f, "vendor/") {
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
+
+// gofmtCheck lists the Go files staged unformatted, with fix they are formatted and restaged
+// unless they have unstaged changes that would be committed with them.
+func gofmtCheck(c *checkContext) ([]byte, error) {
+	unstaged := map[string]bool{}
+	if c.fix && !c.all {
+		out, err := git(c.dir, "diff", "--name-only", "--relative")
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range strings.Fields(out) {
+			unstaged[f] = true
+		}
+	}
+
+	var unformatted, restaged []string
+	var output bytes.Buffer
+	for _, f := range c.goFiles() {
+		src, err := c.read(f)
+		if err != nil {
+			return nil, err
+		}
+
+		formatted, err := format.Source(src)
+		if err != nil {
+			fmt.Fprintf(&output, "%s: %v\n", f, err)
+			unformatted = append(unformatted, f)
+			continue
+		}
+
+		if bytes.Equal(src, formatted) {
+			continue
+		}
+
+		if !c.fix || c.all || unstaged[f] {
+			unformatted = append(unformatted, f)
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(c.dir, f), formatted, 0644); err != nil {
+			return nil, err
+		}
+		restaged = append(restaged, f)
+	}
+
+	if len(restaged) > 0 {
+		if _, err := git(c.dir, append([]string{"add", "--"}, restaged...)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(unformatted) > 0 {
+		fmt.Fprintf(&output, "not formatted, run go fmt ./...:\n  %s\n", strings.Join(unformatted, "\n  "))
+		return output.Bytes(), errors.New("unformatted files")
+	}
+
+	return nil, nil
+}
+
+func vetCheck(c *checkContext) ([]byte, error) {
+	if !c.all && len(c.goFiles()) == 0 {
+		return nil, nil
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = c.dir
+
+	return cmd.CombinedOutput()
+}
+
+// swagCheck is gog swag check, it only runs when Go files changed.
+func swagCheck(c *checkContext) ([]byte, error) {
+	if !c.all && len(c.goFiles()) == 0 {
+		return nil, nil
+	}
+
+	// worker and job presets have no HTTP API
+	if _, err := os.Stat(filepath.Join(c.dir, swagger.MainFile)); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	stale, err := swagger.Stale(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stale) > 0 {
+		return []byte("out of date, run just swagger:\n  " + strings.Join(stale, "\n  ") + "\n"), errors.New("stale docs")
+	}
+
+	return nil, nil
+}
+
+func migrationsCheck(c *checkContext) ([]byte, error) {
+	_, problems, err := migrations.Lint(c.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(problems) > 0 {
+		return []byte(strings.Join(problems, "\n") + "\n"), fmt.Errorf("%d problems in %s", len(problems), migrations.Dir(c.dir))
+	}
+
+	return nil, nil
+}
+
+// secretPatterns match credentials that must never be committed, only their name is printed.
+var secretPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"NATS credentials", regexp.MustCompile(`-----BEGIN NATS USER JWT-----`)},
+	{"NATS user seed", regexp.MustCompile(`\bSU[A-Z2-7]{56}\b`)},
+	{"AWS access key", regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+}
+
+// committedSecretsFiles are the files of secrets/ committed by gog new.
+var committedSecretsFiles = map[string]bool{"README.md": true, ".gitignore": true, ".gitkeep": true}
+
+// secretsCheck fails when config.yaml or a file of secrets/ is committed, or a file contains
+// a private key or a token. The README.md, .gitignore and .gitkeep of secrets/ are committed
+// with the project.
+func secretsCheck(c *checkContext) ([]byte, error) {
+	var found []string
+	for _, f := range c.files {
+		base := path.Base(f)
+		switch {
+		case base == "config.yaml":
+			found = append(found, f+": the local config, only config.yaml.example is committed")
+			continue
+		case strings.HasPrefix(f, "secrets/") && !committedSecretsFiles[base]:
+			found = append(found, f+": secrets/ is mounted at runtime, its files are not committed")
+			continue
+		}
+
+		data, err := c.read(f)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			// deleted or binary
+			continue
+		}
+
+		for i, line := range strings.Split(string(data), "\n") {
+			for _, s := range secretPatterns {
+				if s.pattern.MatchString(line) {
+					found = append(found, fmt.Sprintf("%s:%d: %s", f, i+1, s.name))
+				}
+			}
+		}
+	}
+
+	if len(found) > 0 {
+		return []byte(strings.Join(found, "\n") + "\n"), fmt.Errorf("%d secrets would be committed", len(found))
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,204 @@
+// Package arch holds go/analysis analyzers enforcing the layering of generated projects:
+// handler -> service (through interfaces.*ServiceProvider) -> repository.
+//
+// The analyzers run through `gog lint arch` or as a vet tool:
+//
+//	go install github.com/nayla-finance/gog/cmd/gog-archlint@latest
+//	go vet -vettool=$(which gog-archlint) ./...
+package arch
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/source"
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	domainsDir    = "/internal/domains/"
+	interfacesPkg = "interfaces"
+	modelPkg      = "model"
+	fiberModule   = "github.com/gofiber/"
+	fiberCtx      = "*github.com/gofiber/fiber/v2.Ctx"
+)
+
+// Analyzers returns every architecture rule.
+func Analyzers() []*analysis.Analyzer {
+	return []*analysis.Analyzer{
+		HandlerDeps,
+		RepoExport,
+		ServiceFiber,
+		CrossRepo,
+		HandlerErrors,
+		UserContext,
+	}
+}
+
+// pass wraps analysis.Pass with the information shared by the rules.
+type pass struct {
+	*analysis.Pass
+	rule string
+	// module is the module path of the analyzed project (e.g. github.com/org/service)
+	module string
+	// domain is the domain package name (e.g. user) or "" when the package is not a domain
+	domain string
+}
+
+func newAnalyzer(name, doc string, run func(p *pass)) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: name,
+		Doc:  doc,
+		// the rules are syntactic enough to report useful results on packages with type errors
+		RunDespiteErrors: true,
+		Run: func(ap *analysis.Pass) (any, error) {
+			module, domain := domainOf(ap.Pkg.Path())
+			if domain == "" {
+				return nil, nil
+			}
+
+			run(&pass{Pass: ap, rule: name, module: module, domain: domain})
+			return nil, nil
+		},
+	}
+}
+
+// report reports a diagnostic unless the rule is disabled or excepted for the file.
+func (p *pass) report(node ast.Node, format string, args ...any) {
+	filename := p.Fset.Position(node.Pos()).Filename
+
+	proj, err := projectFor(filename)
+	if err == nil && !proj.config.Enabled(p.rule, proj.rel(filename)) {
+		return
+	}
+
+	p.Report(analysis.Diagnostic{
+		Pos:      node.Pos(),
+		End:      node.End(),
+		Category: p.rule,
+		Message:  p.rule + ": " + fmt.Sprintf(format, args...),
+	})
+}
+
+// filename returns the base name of the file containing f.
+func (p *pass) filename(f *ast.File) string {
+	return filepath.Base(p.Fset.Position(f.Package).Filename)
+}
+
+// isDomainPackage reports whether importPath is a domain package of the project other than
+// the shared interfaces and model packages.
+func (p *pass) isDomainPackage(importPath string) (string, bool) {
+	module, domain := domainOf(importPath)
+	return domain, domain != "" && module == p.module
+}
+
+// domainOf splits an import path like github.com/org/svc/internal/domains/user into the module
+// path and the domain name. Shared packages (interfaces, model) are not domains.
+func domainOf(importPath string) (string, string) {
+	i := strings.Index(importPath, domainsDir)
+	if i < 0 {
+		return "", ""
+	}
+
+	domain := strings.SplitN(importPath[i+len(domainsDir):], "/", 2)[0]
+	if domain == interfacesPkg || domain == modelPkg {
+		return "", ""
+	}
+
+	return importPath[:i], domain
+}
+
+// isTransportFile reports whether the file belongs to the HTTP layer of a domain.
+func isTransportFile(name string) bool {
+	for _, prefix := range []string{"handler", "middleware", "routes"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imports maps the names used in f to the import paths they refer to.
+func imports(f *ast.File) map[string]string {
+	out := map[string]string{}
+
+	for _, spec := range f.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := source.ImportName(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		out[name] = p
+	}
+
+	return out
+}
+
+// qualified resolves pkg.Name selector expressions to the import path and name.
+func qualified(imports map[string]string, expr ast.Expr) (string, string, bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+
+	importPath, ok := imports[x.Name]
+	if !ok {
+		return "", "", false
+	}
+
+	return importPath, sel.Sel.Name, true
+}
+
+// isFiberCtx reports whether expr is a *fiber.Ctx, using type information when available and
+// the declared parameter types of the enclosing function otherwise.
+func (p *pass) isFiberCtx(expr ast.Expr, ctxParams map[string]bool) bool {
+	if p.TypesInfo != nil {
+		if t := p.TypesInfo.TypeOf(expr); t != nil && t != types.Typ[types.Invalid] {
+			return types.TypeString(t, nil) == fiberCtx
+		}
+	}
+
+	id, ok := expr.(*ast.Ident)
+	return ok && ctxParams[id.Name]
+}
+
+// fiberCtxParams returns the names of the *fiber.Ctx parameters of a function type.
+func fiberCtxParams(imports map[string]string, ft *ast.FuncType) map[string]bool {
+	params := map[string]bool{}
+	if ft.Params == nil {
+		return params
+	}
+
+	for _, field := range ft.Params.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+
+		importPath, name, ok := qualified(imports, star.X)
+		if !ok || name != "Ctx" || !strings.HasPrefix(importPath, fiberModule+"fiber") {
+			continue
+		}
+
+		for _, n := range field.Names {
+			params[n.Name] = true
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,158 @@
+package arch
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ConfigFile is the project relative path of the lint configuration.
+const ConfigFile = ".gog/lint.yaml"
+
+type (
+	// Config configures the architecture rules of a project, e.g.
+	//
+	//	arch:
+	//	  disabled: [handlererrors]
+	//	  exceptions:
+	//	    handlerdeps:
+	//	      - internal/domains/health/**
+	Config struct {
+		Arch RulesConfig `yaml:"arch"`
+	}
+
+	RulesConfig struct {
+		// Disabled rules are not reported at all
+		Disabled []string `yaml:"disabled"`
+		// Exceptions maps a rule (or "*" for every rule) to project relative file globs it does not apply to
+		Exceptions map[string][]string `yaml:"exceptions"`
+	}
+
+	// project is a module root and the configuration loaded from it.
+	project struct {
+		root   string
+		config *Config
+	}
+)
+
+var (
+	mu sync.Mutex
+	// configFile overrides the per project configuration, see SetConfigFile
+	configFile string
+	projects   = map[string]*project{}
+)
+
+// SetConfigFile makes every analyzer use the given configuration file instead of
+// looking up .gog/lint.yaml in the module of the analyzed files.
+func SetConfigFile(file string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	configFile = file
+	projects = map[string]*project{}
+}
+
+// LoadConfig reads a lint configuration file, a missing file is an empty configuration.
+func LoadConfig(file string) (*Config, error) {
+	c := &Config{}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", file, err)
+	}
+
+	return c, nil
+}
+
+// Enabled reports whether rule should be reported for the project relative file.
+func (c *Config) Enabled(rule, file string) bool {
+	for _, d := range c.Arch.Disabled {
+		if d == rule {
+			return false
+		}
+	}
+
+	for _, key := range []string{rule, "*"} {
+		for _, pattern := range c.Arch.Exceptions[key] {
+			if matchPath(pattern, file) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// projectFor returns the module root containing filename and its configuration.
+func projectFor(filename string) (*project, error) {
+	dir := filepath.Dir(filename)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if p, ok := projects[dir]; ok {
+		return p, nil
+	}
+
+	root := dir
+	for {
+		if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(root)
+		if parent == root {
+			root = dir
+			break
+		}
+		root = parent
+	}
+
+	file := configFile
+	if file == "" {
+		file = filepath.Join(root, ConfigFile)
+	}
+
+	c, err := LoadConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &project{root: root, config: c}
+	projects[dir] = p
+
+	return p, nil
+}
+
+// rel returns filename relative to the project root using forward slashes.
+func (p *project) rel(filename string) string {
+	rel, err := filepath.Rel(p.root, filename)
+	if err != nil {
+		return filepath.ToSlash(filename)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// matchPath matches a slash separated path against a glob where a trailing /** matches
+// everything below a directory.
+func matchPath(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return name == prefix || strings.HasPrefix(name, prefix+"/")
+	}
+
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
@@ -0,0 +1,233 @@
+package arch
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+var (
+	HandlerDeps = newAnalyzer(
+		"handlerdeps",
+		"handlers only depend on services through interfaces.*ServiceProvider, never on repositories or the database",
+		runHandlerDeps,
+	)
+
+	RepoExport = newAnalyzer(
+		"repoexport",
+		"repository methods are unexported so only the service of the same domain can call them",
+		runRepoExport,
+	)
+
+	ServiceFiber = newAnalyzer(
+		"servicefiber",
+		"only handlers and middlewares import fiber, services and repositories stay transport agnostic",
+		runServiceFiber,
+	)
+
+	CrossRepo = newAnalyzer(
+		"crossrepo",
+		"a domain never uses the repository of another domain, it calls that domain's service instead",
+		runCrossRepo,
+	)
+
+	HandlerErrors = newAnalyzer(
+		"handlererrors",
+		"handlers return errors created through errors.ErrorProvider instead of fmt.Errorf or errors.New",
+		runHandlerErrors,
+	)
+
+	UserContext = newAnalyzer(
+		"usercontext",
+		"handlers pass c.UserContext() to services instead of the fasthttp c.Context()",
+		runUserContext,
+	)
+)
+
+func runHandlerDeps(p *pass) {
+	interfacesPath := p.module + domainsDir + interfacesPkg
+
+	for _, f := range p.Files {
+		imports := imports(f)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			name := strings.ToLower(ts.Name.Name)
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !strings.Contains(name, "handler") || !strings.HasSuffix(name, "dependencies") {
+				return false
+			}
+
+			for _, field := range it.Methods.List {
+				if len(field.Names) > 0 {
+					continue
+				}
+
+				switch t := field.Type.(type) {
+				case *ast.Ident:
+					if strings.HasSuffix(t.Name, "RepositoryProvider") {
+						p.report(field, "%s depends on %s, handlers must go through a service", ts.Name.Name, t.Name)
+					} else if strings.HasSuffix(t.Name, "ServiceProvider") && t.Name != "ServiceProvider" {
+						p.report(field, "%s depends on %s, use interfaces.%s instead", ts.Name.Name, t.Name, t.Name)
+					}
+				case *ast.SelectorExpr:
+					importPath, sel, ok := qualified(imports, t)
+					if !ok {
+						continue
+					}
+
+					switch {
+					case strings.HasSuffix(sel, "RepositoryProvider") || sel == "DBProvider":
+						p.report(field, "%s depends on %s.%s, handlers must go through a service", ts.Name.Name, types.ExprString(t.X), sel)
+					case strings.HasSuffix(sel, "ServiceProvider") && importPath != interfacesPath && strings.HasPrefix(importPath, p.module+"/"):
+						p.report(field, "%s depends on %s.%s, use interfaces.%s instead", ts.Name.Name, types.ExprString(t.X), sel, sel)
+					}
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+func runRepoExport(p *pass) {
+	for _, f := range p.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			it, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || ts.Name.Name != "Repository" {
+				return false
+			}
+
+			for _, field := range it.Methods.List {
+				for _, name := range field.Names {
+					if name.IsExported() {
+						p.report(name, "repository method %s is exported, unexport it so only the %s service can call it", name.Name, p.domain)
+					}
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+func runServiceFiber(p *pass) {
+	for _, f := range p.Files {
+		if isTransportFile(p.filename(f)) {
+			continue
+		}
+
+		for _, spec := range f.Imports {
+			if strings.HasPrefix(strings.Trim(spec.Path.Value, `"`), fiberModule) {
+				p.report(spec, "%s imports %s, only handlers and middlewares may depend on fiber", p.filename(f), spec.Path.Value)
+			}
+		}
+	}
+}
+
+func runCrossRepo(p *pass) {
+	for _, f := range p.Files {
+		imports := imports(f)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			importPath, name, ok := qualified(imports, sel)
+			if !ok || !strings.Contains(name, "Repository") {
+				return true
+			}
+
+			if domain, ok := p.isDomainPackage(importPath); ok && domain != p.domain {
+				p.report(sel, "%s uses %s.%s, call the %s service through interfaces instead", p.domain, types.ExprString(sel.X), name, domain)
+			}
+
+			return true
+		})
+	}
+}
+
+func runHandlerErrors(p *pass) {
+	for _, f := range p.Files {
+		if !isTransportFile(p.filename(f)) {
+			continue
+		}
+
+		imports := imports(f)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			importPath, name, ok := qualified(imports, call.Fun)
+			if !ok {
+				return true
+			}
+
+			if (importPath == "fmt" && name == "Errorf") || (importPath == "errors" && name == "New") {
+				p.report(call, "return h.d.NewError(errors.Err..., message) instead of %s.%s so the error handler can map it to a status code", importPath, name)
+			}
+
+			return true
+		})
+	}
+}
+
+func runUserContext(p *pass) {
+	for _, f := range p.Files {
+		imports := imports(f)
+
+		var check func(body ast.Node, params map[string]bool)
+		check = func(body ast.Node, params map[string]bool) {
+			ast.Inspect(body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.FuncLit:
+					inner := fiberCtxParams(imports, n.Type)
+					for k, v := range params {
+						if _, shadowed := inner[k]; !shadowed {
+							inner[k] = v
+						}
+					}
+
+					check(n.Body, inner)
+					return false
+				case *ast.CallExpr:
+					for _, arg := range n.Args {
+						inner, ok := arg.(*ast.CallExpr)
+						if !ok || len(inner.Args) != 0 {
+							continue
+						}
+
+						sel, ok := inner.Fun.(*ast.SelectorExpr)
+						if !ok || sel.Sel.Name != "Context" || !p.isFiberCtx(sel.X, params) {
+							continue
+						}
+
+						p.report(inner, "pass %s.UserContext() instead of %s.Context(), the fasthttp context does not carry the request span and values", types.ExprString(sel.X), types.ExprString(sel.X))
+					}
+				}
+
+				return true
+			})
+		}
+
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+				check(fd.Body, fiberCtxParams(imports, fd.Type))
+			}
+		}
+	}
+}
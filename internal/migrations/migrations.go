@@ -0,0 +1,159 @@
+// Package migrations reads the goose migrations of a project.
+package migrations
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultDir is the migrations directory when database.migrations_dir is not set.
+const DefaultDir = "migrations"
+
+// FileName matches the goose file names, e.g. 20241108133703_init.sql
+var FileName = regexp.MustCompile(`^(\d+)_(\w+)\.(sql|go)$`)
+
+// Dir returns database.migrations_dir of the config of the project in dir, relative to it.
+func Dir(dir string) string {
+	for _, file := range []string{"config.yaml", "config.yaml.example"} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			continue
+		}
+
+		var cfg struct {
+			Database struct {
+				MigrationsDir string `yaml:"migrations_dir"`
+			} `yaml:"database"`
+		}
+		if err := yaml.Unmarshal(data, &cfg); err == nil && cfg.Database.MigrationsDir != "" {
+			return cfg.Database.MigrationsDir
+		}
+	}
+
+	return DefaultDir
+}
+
+// Lint checks the file names, versions and goose annotations of the migrations of the project
+// in dir. It returns the number of migrations and the problems found, prefixed with the file
+// name.
+func Lint(dir string) (int, []string, error) {
+	migrationsDir := Dir(dir)
+
+	entries, err := os.ReadDir(filepath.Join(dir, migrationsDir))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var problems []string
+	versions := map[string]string{}
+	count := 0
+
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		m := FileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			problems = append(problems, fmt.Sprintf("%s: not a <version>_<name>.sql file name, goose ignores it", e.Name()))
+			continue
+		}
+		count++
+
+		if other, ok := versions[m[1]]; ok {
+			problems = append(problems, fmt.Sprintf("%s: version %s is also used by %s", e.Name(), m[1], other))
+		}
+		versions[m[1]] = e.Name()
+
+		if m[3] != "sql" {
+			continue
+		}
+
+		for _, p := range parse(filepath.Join(dir, migrationsDir, e.Name())) {
+			problems = append(problems, e.Name()+": "+p)
+		}
+	}
+
+	sort.Strings(problems)
+
+	return count, problems, nil
+}
+
+// parse checks the goose annotations of a SQL migration.
+func parse(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	defer f.Close()
+
+	var problems []string
+	var section string
+	up, down, inStatement, statementLine := 0, 0, false, 0
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "-- +goose ") {
+			continue
+		}
+
+		switch annotation := strings.Fields(strings.TrimPrefix(text, "-- +goose "))[0]; annotation {
+		case "Up", "Down":
+			if inStatement {
+				problems = append(problems, fmt.Sprintf("line %d: +goose %s inside the statement started line %d", line, annotation, statementLine))
+			}
+			if annotation == "Up" {
+				up++
+				if down > 0 {
+					problems = append(problems, fmt.Sprintf("line %d: +goose Up after +goose Down", line))
+				}
+			} else {
+				down++
+			}
+			section = annotation
+		case "StatementBegin":
+			if section == "" {
+				problems = append(problems, fmt.Sprintf("line %d: StatementBegin before +goose Up", line))
+			}
+			if inStatement {
+				problems = append(problems, fmt.Sprintf("line %d: StatementBegin inside the statement started line %d", line, statementLine))
+			}
+			inStatement, statementLine = true, line
+		case "StatementEnd":
+			if !inStatement {
+				problems = append(problems, fmt.Sprintf("line %d: StatementEnd without StatementBegin", line))
+			}
+			inStatement = false
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return append(problems, err.Error())
+	}
+
+	if inStatement {
+		problems = append(problems, fmt.Sprintf("line %d: StatementBegin without StatementEnd", statementLine))
+	}
+
+	switch {
+	case up == 0:
+		problems = append(problems, "no +goose Up annotation")
+	case up > 1:
+		problems = append(problems, "more than one +goose Up annotation")
+	}
+
+	if down > 1 {
+		problems = append(problems, "more than one +goose Down annotation")
+	}
+
+	return problems
+}
@@ -0,0 +1,458 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// Schema is the database schema left by the Up sections of the migrations.
+	Schema struct {
+		Tables map[string]*Table
+		// Warnings are the migrations and statements that could not be replayed
+		Warnings []string
+	}
+
+	Table struct {
+		Name    string
+		Columns []*Column
+	}
+
+	Column struct {
+		Name string
+		// Type is the SQL type as written in the migration, e.g. VARCHAR(255)
+		Type    string
+		NotNull bool
+	}
+)
+
+// Column returns the column of t with the given name.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Replay reads the tables and columns created by the SQL migrations of the project in dir,
+// in version order. The Go migrations and the statements other than CREATE, ALTER and DROP
+// TABLE are skipped.
+func Replay(dir string) (*Schema, error) {
+	migrationsDir := filepath.Join(dir, Dir(dir))
+
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("❌ Failed to read migrations: %w", err)
+	}
+
+	type migration struct {
+		version, file string
+	}
+	var files []migration
+	for _, e := range entries {
+		if m := FileName.FindStringSubmatch(e.Name()); m != nil && !e.IsDir() {
+			files = append(files, migration{m[1], e.Name()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if len(files[i].version) != len(files[j].version) {
+			return len(files[i].version) < len(files[j].version)
+		}
+		return files[i].version < files[j].version
+	})
+
+	s := &Schema{Tables: map[string]*Table{}}
+	for _, f := range files {
+		if strings.HasSuffix(f.file, ".go") {
+			s.Warnings = append(s.Warnings, fmt.Sprintf("%s: Go migrations are not replayed", f.file))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(migrationsDir, f.file))
+		if err != nil {
+			return nil, fmt.Errorf("❌ Failed to read migration: %w", err)
+		}
+
+		for _, stmt := range splitStatements(upSection(string(data))) {
+			if err := s.apply(tokenize(stmt)); err != nil {
+				s.Warnings = append(s.Warnings, fmt.Sprintf("%s: %v", f.file, err))
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// upSection returns the SQL between +goose Up and +goose Down, without the comments.
+func upSection(src string) string {
+	var b strings.Builder
+	up := false
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Up"):
+			up = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			up = false
+		}
+
+		if up {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// splitStatements splits src on the semicolons outside of strings, quoted identifiers, dollar
+// quoted bodies and comments. The comments are removed.
+func splitStatements(src string) []string {
+	var stmts []string
+	var b strings.Builder
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end - 1
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 3
+			}
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				end++
+			}
+			b.WriteString(src[i:min(end+1, len(src))])
+			i = end
+		case c == '$':
+			// dollar quoted bodies of functions and DO blocks, e.g. $$ ... $$ or $body$ ... $body$
+			end := strings.IndexByte(src[i+1:], '$')
+			if end < 0 || strings.ContainsAny(src[i+1:i+1+end], " \n\t;()") {
+				b.WriteByte(c)
+				continue
+			}
+			tag := src[i : i+end+2]
+			body := strings.Index(src[i+len(tag):], tag)
+			if body < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteString(src[i : i+len(tag)+body+len(tag)])
+			i += len(tag) + body + len(tag) - 1
+		case c == ';':
+			if s := strings.TrimSpace(b.String()); s != "" {
+				stmts = append(stmts, s)
+			}
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if s := strings.TrimSpace(b.String()); s != "" {
+		stmts = append(stmts, s)
+	}
+
+	return stmts
+}
+
+// tokenize splits a statement into words, quoted identifiers, strings and punctuation.
+func tokenize(stmt string) []string {
+	var tokens []string
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(stmt) && stmt[end] != c {
+				end++
+			}
+			tokens = append(tokens, stmt[i:min(end+1, len(stmt))])
+			i = end + 1
+		default:
+			end := i
+			for end < len(stmt) && !strings.ContainsRune(" \t\n\r(),'\"", rune(stmt[end])) {
+				end++
+			}
+			tokens = append(tokens, stmt[i:end])
+			i = end
+		}
+	}
+
+	return tokens
+}
+
+// parser walks the tokens of a statement.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return strings.ToUpper(p.tokens[p.pos])
+	}
+
+	return ""
+}
+
+func (p *parser) next() string {
+	if p.pos < len(p.tokens) {
+		p.pos++
+		return p.tokens[p.pos-1]
+	}
+
+	return ""
+}
+
+// accept consumes the given keywords when they are next.
+func (p *parser) accept(keywords ...string) bool {
+	for i, k := range keywords {
+		if p.pos+i >= len(p.tokens) || strings.ToUpper(p.tokens[p.pos+i]) != k {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+
+	return true
+}
+
+// name consumes an identifier, without its schema and quotes.
+func (p *parser) name() string {
+	name := p.next()
+	if i := strings.LastIndexByte(name, '.'); i >= 0 && !strings.HasPrefix(name, `"`) {
+		name = name[i+1:]
+	}
+
+	return unquote(name)
+}
+
+// group consumes the tokens up to the comma or closing parenthesis ending the current
+// element, the nested parentheses included.
+func (p *parser) group() []string {
+	start, depth := p.pos, 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		switch p.tokens[p.pos] {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				return p.tokens[start:p.pos]
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				return p.tokens[start:p.pos]
+			}
+		}
+	}
+
+	return p.tokens[start:]
+}
+
+func (s *Schema) apply(tokens []string) error {
+	p := &parser{tokens: tokens}
+
+	switch {
+	case p.accept("CREATE"):
+		for p.accept("UNLOGGED") || p.accept("TEMP") || p.accept("TEMPORARY") {
+		}
+		if !p.accept("TABLE") {
+			return nil
+		}
+		p.accept("IF", "NOT", "EXISTS")
+		return s.createTable(p)
+	case p.accept("ALTER", "TABLE"):
+		p.accept("IF", "EXISTS")
+		p.accept("ONLY")
+		return s.alterTable(p)
+	case p.accept("DROP", "TABLE"):
+		p.accept("IF", "EXISTS")
+		for {
+			delete(s.Tables, p.name())
+			if !p.accept(",") {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// constraintKeywords start the table constraints of a CREATE TABLE.
+var constraintKeywords = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true, "EXCLUDE": true, "LIKE": true}
+
+func (s *Schema) createTable(p *parser) error {
+	t := &Table{Name: p.name()}
+	if !p.accept("(") {
+		// CREATE TABLE ... AS SELECT
+		return fmt.Errorf("CREATE TABLE %s without columns is not replayed", t.Name)
+	}
+
+	for p.peek() != "" && p.peek() != ")" {
+		element := p.group()
+		if len(element) > 0 && !constraintKeywords[strings.ToUpper(element[0])] {
+			t.Columns = append(t.Columns, column(element))
+		}
+		p.accept(",")
+	}
+	s.Tables[t.Name] = t
+
+	return nil
+}
+
+func (s *Schema) alterTable(p *parser) error {
+	name := p.name()
+
+	if p.accept("RENAME", "TO") {
+		if t, ok := s.Tables[name]; ok {
+			t.Name = p.name()
+			delete(s.Tables, name)
+			s.Tables[t.Name] = t
+		}
+		return nil
+	}
+
+	t, ok := s.Tables[name]
+	if !ok {
+		return fmt.Errorf("ALTER TABLE of the unknown table %s", name)
+	}
+
+	for p.peek() != "" {
+		action := &parser{tokens: p.group()}
+		p.accept(",")
+
+		switch {
+		case action.accept("ADD"):
+			if constraintKeywords[action.peek()] {
+				continue
+			}
+			action.accept("COLUMN")
+			action.accept("IF", "NOT", "EXISTS")
+			c := column(action.tokens[action.pos:])
+			if t.Column(c.Name) == nil {
+				t.Columns = append(t.Columns, c)
+			}
+		case action.accept("DROP"):
+			if action.peek() == "CONSTRAINT" {
+				continue
+			}
+			action.accept("COLUMN")
+			action.accept("IF", "EXISTS")
+			t.Columns = removeColumn(t.Columns, action.name())
+		case action.accept("ALTER"):
+			action.accept("COLUMN")
+			c := t.Column(action.name())
+			if c == nil {
+				continue
+			}
+			switch {
+			case action.accept("TYPE"), action.accept("SET", "DATA", "TYPE"):
+				c.Type = columnType(action.tokens[action.pos:])
+			case action.accept("SET", "NOT", "NULL"):
+				c.NotNull = true
+			case action.accept("DROP", "NOT", "NULL"):
+				c.NotNull = false
+			}
+		case action.accept("RENAME"):
+			if action.accept("TO") {
+				t.Name = action.name()
+				delete(s.Tables, name)
+				s.Tables[t.Name] = t
+				continue
+			}
+			action.accept("COLUMN")
+			c := t.Column(action.name())
+			if c != nil && action.accept("TO") {
+				c.Name = action.name()
+			}
+		}
+	}
+
+	return nil
+}
+
+// columnKeywords end the type of a column definition.
+var columnKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true, "REFERENCES": true,
+	"CHECK": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true, "USING": true,
+}
+
+// column parses a column definition, e.g. email VARCHAR(255) NOT NULL UNIQUE.
+func column(tokens []string) *Column {
+	p := &parser{tokens: tokens}
+	c := &Column{Name: p.name()}
+	c.Type = columnType(tokens[p.pos:])
+
+	rest := strings.ToUpper(strings.Join(tokens[p.pos:], " "))
+	c.NotNull = strings.Contains(rest, "NOT NULL") || strings.Contains(rest, "PRIMARY KEY") ||
+		strings.HasPrefix(c.Type, "SERIAL") || strings.HasPrefix(c.Type, "BIGSERIAL")
+
+	return c
+}
+
+// columnType joins the tokens of a type up to its constraints, e.g. NUMERIC(10,2).
+func columnType(tokens []string) string {
+	var b strings.Builder
+	depth := 0
+	for _, t := range tokens {
+		if depth == 0 && columnKeywords[strings.ToUpper(t)] {
+			break
+		}
+
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+
+		if b.Len() > 0 && t != "(" && t != ")" && t != "," && !strings.HasSuffix(b.String(), "(") && !strings.HasSuffix(b.String(), ",") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t)
+	}
+
+	return strings.ToUpper(b.String())
+}
+
+func removeColumn(columns []*Column, name string) []*Column {
+	var kept []*Column
+	for _, c := range columns {
+		if c.Name != name {
+			kept = append(kept, c)
+		}
+	}
+
+	return kept
+}
+
+func unquote(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return name[1 : len(name)-1]
+	}
+
+	return strings.ToLower(name)
+}
@@ -0,0 +1,278 @@
+// Package openapi reads the subset of OpenAPI 3 documents (YAML or JSON) needed to generate
+// handlers: paths, operations, parameters, JSON bodies and component schemas.
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Methods are the operation methods of a path item in the order they are generated.
+var Methods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+type (
+	Spec struct {
+		OpenAPI    string               `yaml:"openapi"`
+		Paths      map[string]*PathItem `yaml:"paths"`
+		Components Components           `yaml:"components"`
+	}
+
+	Components struct {
+		Schemas       map[string]*Schema      `yaml:"schemas"`
+		Parameters    map[string]*Parameter   `yaml:"parameters"`
+		RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+		Responses     map[string]*Response    `yaml:"responses"`
+	}
+
+	PathItem struct {
+		Parameters []*Parameter `yaml:"parameters"`
+		Get        *Operation   `yaml:"get"`
+		Post       *Operation   `yaml:"post"`
+		Put        *Operation   `yaml:"put"`
+		Patch      *Operation   `yaml:"patch"`
+		Delete     *Operation   `yaml:"delete"`
+		Head       *Operation   `yaml:"head"`
+		Options    *Operation   `yaml:"options"`
+	}
+
+	Operation struct {
+		OperationID string               `yaml:"operationId"`
+		Summary     string               `yaml:"summary"`
+		Description string               `yaml:"description"`
+		Tags        []string             `yaml:"tags"`
+		Parameters  []*Parameter         `yaml:"parameters"`
+		RequestBody *RequestBody         `yaml:"requestBody"`
+		Responses   map[string]*Response `yaml:"responses"`
+	}
+
+	Parameter struct {
+		Ref         string  `yaml:"$ref"`
+		Name        string  `yaml:"name"`
+		In          string  `yaml:"in"`
+		Description string  `yaml:"description"`
+		Required    bool    `yaml:"required"`
+		Schema      *Schema `yaml:"schema"`
+	}
+
+	RequestBody struct {
+		Ref         string                `yaml:"$ref"`
+		Description string                `yaml:"description"`
+		Required    bool                  `yaml:"required"`
+		Content     map[string]*MediaType `yaml:"content"`
+	}
+
+	Response struct {
+		Ref         string                `yaml:"$ref"`
+		Description string                `yaml:"description"`
+		Content     map[string]*MediaType `yaml:"content"`
+	}
+
+	// Type is the schema type, OpenAPI 3.1 lists like [string, "null"] are reduced to the
+	// first non null type.
+	Type string
+
+	// Properties keeps the order of the properties of the document.
+	Properties struct {
+		Keys   []string
+		Values map[string]*Schema
+	}
+
+	MediaType struct {
+		Schema *Schema `yaml:"schema"`
+	}
+
+	Schema struct {
+		Ref                  string     `yaml:"$ref"`
+		Type                 Type       `yaml:"type"`
+		Format               string     `yaml:"format"`
+		Description          string     `yaml:"description"`
+		Properties           Properties `yaml:"properties"`
+		Required             []string   `yaml:"required"`
+		Items                *Schema    `yaml:"items"`
+		AdditionalProperties *Schema    `yaml:"additionalProperties"`
+		AllOf                []*Schema  `yaml:"allOf"`
+		OneOf                []*Schema  `yaml:"oneOf"`
+		AnyOf                []*Schema  `yaml:"anyOf"`
+		Enum                 []any      `yaml:"enum"`
+		Nullable             bool       `yaml:"nullable"`
+		MinLength            *int       `yaml:"minLength"`
+		MaxLength            *int       `yaml:"maxLength"`
+		Pattern              string     `yaml:"pattern"`
+		Minimum              *float64   `yaml:"minimum"`
+		Maximum              *float64   `yaml:"maximum"`
+		// ExclusiveMinimum and ExclusiveMaximum are booleans in OpenAPI 3.0 and numbers in 3.1
+		ExclusiveMinimum any  `yaml:"exclusiveMinimum"`
+		ExclusiveMaximum any  `yaml:"exclusiveMaximum"`
+		MinItems         *int `yaml:"minItems"`
+		MaxItems         *int `yaml:"maxItems"`
+	}
+)
+
+// Load reads a YAML or JSON OpenAPI 3 document.
+func Load(file string) (*Spec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read spec: %w", err)
+	}
+
+	s := &Spec{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", file, err)
+	}
+
+	if !strings.HasPrefix(s.OpenAPI, "3.") {
+		return nil, fmt.Errorf("❌ %s is not an OpenAPI 3 document (openapi: %q)", file, s.OpenAPI)
+	}
+
+	return s, nil
+}
+
+func (p *Properties) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", n.Line)
+	}
+
+	p.Values = map[string]*Schema{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i].Value
+
+		s := &Schema{}
+		if err := n.Content[i+1].Decode(s); err != nil {
+			return err
+		}
+
+		p.Keys = append(p.Keys, key)
+		p.Values[key] = s
+	}
+
+	return nil
+}
+
+func (t *Type) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		*t = Type(n.Value)
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			if c.Value != "null" {
+				*t = Type(c.Value)
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: type must be a string or a list", n.Line)
+	}
+
+	return nil
+}
+
+// Bound returns the minimum or maximum of a schema and whether it is exclusive.
+func Bound(value *float64, exclusive any) (*float64, bool) {
+	switch e := exclusive.(type) {
+	case bool:
+		return value, e
+	case int:
+		f := float64(e)
+		return &f, true
+	case float64:
+		return &e, true
+	}
+
+	return value, false
+}
+
+// Operations returns the operations of a path item by lower case method.
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		"get": p.Get, "post": p.Post, "put": p.Put, "patch": p.Patch,
+		"delete": p.Delete, "head": p.Head, "options": p.Options,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+
+	return ops
+}
+
+// SortedPaths returns the paths in a stable order.
+func (s *Spec) SortedPaths() []string {
+	paths := make([]string, 0, len(s.Paths))
+	for p := range s.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// RefName returns the component name of a local reference like #/components/schemas/Pet.
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func (s *Spec) Parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+
+	if resolved, ok := s.Components.Parameters[RefName(p.Ref)]; ok && strings.HasPrefix(p.Ref, "#/components/parameters/") {
+		return resolved, nil
+	}
+
+	return nil, fmt.Errorf("❌ Unresolved reference %s", p.Ref)
+}
+
+func (s *Spec) RequestBody(b *RequestBody) (*RequestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+
+	if resolved, ok := s.Components.RequestBodies[RefName(b.Ref)]; ok && strings.HasPrefix(b.Ref, "#/components/requestBodies/") {
+		return resolved, nil
+	}
+
+	return nil, fmt.Errorf("❌ Unresolved reference %s", b.Ref)
+}
+
+func (s *Spec) Response(r *Response) (*Response, error) {
+	if r.Ref == "" {
+		return r, nil
+	}
+
+	if resolved, ok := s.Components.Responses[RefName(r.Ref)]; ok && strings.HasPrefix(r.Ref, "#/components/responses/") {
+		return resolved, nil
+	}
+
+	return nil, fmt.Errorf("❌ Unresolved reference %s", r.Ref)
+}
+
+// Schema resolves a schema reference to the component schema.
+func (s *Spec) Schema(schema *Schema) (*Schema, error) {
+	for seen := 0; schema.Ref != ""; seen++ {
+		resolved, ok := s.Components.Schemas[RefName(schema.Ref)]
+		if !ok || !strings.HasPrefix(schema.Ref, "#/components/schemas/") || seen > len(s.Components.Schemas) {
+			return nil, fmt.Errorf("❌ Unresolved reference %s", schema.Ref)
+		}
+
+		schema = resolved
+	}
+
+	return schema, nil
+}
+
+// JSON returns the application/json schema of a body, or nil when there is none.
+func JSON(content map[string]*MediaType) *Schema {
+	for _, ct := range []string{"application/json", "application/problem+json", "*/*"} {
+		if mt, ok := content[ct]; ok && mt.Schema != nil {
+			return mt.Schema
+		}
+	}
+
+	return nil
+}
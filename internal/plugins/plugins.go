@@ -0,0 +1,207 @@
+// Package plugins discovers and runs the gog-<name> executables extending gog.
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/nayla-finance/gog"
+	"github.com/nayla-finance/gog/internal/project"
+	"github.com/nayla-finance/gog/plugin"
+	"golang.org/x/mod/modfile"
+)
+
+const prefix = "gog-"
+
+// tools are the executables shipped with gog that share the prefix but are not plugins, e.g.
+// the go vet tool gog-archlint.
+var tools = map[string]bool{
+	"archlint": true,
+}
+
+type Plugin struct {
+	Name string
+	Path string
+	// Shadowed are the executables with the same name found later in the search order
+	Shadowed []string
+}
+
+// Dirs returns the directories searched for plugins in order: $XDG_CONFIG_HOME/gog/plugins
+// (~/.config/gog/plugins by default) then the PATH.
+func Dirs() []string {
+	var dirs []string
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, "gog", "plugins"))
+	}
+
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Discover returns the plugins sorted by name, the first executable of a name in Dirs wins.
+func Discover() []*Plugin {
+	byName := map[string]*Plugin{}
+	seen := map[string]bool{}
+
+	for _, dir := range Dirs() {
+		if dir == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			if p, ok := byName[name]; ok {
+				p.Shadowed = append(p.Shadowed, path)
+				continue
+			}
+
+			byName[name] = &Plugin{Name: name, Path: path}
+		}
+	}
+
+	plugins := make([]*Plugin, 0, len(byName))
+	for _, p := range byName {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	return plugins
+}
+
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(file))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+
+	name := strings.TrimPrefix(file, prefix)
+	if name == file || name == "" || strings.ContainsAny(name, " .") || tools[name] {
+		return "", false
+	}
+
+	return name, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+// NewContext returns the context of the project containing dir.
+func NewContext(dir string) (*plugin.Context, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &plugin.Context{GogVersion: gog.Version, WorkDir: abs}
+
+	root := findModuleRoot(abs)
+	if root == "" {
+		return ctx, nil
+	}
+	ctx.ProjectDir = root
+
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	ctx.Module = modfile.ModulePath(data)
+
+	lock, err := project.ReadLock(root)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if lock != nil {
+		ctx.Template = &plugin.Template{
+			GogVersion:   lock.GogVersion,
+			Hash:         lock.TemplateHash,
+			Name:         lock.Name,
+			Module:       lock.Module,
+			CreatedAt:    lock.CreatedAt,
+			SharedModule: lock.SharedModule,
+			Excluded:     lock.Excluded,
+		}
+	}
+
+	return ctx, nil
+}
+
+func findModuleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Run runs a plugin with the context in its environment, its exit status is returned as an
+// *exec.ExitError.
+func Run(p *Plugin, ctx *plugin.Context, args []string) error {
+	data, err := json.Marshal(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(),
+		plugin.EnvContext+"="+string(data),
+		plugin.EnvVersion+"="+ctx.GogVersion,
+		plugin.EnvProjectDir+"="+ctx.ProjectDir,
+		plugin.EnvModule+"="+ctx.Module,
+	)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr
+		}
+
+		return fmt.Errorf("❌ Failed to run plugin %s: %w", p.Name, err)
+	}
+
+	return nil
+}
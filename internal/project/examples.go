@@ -0,0 +1,49 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/nayla-finance/gog/internal/examples"
+	"github.com/nayla-finance/gog/internal/swagger"
+)
+
+// initMigration is the init migration without the example tables.
+var initMigration = path.Join(presetsDir, "common", "migrations")
+
+// removeExamples removes the example domains from the copied files, the init migration is
+// replaced since nothing has applied it yet.
+func (p *Project) removeExamples(module string, replaceFuncs []func(data []byte) []byte) error {
+	p.reporter.Info("🧹", "Removing example domains...")
+
+	result, err := examples.Remove(p.dir, examples.Options{Module: module})
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range result.Removed {
+		p.excluded[filepath.FromSlash(rel)] = true
+		p.reporter.FileRemoved(filepath.Join(p.dir, rel))
+	}
+
+	for _, rel := range result.Edited {
+		p.reporter.FileUpdated(filepath.Join(p.dir, rel))
+	}
+
+	return CopyTemplate(p.template, initMigration, filepath.Join(p.dir, "migrations"), replaceFuncs, nil, p.reporter)
+}
+
+// regenerateDocs regenerates the swagger docs without the example routes, it only warns on
+// failure since the project is usable without them.
+func (p *Project) regenerateDocs() {
+	const step = "Regenerating swagger docs"
+	p.reporter.StepStarted("📚", step)
+
+	if err := swagger.Generate(p.dir, filepath.Join(p.dir, swagger.DocsDir)); err != nil {
+		p.reporter.Warn(fmt.Sprintf("Failed to regenerate swagger docs, run just swagger: %v", err))
+		return
+	}
+
+	p.reporter.StepSucceeded(step)
+}
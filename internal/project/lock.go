@@ -0,0 +1,122 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nayla-finance/gog"
+	"go.yaml.in/yaml/v3"
+)
+
+// LockFile records the template a project was generated from.
+const LockFile = ".gog/template.lock"
+
+// Lock is the content of the lock file.
+type Lock struct {
+	GogVersion string `yaml:"gog_version" json:"gog_version"`
+	// TemplateHash is the sha256 of the template and preset files before the placeholders are replaced
+	TemplateHash string    `yaml:"template_hash" json:"template_hash"`
+	Name         string    `yaml:"name" json:"name"`
+	Preset       string    `yaml:"preset" json:"preset"`
+	Module       string    `yaml:"module" json:"module"`
+	CreatedAt    time.Time `yaml:"created_at" json:"created_at"`
+	SharedModule string    `yaml:"shared_module,omitempty" json:"shared_module,omitempty"`
+	// Excluded are the template files that were not generated
+	Excluded []string `yaml:"excluded,omitempty" json:"excluded,omitempty"`
+}
+
+// ReadLock reads the lock file of the project in dir.
+func ReadLock(dir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lock{}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", LockFile, err)
+	}
+
+	return lock, nil
+}
+
+// TemplateHash returns the sha256 of the files below roots, it changes with any template change.
+func TemplateHash(fsys fs.FS, roots ...string) (string, error) {
+	var files []string
+	for _, root := range roots {
+		err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "%s %d\n", f, len(data))
+		h.Write(data)
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (p *Project) writeLock(module string) error {
+	hash, err := TemplateHash(p.template, append([]string{p.templateDir}, p.overlays...)...)
+	if err != nil {
+		return fmt.Errorf("❌ Failed to hash template: %w", err)
+	}
+
+	lock := Lock{
+		GogVersion:   gog.Version,
+		TemplateHash: hash,
+		Name:         p.name,
+		Preset:       p.preset.Name,
+		Module:       module,
+		CreatedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	if p.shared != nil {
+		lock.SharedModule = p.shared.module
+	}
+
+	for path := range p.excluded {
+		lock.Excluded = append(lock.Excluded, filepath.ToSlash(path))
+	}
+	sort.Strings(lock.Excluded)
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(p.dir, LockFile)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	header := "# Generated by gog new, do not edit. gog and its plugins read the template of the project from it.\n"
+
+	return os.WriteFile(file, append([]byte(header), data...), 0644)
+}
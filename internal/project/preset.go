@@ -0,0 +1,112 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// presetsDir holds the files the presets copy over the template, see Preset.overlays.
+const presetsDir = "_presets"
+
+// DefaultPreset is the HTTP API template.
+const DefaultPreset = "api"
+
+// Preset is a shape of service generated from the template.
+type Preset struct {
+	Name        string
+	Description string
+	// Command runs the service, e.g. serve for `go run . serve`
+	Command string
+	// Swagger is set when the service has an HTTP API documented with swag
+	Swagger bool
+
+	// noExamples removes the example domains from the copied files, like gog new --no-examples
+	noExamples bool
+	// excluded are template relative paths that are not generated
+	excluded []string
+	// overlays are directories of _presets copied over the template in order, their files
+	// replace the template files with the same path
+	overlays []string
+}
+
+// httpAPI are the template files only used by the HTTP API.
+var httpAPI = []string{
+	"cmd/serve",
+	"docs",
+	".github/workflows/swagger.yaml",
+	"internal/registry/registry_api.go",
+	"internal/registry/registry_api_testing.go",
+}
+
+// workCommand is the template command running the consumers without the HTTP API, the worker
+// preset runs it.
+var workCommand = []string{"cmd/work"}
+
+// Presets share the registry, config, logger and errors packages, they differ by the command
+// running the service.
+var Presets = []Preset{
+	{
+		Name:        "api",
+		Description: "HTTP API with fiber and swagger, and the user and post example domains",
+		Command:     "serve",
+		Swagger:     true,
+	},
+	{
+		Name:        "worker",
+		Description: "NATS consumers run by a work command, health checks and metrics are served on worker.admin_port",
+		Command:     "work",
+		noExamples:  true,
+		excluded:    httpAPI,
+		overlays:    []string{"headless", "worker"},
+	},
+	{
+		Name:        "job",
+		Description: "One-shot run command, e.g. for a Kubernetes CronJob",
+		Command:     "run",
+		noExamples:  true,
+		excluded:    append(slices.Clone(httpAPI), workCommand...),
+		overlays:    []string{"headless", "job"},
+	},
+	{
+		Name:        "minimal",
+		Description: "HTTP API without the example domains",
+		Command:     "serve",
+		Swagger:     true,
+		noExamples:  true,
+	},
+}
+
+// FindPreset returns the preset with the given name, api when name is empty.
+func FindPreset(name string) (Preset, error) {
+	if name == "" {
+		name = DefaultPreset
+	}
+
+	names := make([]string, 0, len(Presets))
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, nil
+		}
+
+		names = append(names, p.Name)
+	}
+
+	return Preset{}, fmt.Errorf("❌ Unknown preset '%s', available: %s", name, strings.Join(names, ", "))
+}
+
+// WithPreset generates the service shape of a preset instead of the HTTP API.
+func WithPreset(preset Preset) Option {
+	return func(p *Project) {
+		p.preset = preset
+		p.noExamples = p.noExamples || preset.noExamples
+		for _, file := range preset.excluded {
+			p.excluded[file] = true
+		}
+
+		for _, overlay := range preset.overlays {
+			p.overlays = append(p.overlays, path.Join(presetsDir, overlay))
+		}
+	}
+}
@@ -8,28 +8,122 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/nayla-finance/gog/internal/hooks"
+	"github.com/nayla-finance/gog/internal/report"
 )
 
 const (
 	modulePlaceholder = "github.com/PROJECT_NAME"
 	projectName       = "PROJECT_NAME"
+	// pseudoVersion is required for local modules that are only resolved through a replace directive
+	pseudoVersion = "v0.0.0-00010101000000-000000000000"
 )
 
-type Project struct {
-	template       embed.FS
-	name           string
-	templateDir    string
-	dir            string
-	gitHubUsername string
+type (
+	Project struct {
+		template       embed.FS
+		name           string
+		templateDir    string
+		dir            string
+		gitHubUsername string
+		module         string
+		shared         *sharedModule
+		// excluded are template relative paths that are not generated
+		excluded map[string]bool
+		noGit    bool
+		// getStarted replaces the default "cd <dir> && just <command>" instructions
+		getStarted []string
+		preset     Preset
+		// overlays are template directories copied over templateDir
+		overlays []string
+		// noExamples removes the example domains and their references once the files are copied
+		noExamples bool
+		reporter   report.Reporter
+	}
+
+	// sharedModule is a module of a workspace holding packages the project uses instead of
+	// generating its own copy under internal/.
+	sharedModule struct {
+		module   string
+		path     string
+		packages []string
+	}
+
+	Option func(*Project)
+)
+
+// WithModule sets the module path instead of github.com/<username>/<name>.
+func WithModule(module string) Option {
+	return func(p *Project) {
+		p.module = module
+	}
+}
+
+// WithSharedModule makes the project import packages (e.g. errors, db) from a shared module
+// located at path (relative to the project) instead of generating internal/<package>.
+func WithSharedModule(module, path string, packages []string) Option {
+	return func(p *Project) {
+		p.shared = &sharedModule{module: module, path: path, packages: packages}
+		for _, pkg := range packages {
+			p.excluded[filepath.Join("internal", pkg)] = true
+		}
+	}
+}
+
+// WithoutFiles skips template files or directories (template relative paths).
+func WithoutFiles(paths ...string) Option {
+	return func(p *Project) {
+		for _, path := range paths {
+			p.excluded[filepath.Clean(path)] = true
+		}
+	}
+}
+
+// WithoutGit skips the git repository and pre-commit hook, e.g. when the project is created
+// inside a repository that already has them.
+func WithoutGit() Option {
+	return func(p *Project) {
+		p.noGit = true
+	}
+}
+
+// WithGetStarted replaces the commands printed once the project is created.
+func WithGetStarted(lines ...string) Option {
+	return func(p *Project) {
+		p.getStarted = lines
+	}
+}
+
+// WithReporter reports the progress to r instead of printing the emoji lines to stdout.
+func WithReporter(r report.Reporter) Option {
+	return func(p *Project) {
+		p.reporter = r
+	}
 }
 
-func NewProject(template embed.FS, name string, dir string, gitHubUsername string) *Project {
+// WithoutExamples removes the example domains and every reference to them, the registry and
+// routes are kept ready for new domains.
+func WithoutExamples() Option {
+	return func(p *Project) {
+		p.noExamples = true
+	}
+}
+
+func NewProject(template embed.FS, name string, dir string, gitHubUsername string, opts ...Option) *Project {
 	p := &Project{
 		template:       template,
 		name:           name,
 		templateDir:    "_template",
 		dir:            dir,
 		gitHubUsername: gitHubUsername,
+		excluded:       map[string]bool{},
+		preset:         Presets[0],
+		reporter:       report.NewText(os.Stdout),
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
 
 	p.dir = p.projectDir()
@@ -44,26 +138,42 @@ func (p *Project) Create() error {
 		}
 	}
 
-	fmt.Printf("🎉 Creating new project '%s'\n", p.name)
+	p.reporter.Info("🎉", fmt.Sprintf("Creating new project '%s'", p.name))
 
-	newModule := ""
-	if p.gitHubUsername != "" {
+	newModule := p.module
+	if newModule == "" && p.gitHubUsername != "" {
 		newModule = fmt.Sprintf("github.com/%s/%s", p.gitHubUsername, p.name)
-	} else {
+	} else if newModule == "" {
 		newModule = fmt.Sprintf("github.com/%s", p.name)
 	}
 
 	// Copy template files
-	fmt.Println("✨ Creating files...")
-	replaceFuncs := []func(data []byte) []byte{
-		replaceInFile(modulePlaceholder, newModule), // start with module first
-		replaceInFile(projectName, p.name),
+	p.reporter.Info("✨", "Creating files...")
+	replaceFuncs := Placeholders(newModule, p.name)
+
+	if p.shared != nil {
+		for _, pkg := range p.shared.packages {
+			replaceFuncs = append(replaceFuncs, replaceInFile(
+				fmt.Sprintf("%q", newModule+"/internal/"+pkg),
+				fmt.Sprintf("%q", p.shared.module+"/"+pkg),
+			))
+		}
 	}
 
 	if err := p.copyTemplateFiles(replaceFuncs); err != nil {
 		return err
 	}
 
+	if p.noExamples {
+		if err := p.removeExamples(newModule, replaceFuncs); err != nil {
+			return err
+		}
+	}
+
+	if err := p.writeLock(newModule); err != nil {
+		return err
+	}
+
 	// create .env from .env.example
 	configExample, err := os.ReadFile(filepath.Join(p.dir, "config.yaml.example"))
 	if err != nil {
@@ -78,25 +188,48 @@ func (p *Project) Create() error {
 	// In CreateProject function:
 	steps := []cmdStep{
 		{emoji: "🚀", name: "Initializing project", command: "go", args: []string{"mod", "init", newModule}},
-		{emoji: "🔍", name: "Tidying project", command: "go", args: []string{"mod", "tidy"}},
-		{emoji: "🔍", name: "Initializing git repository", command: "git", args: []string{"init"}},
-		{emoji: "🔍", name: "Creating pre-commit hooks", command: "touch", args: []string{".git/hooks/pre-commit"}},
-		{emoji: "🔍", name: "Adding pre-commit script", command: "sh", args: []string{"-c", "echo \"go fmt ./...\ngo mod tidy\" > .git/hooks/pre-commit"}},
-		{emoji: "🔍", name: "Finalizing git repository", command: "chmod", args: []string{"+x", ".git/hooks/pre-commit"}},
-		{emoji: "🔍", name: "Formatting project", command: "go", args: []string{"fmt", "./..."}},
 	}
 
+	if p.shared != nil {
+		steps = append(steps, cmdStep{emoji: "🔗", name: "Linking shared module", command: "go", args: []string{
+			"mod", "edit",
+			"-require=" + p.shared.module + "@" + pseudoVersion,
+			"-replace=" + p.shared.module + "=" + p.shared.path,
+		}})
+	}
+
+	steps = append(steps, cmdStep{emoji: "🔍", name: "Tidying project", command: "go", args: []string{"mod", "tidy"}})
+
+	if !p.noGit {
+		steps = append(steps, gitSteps...)
+	}
+
+	steps = append(steps, cmdStep{emoji: "🔍", name: "Formatting project", command: "go", args: []string{"fmt", "./..."}})
+
 	if err := p.runCommands(steps); err != nil {
 		return err
 	}
 
-	fmt.Println("\n\n\n✅ Project created successfully!")
-	fmt.Printf("\n  To get started, run:\n\n")
-	if !p.isCurrentDir() {
-		fmt.Printf("  cd %s\n", p.dir)
+	if !p.noGit {
+		if err := installHooks(p.reporter, p.dir, hooks.ProjectDefaults); err != nil {
+			return err
+		}
+	}
+
+	if p.noExamples && p.preset.Swagger {
+		p.regenerateDocs()
 	}
-	fmt.Printf("  just serve\n\n\n")
-	fmt.Println(`
+
+	nextSteps := p.getStarted
+	if len(nextSteps) == 0 {
+		if !p.isCurrentDir() {
+			nextSteps = append(nextSteps, "cd "+p.dir)
+		}
+		nextSteps = append(nextSteps, "just "+p.preset.Command)
+	}
+
+	p.reporter.Completed("Project created successfully!", nextSteps...)
+	p.reporter.Banner(`
     ʕ◔ϖ◔ʔ < Happy coding!
     `)
 	return nil
@@ -113,31 +246,65 @@ func (p *Project) projectDir() string {
 }
 
 func (p *Project) copyTemplateFiles(replaceFuncs []func(data []byte) []byte) error {
-	return fs.WalkDir(p.template, p.templateDir, func(path string, d fs.DirEntry, err error) error {
+	skip := func(relPath string) bool {
+		return p.excluded[relPath]
+	}
+
+	if err := CopyTemplate(p.template, p.templateDir, p.dir, replaceFuncs, skip, p.reporter); err != nil {
+		return err
+	}
+
+	for _, overlay := range p.overlays {
+		if err := CopyTemplate(p.template, overlay, p.dir, replaceFuncs, skip, p.reporter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Placeholders returns the replacements of the template placeholders, the module first since
+// the project name placeholder is part of it.
+func Placeholders(module, name string) []func(data []byte) []byte {
+	return []func(data []byte) []byte{
+		replaceInFile(modulePlaceholder, module),
+		replaceInFile(projectName, name),
+	}
+}
+
+// CopyTemplate copies the files below root of fsys to dir and applies replaceFuncs to their
+// content. The paths (relative to root) skip returns true for are not copied, the copied files
+// are reported to r.
+func CopyTemplate(fsys fs.FS, root, dir string, replaceFuncs []func(data []byte) []byte, skip func(relPath string) bool, r report.Reporter) error {
+	return fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Get relative path from src
-		relPath, err := filepath.Rel(p.templateDir, path)
+		relPath, err := filepath.Rel(root, path)
 		if err != nil {
 			return err
 		}
 
-		targetPath := filepath.Join(p.dir, relPath)
+		targetPath := filepath.Join(dir, relPath)
+
+		if skip != nil && skip(relPath) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
 
 		if d.IsDir() {
 			return os.MkdirAll(targetPath, 0755)
 		}
 
 		// Copy file contents
-		if p.isCurrentDir() {
-			fmt.Printf("  📄 Creating file '%s'\n", relPath)
-		} else {
-			fmt.Printf("  📄 Creating file '%s/%s'\n", p.dir, relPath)
-		}
+		r.FileCreated(targetPath)
 
-		data, err := p.template.ReadFile(path)
+		data, err := fs.ReadFile(fsys, path)
 		if err != nil {
 			return err
 		}
@@ -163,19 +330,48 @@ type cmdStep struct {
 	args    []string
 }
 
+var gitSteps = []cmdStep{
+	{emoji: "🔍", name: "Initializing git repository", command: "git", args: []string{"init"}},
+}
+
 func (p *Project) runCommands(steps []cmdStep) error {
+	return runCommands(p.reporter, p.dir, steps)
+}
+
+func runCommands(r report.Reporter, dir string, steps []cmdStep) error {
 	for _, step := range steps {
-		fmt.Printf("%s %s...\n", step.emoji, step.name)
+		r.StepStarted(step.emoji, step.name)
 
 		cmd := exec.Command(step.command, step.args...)
-		cmd.Dir = p.dir // Set working directory
+		cmd.Dir = dir // Set working directory
 
 		if output, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("%s failed: %w\nOutput: %s", step.name, err, output)
+			// the output is reported with the step
+			r.StepFailed(step.name, output, err)
+			return fmt.Errorf("%s failed: %w", step.name, err)
 		}
 
-		fmt.Printf("✅ %s complete\n", step.name)
+		r.StepSucceeded(step.name)
+	}
+	return nil
+}
+
+// installHooks writes the default .gog/hooks.yaml and installs its git hooks.
+func installHooks(r report.Reporter, dir, defaults string) error {
+	const step = "Installing git hooks"
+	r.StepStarted("🪝", step)
+
+	written, err := hooks.Install(dir, defaults, false)
+	if err != nil {
+		r.StepFailed(step, nil, err)
+		return fmt.Errorf("%s failed: %w", step, err)
 	}
+
+	for _, f := range written {
+		r.FileCreated(f)
+	}
+	r.StepSucceeded(step)
+
 	return nil
 }
 
@@ -0,0 +1,363 @@
+package project
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/nayla-finance/gog/internal/hooks"
+	"github.com/nayla-finance/gog/internal/report"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// WorkspaceFile marks the root of a workspace and lists its services.
+	WorkspaceFile = ".gog/workspace.yaml"
+
+	sharedDir   = "shared"
+	servicesDir = "services"
+)
+
+// SharedPackages are the template packages that can be extracted to the shared module of a
+// workspace. They only depend on go-nayla so every service can import the same copy: the
+// config base embedded by the Config of every service and the health checks of its
+// dependencies.
+var SharedPackages = []string{"errors", "db", "configbase", "healthcheck"}
+
+// sharedAliases are the names of the shared packages after what they hold, e.g.
+// --shared config,health.
+var sharedAliases = map[string]string{
+	"config": "configbase",
+	"health": "healthcheck",
+}
+
+type (
+	Workspace struct {
+		template embed.FS
+		root     string
+		manifest Manifest
+		reporter report.Reporter
+	}
+
+	Manifest struct {
+		// Module is the module path prefix, services are <module>/services/<name>
+		Module   string   `yaml:"module"`
+		Shared   []string `yaml:"shared"`
+		Services []string `yaml:"services"`
+	}
+)
+
+// NewWorkspace returns a workspace that is not created yet, see Create.
+func NewWorkspace(template embed.FS, name, dir, gitHubUsername string, shared []string) (*Workspace, error) {
+	var packages []string
+	for _, pkg := range shared {
+		name := pkg
+		if alias, ok := sharedAliases[pkg]; ok {
+			name = alias
+		}
+
+		if !slices.Contains(SharedPackages, name) {
+			return nil, fmt.Errorf("❌ Unknown shared package '%s', available: %s", pkg, strings.Join(SharedPackages, ", "))
+		}
+
+		if !slices.Contains(packages, name) {
+			packages = append(packages, name)
+		}
+	}
+
+	if dir == "" {
+		dir = name
+	}
+
+	module := fmt.Sprintf("github.com/%s", name)
+	if gitHubUsername != "" {
+		module = fmt.Sprintf("github.com/%s/%s", gitHubUsername, name)
+	}
+
+	return &Workspace{
+		template: template,
+		root:     dir,
+		manifest: Manifest{Module: module, Shared: packages},
+		reporter: report.NewText(os.Stdout),
+	}, nil
+}
+
+// FindWorkspace looks up the workspace containing dir.
+func FindWorkspace(template embed.FS, dir string) (*Workspace, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for root := abs; ; {
+		data, err := os.ReadFile(filepath.Join(root, WorkspaceFile))
+		if err == nil {
+			w := &Workspace{template: template, root: root, reporter: report.NewText(os.Stdout)}
+			if err := yaml.Unmarshal(data, &w.manifest); err != nil {
+				return nil, fmt.Errorf("❌ Failed to parse %s: %w", WorkspaceFile, err)
+			}
+
+			return w, nil
+		}
+
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil, fmt.Errorf("❌ No workspace found in '%s' or its parents (create one with gog new --workspace)", dir)
+		}
+		root = parent
+	}
+}
+
+// SetReporter reports the progress to r instead of printing the emoji lines to stdout.
+func (w *Workspace) SetReporter(r report.Reporter) {
+	w.reporter = r
+}
+
+// Create creates the go.work repository with the shared module.
+func (w *Workspace) Create() error {
+	if _, err := os.Stat(filepath.Join(w.root, WorkspaceFile)); err == nil {
+		return fmt.Errorf("❌ '%s' is already a workspace", w.root)
+	}
+
+	w.reporter.Info("🎉", fmt.Sprintf("Creating new workspace '%s'", filepath.Base(w.root)))
+
+	for _, dir := range []string{filepath.Dir(WorkspaceFile), servicesDir} {
+		if err := os.MkdirAll(filepath.Join(w.root, dir), 0755); err != nil {
+			return fmt.Errorf("❌ Failed to create directory '%s': %w", dir, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(w.root, servicesDir, ".gitkeep"), nil, 0644); err != nil {
+		return err
+	}
+
+	steps := []cmdStep{
+		{emoji: "🚀", name: "Initializing workspace", command: "go", args: []string{"work", "init"}},
+	}
+
+	if len(w.manifest.Shared) > 0 {
+		if err := w.copySharedPackages(); err != nil {
+			return err
+		}
+
+		if err := runCommands(w.reporter, filepath.Join(w.root, sharedDir), []cmdStep{
+			{emoji: "🚀", name: "Initializing shared module", command: "go", args: []string{"mod", "init", w.sharedModule()}},
+			{emoji: "🔍", name: "Tidying shared module", command: "go", args: []string{"mod", "tidy"}},
+		}); err != nil {
+			return err
+		}
+
+		steps = append(steps, cmdStep{emoji: "🔗", name: "Adding shared module to workspace", command: "go", args: []string{"work", "use", "./" + sharedDir}})
+	}
+
+	if err := w.save(); err != nil {
+		return err
+	}
+
+	steps = append(steps, gitSteps...)
+
+	if err := runCommands(w.reporter, w.root, steps); err != nil {
+		return err
+	}
+
+	if err := installHooks(w.reporter, w.root, hooks.WorkspaceDefaults); err != nil {
+		return err
+	}
+
+	w.reporter.Completed("Workspace created successfully!", "cd "+w.root, "gog new my-service --in-workspace")
+
+	return nil
+}
+
+// ProjectOptions returns the options creating a service of the workspace: it lives in
+// services/<name>, imports the shared packages and is run from the workspace justfile.
+// GitHub only reads the workflows of the repository root so .github is not generated either.
+func (w *Workspace) ProjectOptions(name string, preset Preset) []Option {
+	opts := []Option{
+		WithModule(w.manifest.Module + "/" + servicesDir + "/" + name),
+		WithPreset(preset),
+		WithoutFiles("justfile", ".github"),
+		WithoutGit(),
+		WithGetStarted("cd "+w.root, "just "+preset.Command+"-"+name),
+	}
+
+	if len(w.manifest.Shared) > 0 {
+		opts = append(opts, WithSharedModule(w.sharedModule(), "../../"+sharedDir, w.manifest.Shared))
+	}
+
+	return opts
+}
+
+// ServiceDir returns the directory of a service of the workspace.
+func (w *Workspace) ServiceDir(name string) string {
+	return filepath.Join(w.root, servicesDir, name)
+}
+
+// AddService adds a created service to go.work, the manifest and the justfile.
+func (w *Workspace) AddService(name string) error {
+	if err := runCommands(w.reporter, w.root, []cmdStep{
+		{emoji: "🔗", name: "Adding service to workspace", command: "go", args: []string{"work", "use", "./" + path.Join(servicesDir, name)}},
+	}); err != nil {
+		return err
+	}
+
+	if !slices.Contains(w.manifest.Services, name) {
+		w.manifest.Services = append(w.manifest.Services, name)
+		slices.Sort(w.manifest.Services)
+	}
+
+	return w.save()
+}
+
+func (w *Workspace) sharedModule() string {
+	return w.manifest.Module + "/" + sharedDir
+}
+
+// copySharedPackages copies internal/<package> of the template to shared/<package>.
+func (w *Workspace) copySharedPackages() error {
+	replace := replaceInFile(modulePlaceholder+"/internal/", w.sharedModule()+"/")
+
+	for _, pkg := range w.manifest.Shared {
+		src := path.Join("_template", "internal", pkg)
+
+		err := fs.WalkDir(w.template, src, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(src, p)
+			if err != nil {
+				return err
+			}
+
+			target := filepath.Join(w.root, sharedDir, pkg, rel)
+			if d.IsDir() {
+				return os.MkdirAll(target, 0755)
+			}
+
+			w.reporter.FileCreated(filepath.Join(w.root, sharedDir, pkg, rel))
+
+			data, err := w.template.ReadFile(p)
+			if err != nil {
+				return err
+			}
+
+			return os.WriteFile(target, replace(data), 0644)
+		})
+		if err != nil {
+			return fmt.Errorf("❌ Failed to create shared package '%s': %w", pkg, err)
+		}
+	}
+
+	return nil
+}
+
+// save writes the manifest and regenerates the justfile.
+func (w *Workspace) save() error {
+	data, err := yaml.Marshal(w.manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(w.root, WorkspaceFile), data, 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write %s: %w", WorkspaceFile, err)
+	}
+
+	view := justfileView{Shared: w.manifest.Shared}
+	for _, name := range w.manifest.Services {
+		// the services created before presets have no preset in their lock file
+		preset := Presets[0]
+		if lock, err := ReadLock(w.ServiceDir(name)); err == nil {
+			if p, err := FindPreset(lock.Preset); err == nil {
+				preset = p
+			}
+		}
+
+		view.Services = append(view.Services, justfileService{Name: name, Preset: preset})
+	}
+
+	var buf bytes.Buffer
+	if err := justfileTemplate.Execute(&buf, view); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(w.root, "justfile"), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("❌ Failed to write justfile: %w", err)
+	}
+
+	return nil
+}
+
+type (
+	justfileView struct {
+		Shared   []string
+		Services []justfileService
+	}
+
+	justfileService struct {
+		Name   string
+		Preset Preset
+	}
+)
+
+var justfileTemplate = template.Must(template.New("justfile").Parse(`# Generated by gog from .gog/workspace.yaml, add your own recipes to local.just
+set dotenv-load
+
+import? 'local.just'
+
+default:
+    @just --list
+
+# Run the tests of every module
+test:
+{{- if .Shared }}
+    cd shared && go test ./...
+{{- end }}
+{{- range .Services }}
+    cd services/{{ .Name }} && go test ./...
+{{- end }}
+{{- if not .Services }}
+    @echo "no services yet, add one with: gog new my-service --in-workspace"
+{{- end }}
+{{ range .Services }}
+# {{ .Name }}
+
+# Run {{ .Name }} ({{ .Preset.Name }})
+{{ .Preset.Command }}-{{ .Name }}:
+    cd services/{{ .Name }} && go run . {{ .Preset.Command }} -c config.yaml
+
+# Run the migrations of {{ .Name }}
+migrate-{{ .Name }}:
+    cd services/{{ .Name }} && go run . migrate up -c config.yaml
+
+# Create a new migration for {{ .Name }}
+migrate-new-{{ .Name }} name:
+    cd services/{{ .Name }} && go run . migrate new {{ "{{" }}name{{ "}}" }} -c config.yaml
+
+# Build {{ .Name }}
+build-{{ .Name }}: test-{{ .Name }}
+    cd services/{{ .Name }} && go build -o bin/{{ .Name }} .
+
+# Run the tests of {{ .Name }}
+test-{{ .Name }}:
+    cd services/{{ .Name }} && go test ./...
+{{ if .Preset.Swagger }}
+# Generate the swagger docs of {{ .Name }} (you need to have gog installed)
+swagger-{{ .Name }}:
+    cd services/{{ .Name }} && gog swag init -g cmd/serve/serve.go
+{{ end }}
+# Check the layering of {{ .Name }} (you need to have gog installed)
+lint-{{ .Name }}:
+    cd services/{{ .Name }} && gog lint arch
+
+docker-build-{{ .Name }}:
+    cd services/{{ .Name }} && docker build -t {{ .Name }}-image:latest -f devops/Dockerfile .
+{{ end -}}
+`))
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// AddFlags adds the --output and --quiet flags of the commands using a reporter.
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", FormatText, "The output format: text, or json for newline delimited events")
+	cmd.Flags().BoolP("quiet", "q", false, "Only print errors")
+	cmd.MarkFlagsMutuallyExclusive("output", "quiet")
+}
+
+// FromFlags returns the reporter of the --output and --quiet flags.
+func FromFlags(cmd *cobra.Command) (Reporter, error) {
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to get output flag: %w", err)
+	}
+
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to get quiet flag: %w", err)
+	}
+
+	return New(format, quiet)
+}
@@ -0,0 +1,244 @@
+// Package report prints the progress of the gog commands, as the emoji lines read by people
+// or as newline delimited JSON events read by IDE integrations.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats are the values of the --output flag.
+var Formats = []string{FormatText, FormatJSON}
+
+// Event types of the JSON output.
+const (
+	EventFileCreated   = "file_created"
+	EventFileUpdated   = "file_updated"
+	EventFileRemoved   = "file_removed"
+	EventStepStarted   = "step_started"
+	EventStepSucceeded = "step_succeeded"
+	EventStepFailed    = "step_failed"
+	EventMessage       = "message"
+	EventWarning       = "warning"
+	EventCompleted     = "completed"
+	EventError         = "error"
+)
+
+type (
+	// Reporter receives the progress of a command.
+	Reporter interface {
+		// Banner prints ASCII art, only on a terminal
+		Banner(art string)
+		// Info reports progress that is not a step, e.g. "Creating files..."
+		Info(emoji, message string)
+		Warn(message string)
+		FileCreated(path string)
+		FileUpdated(path string)
+		FileRemoved(path string)
+		StepStarted(emoji, step string)
+		StepSucceeded(step string)
+		// StepFailed reports a failed step with the output it captured
+		StepFailed(step string, output []byte, err error)
+		// Completed reports the success of the command and the commands to run next
+		Completed(message string, nextSteps ...string)
+		// Error reports the error ending the command
+		Error(message string, err error)
+	}
+
+	// Event is a line of the JSON output.
+	Event struct {
+		Event     string    `json:"event"`
+		Time      time.Time `json:"time"`
+		Path      string    `json:"path,omitempty"`
+		Step      string    `json:"step,omitempty"`
+		Message   string    `json:"message,omitempty"`
+		Output    string    `json:"output,omitempty"`
+		Error     string    `json:"error,omitempty"`
+		NextSteps []string  `json:"next_steps,omitempty"`
+	}
+)
+
+// New returns the reporter of a format writing to stdout, quiet only reports errors, on stderr.
+func New(format string, quiet bool) (Reporter, error) {
+	switch {
+	case format == FormatJSON:
+		return NewJSON(os.Stdout), nil
+	case format != FormatText:
+		return nil, fmt.Errorf("❌ Unknown output format '%s', available: %s", format, strings.Join(Formats, ", "))
+	case quiet:
+		return NewQuiet(os.Stderr), nil
+	default:
+		return NewText(os.Stdout), nil
+	}
+}
+
+// Text prints emoji lines.
+type Text struct {
+	w io.Writer
+}
+
+func NewText(w io.Writer) *Text {
+	return &Text{w: w}
+}
+
+func (t *Text) Banner(art string) {
+	if isTerminal(t.w) {
+		fmt.Fprintln(t.w, art)
+	}
+}
+
+func (t *Text) Info(emoji, message string) {
+	fmt.Fprintf(t.w, "%s %s\n", emoji, message)
+}
+
+func (t *Text) Warn(message string) {
+	fmt.Fprintf(t.w, "⚠️  %s\n", message)
+}
+
+func (t *Text) FileCreated(path string) {
+	fmt.Fprintf(t.w, "  📄 Created '%s'\n", path)
+}
+
+func (t *Text) FileUpdated(path string) {
+	fmt.Fprintf(t.w, "  ✏️  Updated '%s'\n", path)
+}
+
+func (t *Text) FileRemoved(path string) {
+	fmt.Fprintf(t.w, "  🗑️  Removed '%s'\n", path)
+}
+
+func (t *Text) StepStarted(emoji, step string) {
+	fmt.Fprintf(t.w, "%s %s...\n", emoji, step)
+}
+
+func (t *Text) StepSucceeded(step string) {
+	fmt.Fprintf(t.w, "✅ %s complete\n", step)
+}
+
+func (t *Text) StepFailed(step string, output []byte, err error) {
+	fmt.Fprintf(t.w, "❌ %s failed: %v\n", step, err)
+	if out := strings.TrimRight(string(output), "\n"); out != "" {
+		fmt.Fprintf(t.w, "  %s\n", strings.ReplaceAll(out, "\n", "\n  "))
+	}
+}
+
+func (t *Text) Completed(message string, nextSteps ...string) {
+	fmt.Fprintf(t.w, "\n✅ %s\n", message)
+	if len(nextSteps) > 0 {
+		fmt.Fprintf(t.w, "\n  Next, run:\n\n")
+		for _, line := range nextSteps {
+			fmt.Fprintf(t.w, "  %s\n", line)
+		}
+		fmt.Fprintln(t.w)
+	}
+}
+
+func (t *Text) Error(message string, err error) {
+	fmt.Fprintln(t.w, message+":", err)
+}
+
+// isTerminal reports whether w is a character device, the banner and colors would end up in
+// the logs of a pipe or a file.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// JSON writes an Event per line.
+type JSON struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{enc: json.NewEncoder(w)}
+}
+
+func (j *JSON) emit(e Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	e.Time = time.Now().UTC()
+	j.enc.Encode(e)
+}
+
+func (j *JSON) Banner(string) {}
+
+func (j *JSON) Info(_, message string) {
+	j.emit(Event{Event: EventMessage, Message: message})
+}
+
+func (j *JSON) Warn(message string) {
+	j.emit(Event{Event: EventWarning, Message: message})
+}
+
+func (j *JSON) FileCreated(path string) {
+	j.emit(Event{Event: EventFileCreated, Path: path})
+}
+
+func (j *JSON) FileUpdated(path string) {
+	j.emit(Event{Event: EventFileUpdated, Path: path})
+}
+
+func (j *JSON) FileRemoved(path string) {
+	j.emit(Event{Event: EventFileRemoved, Path: path})
+}
+
+func (j *JSON) StepStarted(_, step string) {
+	j.emit(Event{Event: EventStepStarted, Step: step})
+}
+
+func (j *JSON) StepSucceeded(step string) {
+	j.emit(Event{Event: EventStepSucceeded, Step: step})
+}
+
+func (j *JSON) StepFailed(step string, output []byte, err error) {
+	j.emit(Event{Event: EventStepFailed, Step: step, Output: string(output), Error: err.Error()})
+}
+
+func (j *JSON) Completed(message string, nextSteps ...string) {
+	j.emit(Event{Event: EventCompleted, Message: message, NextSteps: nextSteps})
+}
+
+func (j *JSON) Error(message string, err error) {
+	j.emit(Event{Event: EventError, Message: message, Error: err.Error()})
+}
+
+// Quiet only prints the error ending the command.
+type Quiet struct {
+	w io.Writer
+}
+
+func NewQuiet(w io.Writer) *Quiet {
+	return &Quiet{w: w}
+}
+
+func (q *Quiet) Banner(string)                    {}
+func (q *Quiet) Info(_, _ string)                 {}
+func (q *Quiet) Warn(string)                      {}
+func (q *Quiet) FileCreated(string)               {}
+func (q *Quiet) FileUpdated(string)               {}
+func (q *Quiet) FileRemoved(string)               {}
+func (q *Quiet) StepStarted(_, _ string)          {}
+func (q *Quiet) StepSucceeded(string)             {}
+func (q *Quiet) StepFailed(string, []byte, error) {}
+func (q *Quiet) Completed(string, ...string)      {}
+
+func (q *Quiet) Error(message string, err error) {
+	fmt.Fprintln(q.w, message+":", err)
+}
@@ -0,0 +1,674 @@
+package routes
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nayla-finance/gog/internal/source"
+)
+
+type (
+	// Route is a route registered on the fiber app with the middlewares that run before its handler.
+	Route struct {
+		Method      string       `json:"method"`
+		Path        string       `json:"path"`
+		Handler     string       `json:"handler"`
+		Position    string       `json:"position"`
+		Middlewares []Middleware `json:"middlewares"`
+		Public      *bool        `json:"public,omitempty"`
+	}
+
+	Middleware struct {
+		Name     string `json:"name"`
+		Scope    Scope  `json:"scope"`
+		Position string `json:"position"`
+		// Leaked is set when the middleware was registered with Use by another function on a
+		// shared router, e.g. r.Use inside post.Handler applies to every later /api route.
+		Leaked bool   `json:"leaked,omitempty"`
+		Origin string `json:"origin"`
+	}
+
+	Inventory struct {
+		Routes            []*Route     `json:"routes"`
+		GlobalMiddlewares []Middleware `json:"global_middlewares"`
+	}
+
+	Scope string
+)
+
+const (
+	ScopeGlobal Scope = "global"
+	ScopeGroup  Scope = "group"
+	ScopeRoute  Scope = "route"
+
+	maxDepth = 16
+)
+
+var routeMethods = map[string]string{
+	"Get":     "GET",
+	"Head":    "HEAD",
+	"Post":    "POST",
+	"Put":     "PUT",
+	"Patch":   "PATCH",
+	"Delete":  "DELETE",
+	"Connect": "CONNECT",
+	"Options": "OPTIONS",
+	"Trace":   "TRACE",
+	"All":     "ALL",
+}
+
+type (
+	// router is a fiber.App or fiber.Router value with the prefix of its group.
+	router struct {
+		prefix string
+		root   bool
+	}
+
+	// value is what the interpreter knows about a variable.
+	value struct {
+		router *router
+		// origin is the function that created the value (e.g. middleware.NewAuthMiddleware)
+		origin string
+		// pkg and typ are the declared type of the value when it was created by a local constructor
+		pkg *source.Package
+		typ string
+	}
+
+	// event is a Use or route registration in the order the app would execute them.
+	event struct {
+		use        bool
+		method     string
+		path       string
+		handler    string
+		handlerPos string
+		middleware []Middleware
+		origin     string
+	}
+
+	frame struct {
+		pkg  *source.Package
+		file *source.File
+		fn   source.FuncDecl
+		vars map[string]*value
+		// recv is the receiver type of the method being interpreted
+		recv string
+	}
+
+	interpreter struct {
+		m      *source.Module
+		events []event
+		depth  int
+		active map[*ast.FuncDecl]bool
+	}
+)
+
+// Build statically collects the routes of the project starting from the serve command
+// (or the registry when there is none).
+func Build(m *source.Module) (*Inventory, error) {
+	in := &interpreter{m: m, active: map[*ast.FuncDecl]bool{}}
+
+	entry, args, ok := in.entry()
+	if !ok {
+		return nil, fmt.Errorf("❌ Could not find where the fiber app is created (cmd/serve Run or Registry.InitializeWithFiber)")
+	}
+
+	in.call(entry, args)
+
+	return in.inventory(), nil
+}
+
+// entry finds the function creating the fiber app: serve.Run in generated projects, otherwise
+// any function or method receiving a *fiber.App.
+func (in *interpreter) entry() (funcRef, map[string]*value, bool) {
+	var fallback *funcRef
+
+	for _, pkg := range in.m.SortedPackages() {
+		for _, fd := range pkg.Funcs() {
+			if fd.Decl.Body == nil {
+				continue
+			}
+
+			if strings.HasSuffix(pkg.ImportPath, "/cmd/serve") && fd.Decl.Name.Name == "Run" && fd.Receiver() == "" {
+				return funcRef{pkg: pkg, fn: fd}, nil, true
+			}
+
+			if fallback == nil && fd.Decl.Name.Name == "InitializeWithFiber" {
+				fallback = &funcRef{pkg: pkg, fn: fd}
+			}
+		}
+	}
+
+	if fallback == nil {
+		return funcRef{}, nil, false
+	}
+
+	args := map[string]*value{}
+	for _, field := range fallback.fn.Decl.Type.Params.List {
+		for _, name := range field.Names {
+			args[name.Name] = &value{router: &router{root: true}}
+		}
+	}
+
+	return *fallback, args, true
+}
+
+type funcRef struct {
+	pkg *source.Package
+	fn  source.FuncDecl
+}
+
+func (in *interpreter) call(ref funcRef, args map[string]*value) {
+	decl := ref.fn.Decl
+	if decl.Body == nil || in.active[decl] || in.depth >= maxDepth {
+		return
+	}
+
+	in.active[decl] = true
+	in.depth++
+	defer func() {
+		in.active[decl] = false
+		in.depth--
+	}()
+
+	f := &frame{
+		pkg:  ref.pkg,
+		file: ref.fn.File,
+		fn:   ref.fn,
+		vars: map[string]*value{},
+		recv: ref.fn.Receiver(),
+	}
+
+	for k, v := range args {
+		f.vars[k] = v
+	}
+
+	if recv := ref.fn.ReceiverName(); recv != "" {
+		f.vars[recv] = &value{pkg: ref.pkg, typ: f.recv}
+	}
+
+	in.block(f, decl.Body)
+}
+
+func (in *interpreter) block(f *frame, body ast.Node) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// closures are only interpreted when they are registered as handlers or called
+			return false
+		case *ast.AssignStmt:
+			for _, rhs := range n.Rhs {
+				in.expr(f, rhs)
+			}
+
+			if len(n.Rhs) == 1 {
+				if id, ok := n.Lhs[0].(*ast.Ident); ok && id.Name != "_" {
+					if v := in.eval(f, n.Rhs[0]); v != nil {
+						f.vars[id.Name] = v
+					}
+				}
+			}
+
+			return false
+		case *ast.CallExpr:
+			in.expr(f, n)
+			return false
+		}
+
+		return true
+	})
+}
+
+// expr interprets the calls found in an expression, innermost first.
+func (in *interpreter) expr(f *frame, e ast.Expr) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			for _, arg := range n.Args {
+				in.expr(f, arg)
+			}
+
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok {
+				in.expr(f, sel.X)
+			}
+
+			in.evalCall(f, n)
+			return false
+		}
+
+		return true
+	})
+}
+
+// eval returns what is known about an expression without recording events.
+func (in *interpreter) eval(f *frame, e ast.Expr) *value {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return in.eval(f, e.X)
+	case *ast.Ident:
+		return f.vars[e.Name]
+	case *ast.CallExpr:
+		return in.result(f, e)
+	}
+
+	return nil
+}
+
+// result describes the value returned by a call: routers for Group and app constructors,
+// and the origin/type for everything else.
+func (in *interpreter) result(f *frame, call *ast.CallExpr) *value {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if recv := in.eval(f, sel.X); recv != nil && recv.router != nil && sel.Sel.Name == "Group" {
+			return &value{router: &router{prefix: joinPath(recv.router.prefix, stringArg(call, 0))}}
+		}
+
+		if importPath, ok := f.file.Imports()[identName(sel.X)]; ok {
+			if strings.HasPrefix(importPath, "github.com/gofiber/fiber") && sel.Sel.Name == "New" {
+				return &value{router: &router{root: true}}
+			}
+
+			v := &value{origin: identName(sel.X) + "." + sel.Sel.Name}
+			if pkg, ok := in.m.Packages[importPath]; ok {
+				v.origin = pkg.Name + "." + sel.Sel.Name
+				in.typed(v, pkg, sel.Sel.Name)
+			}
+
+			return v
+		}
+	}
+
+	if id, ok := call.Fun.(*ast.Ident); ok {
+		v := &value{origin: f.pkg.Name + "." + id.Name}
+		in.typed(v, f.pkg, id.Name)
+		return v
+	}
+
+	return nil
+}
+
+// typed fills in the declared result type of a local function, turning it into a router when
+// it returns *fiber.App or fiber.Router.
+func (in *interpreter) typed(v *value, pkg *source.Package, name string) {
+	fd, ok := lookupFunc(pkg, "", name)
+	if !ok || fd.Decl.Type.Results == nil || len(fd.Decl.Type.Results.List) == 0 {
+		return
+	}
+
+	t := fd.Decl.Type.Results.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	switch t := t.(type) {
+	case *ast.Ident:
+		v.pkg, v.typ = pkg, t.Name
+	case *ast.SelectorExpr:
+		importPath := fd.File.Imports()[identName(t.X)]
+		if strings.HasPrefix(importPath, "github.com/gofiber/fiber") && (t.Sel.Name == "App" || t.Sel.Name == "Router") {
+			v.router = &router{root: t.Sel.Name == "App"}
+		}
+	}
+}
+
+func (in *interpreter) evalCall(f *frame, call *ast.CallExpr) {
+	sel, isSel := call.Fun.(*ast.SelectorExpr)
+
+	if isSel {
+		if recv := in.eval(f, sel.X); recv != nil && recv.router != nil {
+			in.routerCall(f, recv.router, sel.Sel.Name, call)
+			return
+		}
+	}
+
+	// only follow calls that hand a router (or the receiver holding one) to another function
+	args := map[int]*value{}
+	for i, arg := range call.Args {
+		if v := in.eval(f, arg); v != nil && v.router != nil {
+			args[i] = v
+		}
+	}
+
+	if len(args) == 0 {
+		return
+	}
+
+	ref, ok := in.resolve(f, call)
+	if !ok {
+		return
+	}
+
+	params := map[string]*value{}
+	i := 0
+	for _, field := range ref.fn.Decl.Type.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			i++
+			continue
+		}
+
+		for _, name := range names {
+			if v, ok := args[i]; ok {
+				params[name.Name] = v
+			}
+			i++
+		}
+	}
+
+	in.call(ref, params)
+}
+
+// resolve finds the declaration of the function or method called.
+func (in *interpreter) resolve(f *frame, call *ast.CallExpr) (funcRef, bool) {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if fd, ok := lookupFunc(f.pkg, "", fun.Name); ok {
+			return funcRef{pkg: f.pkg, fn: fd}, true
+		}
+	case *ast.SelectorExpr:
+		if importPath, ok := f.file.Imports()[identName(fun.X)]; ok {
+			if pkg, ok := in.m.Packages[importPath]; ok {
+				if fd, ok := lookupFunc(pkg, "", fun.Sel.Name); ok {
+					return funcRef{pkg: pkg, fn: fd}, true
+				}
+			}
+
+			return funcRef{}, false
+		}
+
+		if v := in.eval(f, fun.X); v != nil && v.pkg != nil {
+			if fd, ok := lookupFunc(v.pkg, v.typ, fun.Sel.Name); ok {
+				return funcRef{pkg: v.pkg, fn: fd}, true
+			}
+		}
+
+		// the type of the receiver is unknown (e.g. a registry created in another package),
+		// fall back to the only method with that name in the project
+		var found []funcRef
+		for _, pkg := range in.m.SortedPackages() {
+			for _, fd := range pkg.Funcs() {
+				if fd.Receiver() != "" && fd.Decl.Name.Name == fun.Sel.Name {
+					found = append(found, funcRef{pkg: pkg, fn: fd})
+				}
+			}
+		}
+
+		if len(found) == 1 {
+			return found[0], true
+		}
+	}
+
+	return funcRef{}, false
+}
+
+func (in *interpreter) routerCall(f *frame, r *router, method string, call *ast.CallExpr) {
+	origin := in.funcName(f)
+
+	switch {
+	case method == "Use":
+		args := call.Args
+		prefix := r.prefix
+		if len(args) > 0 {
+			if lit, ok := args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				prefix = joinPath(prefix, stringArg(call, 0))
+				args = args[1:]
+			}
+		}
+
+		scope := ScopeGroup
+		if r.root && prefix == "" {
+			scope = ScopeGlobal
+		}
+
+		e := event{use: true, path: prefix, origin: origin}
+		for _, arg := range args {
+			e.middleware = append(e.middleware, in.middleware(f, arg, scope, origin))
+		}
+
+		in.events = append(in.events, e)
+	case method == "Group":
+		// group handlers behave like Use on the group prefix
+		if len(call.Args) > 1 {
+			e := event{use: true, path: joinPath(r.prefix, stringArg(call, 0)), origin: origin}
+			for _, arg := range call.Args[1:] {
+				e.middleware = append(e.middleware, in.middleware(f, arg, ScopeGroup, origin))
+			}
+
+			in.events = append(in.events, e)
+		}
+	case routeMethods[method] != "" || method == "Add":
+		args := call.Args
+		httpMethod := routeMethods[method]
+		if method == "Add" {
+			if len(args) == 0 {
+				return
+			}
+
+			httpMethod = strings.Trim(types.ExprString(args[0]), `"`)
+			args = args[1:]
+		}
+
+		if len(args) < 2 {
+			return
+		}
+
+		p, _ := strconv.Unquote(types.ExprString(args[0]))
+		handlers := args[1:]
+
+		e := event{
+			method: httpMethod,
+			path:   joinPath(r.prefix, p),
+			origin: origin,
+		}
+
+		for _, mw := range handlers[:len(handlers)-1] {
+			e.middleware = append(e.middleware, in.middleware(f, mw, ScopeRoute, origin))
+		}
+
+		e.handler, e.handlerPos = in.handler(f, handlers[len(handlers)-1])
+		in.events = append(in.events, e)
+	}
+}
+
+// middleware describes a middleware expression, e.g. NewSpecificPostMiddleware(h.d).Handle
+// becomes post.NewSpecificPostMiddleware.
+func (in *interpreter) middleware(f *frame, e ast.Expr, scope Scope, origin string) Middleware {
+	mw := Middleware{
+		Name:     types.ExprString(e),
+		Scope:    scope,
+		Position: in.position(e.Pos()),
+		Origin:   origin,
+	}
+
+	if sel, ok := e.(*ast.SelectorExpr); ok && sel.Sel.Name == "Handle" {
+		e = sel.X
+	}
+
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		if v := in.result(f, e); v != nil && v.origin != "" {
+			mw.Name = v.origin
+		}
+	case *ast.Ident:
+		if v := f.vars[e.Name]; v != nil && v.origin != "" {
+			mw.Name = v.origin
+		}
+	}
+
+	return mw
+}
+
+// handler returns the name and declaration position of the final route handler.
+func (in *interpreter) handler(f *frame, e ast.Expr) (string, string) {
+	switch e := e.(type) {
+	case *ast.FuncLit:
+		return "func literal", in.position(e.Pos())
+	case *ast.Ident:
+		if fd, ok := lookupFunc(f.pkg, "", e.Name); ok {
+			return f.pkg.Name + "." + e.Name, in.position(fd.Decl.Pos())
+		}
+	case *ast.SelectorExpr:
+		if v := in.eval(f, e.X); v != nil && v.pkg != nil {
+			if fd, ok := lookupFunc(v.pkg, v.typ, e.Sel.Name); ok {
+				return fmt.Sprintf("%s.(*%s).%s", v.pkg.Name, v.typ, e.Sel.Name), in.position(fd.Decl.Pos())
+			}
+		}
+
+		if importPath, ok := f.file.Imports()[identName(e.X)]; ok {
+			if pkg, ok := in.m.Packages[importPath]; ok {
+				if fd, ok := lookupFunc(pkg, "", e.Sel.Name); ok {
+					return pkg.Name + "." + e.Sel.Name, in.position(fd.Decl.Pos())
+				}
+			}
+		}
+	}
+
+	return types.ExprString(e), in.position(e.Pos())
+}
+
+func (in *interpreter) funcName(f *frame) string {
+	if f.recv != "" {
+		return fmt.Sprintf("%s.(*%s).%s", f.pkg.Name, f.recv, f.fn.Decl.Name.Name)
+	}
+
+	return f.pkg.Name + "." + f.fn.Decl.Name.Name
+}
+
+func (in *interpreter) position(pos token.Pos) string {
+	p := in.m.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
+
+// inventory replays the registrations: a middleware registered with Use runs before every
+// route registered after it whose path starts with the Use prefix. The slices are never nil,
+// so gog routes --json always prints arrays.
+func (in *interpreter) inventory() *Inventory {
+	inv := &Inventory{Routes: []*Route{}, GlobalMiddlewares: []Middleware{}}
+
+	for i, e := range in.events {
+		if e.use {
+			if e.path == "" {
+				for _, mw := range e.middleware {
+					if mw.Scope == ScopeGlobal {
+						inv.GlobalMiddlewares = append(inv.GlobalMiddlewares, mw)
+					}
+				}
+			}
+			continue
+		}
+
+		r := &Route{
+			Method:      e.method,
+			Path:        e.path,
+			Handler:     e.handler,
+			Position:    e.handlerPos,
+			Middlewares: []Middleware{},
+		}
+
+		for _, prev := range in.events[:i] {
+			if !prev.use || !hasPrefix(e.path, prev.path) {
+				continue
+			}
+
+			for _, mw := range prev.middleware {
+				if mw.Scope == ScopeGlobal {
+					continue
+				}
+
+				mw.Leaked = mw.Origin != e.origin
+				r.Middlewares = append(r.Middlewares, mw)
+			}
+		}
+
+		r.Middlewares = append(r.Middlewares, e.middleware...)
+		inv.Routes = append(inv.Routes, r)
+	}
+
+	sort.SliceStable(inv.Routes, func(i, j int) bool {
+		if inv.Routes[i].Path != inv.Routes[j].Path {
+			return inv.Routes[i].Path < inv.Routes[j].Path
+		}
+
+		return inv.Routes[i].Method < inv.Routes[j].Method
+	})
+
+	return inv
+}
+
+// MarkPublic sets Public on every route according to the api.public_routes of a config file.
+// Entries match a route exactly, entries ending with * match every route below them.
+func (inv *Inventory) MarkPublic(publicRoutes []string) {
+	for _, r := range inv.Routes {
+		public := false
+		for _, p := range publicRoutes {
+			if prefix, ok := strings.CutSuffix(p, "*"); ok {
+				public = public || strings.HasPrefix(r.Path, prefix)
+			} else {
+				public = public || r.Path == p
+			}
+		}
+
+		r.Public = &public
+	}
+}
+
+func lookupFunc(pkg *source.Package, recv, name string) (source.FuncDecl, bool) {
+	for _, fd := range pkg.Funcs() {
+		if fd.Decl.Name.Name == name && fd.Receiver() == recv {
+			return fd, true
+		}
+	}
+
+	return source.FuncDecl{}, false
+}
+
+func identName(e ast.Expr) string {
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return ""
+}
+
+func stringArg(call *ast.CallExpr, i int) string {
+	if len(call.Args) <= i {
+		return ""
+	}
+
+	lit, ok := call.Args[i].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+
+	s, _ := strconv.Unquote(lit.Value)
+	return s
+}
+
+// joinPath joins route prefixes the way fiber does, keeping the result rooted.
+func joinPath(prefix, p string) string {
+	if p == "" {
+		return prefix
+	}
+
+	joined := path.Join("/", prefix, p)
+	if strings.HasSuffix(p, "/") && joined != "/" {
+		joined += "/"
+	}
+
+	return joined
+}
+
+// hasPrefix reports whether a Use registered on prefix matches the route path.
+func hasPrefix(routePath, prefix string) bool {
+	if prefix == "" || prefix == "/" {
+		return true
+	}
+
+	return routePath == prefix || strings.HasPrefix(routePath, strings.TrimSuffix(prefix, "/")+"/")
+}
@@ -0,0 +1,272 @@
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+type (
+	// Module is a generated project parsed from disk. It only holds syntax trees, so
+	// gog can analyze a project even when its dependencies are not downloaded.
+	Module struct {
+		Dir      string
+		Path     string
+		Fset     *token.FileSet
+		Packages map[string]*Package
+	}
+
+	Package struct {
+		ImportPath string
+		Dir        string
+		Name       string
+		Files      []*File
+	}
+
+	File struct {
+		Path string
+		AST  *ast.File
+	}
+)
+
+// Load parses every non-test Go package of the module rooted at dir.
+// Hidden, vendor, testdata and underscore prefixed directories are skipped like the go tool does.
+func Load(dir string) (*Module, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to read go.mod in '%s': %w", dir, err)
+	}
+
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		return nil, fmt.Errorf("❌ Missing module directive in '%s'", filepath.Join(dir, "go.mod"))
+	}
+
+	m := &Module{
+		Dir:      dir,
+		Path:     modulePath,
+		Fset:     token.NewFileSet(),
+		Packages: map[string]*Package{},
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if p != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+
+			// nested modules are not part of this module
+			if p != dir {
+				if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+					return filepath.SkipDir
+				}
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+
+		f, err := parser.ParseFile(m.Fset, p, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+
+		importPath := modulePath
+		if rel != "." {
+			importPath = path.Join(modulePath, filepath.ToSlash(rel))
+		}
+
+		pkg, ok := m.Packages[importPath]
+		if !ok {
+			pkg = &Package{
+				ImportPath: importPath,
+				Dir:        filepath.Dir(p),
+				Name:       f.Name.Name,
+			}
+			m.Packages[importPath] = pkg
+		}
+
+		pkg.Files = append(pkg.Files, &File{Path: p, AST: f})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse project '%s': %w", dir, err)
+	}
+
+	return m, nil
+}
+
+// SortedPackages returns the packages ordered by import path so output is stable.
+func (m *Module) SortedPackages() []*Package {
+	pkgs := make([]*Package, 0, len(m.Packages))
+	for _, p := range m.Packages {
+		pkgs = append(pkgs, p)
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ImportPath < pkgs[j].ImportPath
+	})
+
+	return pkgs
+}
+
+// Position returns the position of pos with the filename relative to the module directory.
+func (m *Module) Position(pos token.Pos) token.Position {
+	p := m.Fset.Position(pos)
+	if rel, err := filepath.Rel(m.Dir, p.Filename); err == nil {
+		p.Filename = filepath.ToSlash(rel)
+	}
+
+	return p
+}
+
+// IsLocal reports whether importPath belongs to this module.
+func (m *Module) IsLocal(importPath string) bool {
+	return importPath == m.Path || strings.HasPrefix(importPath, m.Path+"/")
+}
+
+// Rel returns the import path relative to the module path (e.g. internal/domains/user).
+func (m *Module) Rel(importPath string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(importPath, m.Path), "/")
+}
+
+// Imports maps the names a file uses to refer to its imports to their import paths.
+func (f *File) Imports() map[string]string {
+	imports := map[string]string{}
+
+	for _, spec := range f.AST.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := ImportName(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		if name == "_" || name == "." {
+			continue
+		}
+
+		imports[name] = p
+	}
+
+	return imports
+}
+
+// ImportName guesses the package name of an import path the same way goimports does
+// for the common cases (e.g. github.com/gofiber/fiber/v2 -> fiber, go-nayla -> nayla).
+func ImportName(importPath string) string {
+	base := path.Base(importPath)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(importPath))
+	}
+
+	base = strings.TrimPrefix(base, "go-")
+	base = strings.TrimSuffix(base, ".go")
+
+	return strings.ReplaceAll(base, "-", "_")
+}
+
+// TypeSpecs returns all type declarations of the package keyed by name.
+func (p *Package) TypeSpecs() map[string]*ast.TypeSpec {
+	specs := map[string]*ast.TypeSpec{}
+
+	for _, f := range p.Files {
+		for _, decl := range f.AST.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				specs[ts.Name.Name] = ts
+			}
+		}
+	}
+
+	return specs
+}
+
+// Funcs returns all function and method declarations of the package together with their file.
+func (p *Package) Funcs() []FuncDecl {
+	var funcs []FuncDecl
+
+	for _, f := range p.Files {
+		for _, decl := range f.AST.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok {
+				funcs = append(funcs, FuncDecl{File: f, Decl: fd})
+			}
+		}
+	}
+
+	return funcs
+}
+
+// FuncDecl is a function declaration and the file it was declared in.
+type FuncDecl struct {
+	File *File
+	Decl *ast.FuncDecl
+}
+
+// Receiver returns the receiver type name of a method (without pointer) or "" for functions.
+func (fd FuncDecl) Receiver() string {
+	if fd.Decl.Recv == nil || len(fd.Decl.Recv.List) == 0 {
+		return ""
+	}
+
+	t := fd.Decl.Recv.List[0].Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+
+	if idx, ok := t.(*ast.IndexExpr); ok {
+		t = idx.X
+	}
+
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return ""
+}
+
+// ReceiverName returns the name of the receiver variable of a method (e.g. r for (r *Registry)).
+func (fd FuncDecl) ReceiverName() string {
+	if fd.Decl.Recv == nil || len(fd.Decl.Recv.List) == 0 || len(fd.Decl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+
+	return fd.Decl.Recv.List[0].Names[0].Name
+}
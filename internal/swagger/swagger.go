@@ -0,0 +1,108 @@
+// Package swagger generates the swagger docs of a project the way `just swagger` does.
+package swagger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/swaggo/swag"
+	"github.com/swaggo/swag/gen"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// MainFile is the file holding the general API info, relative to the project directory.
+	MainFile = "cmd/serve/serve.go"
+	// DocsDir is the output directory of the docs, relative to the project directory.
+	DocsDir = "docs"
+)
+
+// Files are the generated docs files.
+var Files = []string{"docs.go", "swagger.json", "swagger.yaml"}
+
+// Generate writes the docs of the project in dir to output.
+func Generate(dir, output string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	// a missing overrides file is only ignored under its default relative name
+	overrides := filepath.Join(absDir, gen.DefaultOverridesFile)
+	if _, err := os.Stat(overrides); err != nil {
+		overrides = ""
+	}
+
+	return gen.New().Build(&gen.Config{
+		SearchDir:          absDir,
+		MainAPIFile:        MainFile,
+		PropNamingStrategy: swag.CamelCase,
+		OutputDir:          output,
+		OutputTypes:        []string{"go", "json", "yaml"},
+		ParseDepth:         100,
+		OverridesFile:      overrides,
+		ParseGoList:        true,
+		LeftTemplateDelim:  "{{",
+		RightTemplateDelim: "}}",
+		Debugger:           log.New(io.Discard, "", log.LstdFlags),
+		CollectionFormat:   "csv",
+	})
+}
+
+// Stale generates the docs of the project in a temporary directory and returns the docs
+// files that differ from the generated ones. The specs are compared by value, swag versions
+// order the keys differently.
+func Stale(dir string) ([]string, error) {
+	tmp, err := os.MkdirTemp("", "gog-swagger-")
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// docs.go is named after its directory
+	output := filepath.Join(tmp, DocsDir)
+	if err := Generate(dir, output); err != nil {
+		return nil, fmt.Errorf("❌ Failed to generate swagger docs: %w", err)
+	}
+
+	var stale []string
+	for _, f := range Files {
+		got, err := os.ReadFile(filepath.Join(dir, DocsDir, f))
+		if os.IsNotExist(err) {
+			stale = append(stale, filepath.Join(DocsDir, f))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if filepath.Ext(f) == ".go" {
+			// docs.go embeds swagger.json
+			continue
+		}
+
+		want, err := os.ReadFile(filepath.Join(output, f))
+		if err != nil {
+			return nil, err
+		}
+
+		var gotSpec, wantSpec any
+		if err := yaml.Unmarshal(got, &gotSpec); err != nil {
+			stale = append(stale, filepath.Join(DocsDir, f))
+			continue
+		}
+		if err := yaml.Unmarshal(want, &wantSpec); err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(gotSpec, wantSpec) {
+			stale = append(stale, filepath.Join(DocsDir, f))
+		}
+	}
+
+	return stale, nil
+}
@@ -0,0 +1,139 @@
+// Package plugin helps writing gog plugins.
+//
+// A plugin is an executable named gog-<name> on the PATH or in ~/.config/gog/plugins, gog
+// runs it for `gog <name> [args]` with the project context in the environment:
+//
+//	func main() {
+//		plugin.Main(func(ctx *plugin.Context, args []string) error {
+//			return ctx.Scaffold(templates, "templates/ledger", "internal/domains/ledger", nil)
+//		})
+//	}
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nayla-finance/gog/internal/project"
+	"github.com/nayla-finance/gog/internal/report"
+)
+
+// Environment variables set by gog when running a plugin.
+const (
+	// EnvContext holds the Context as JSON
+	EnvContext = "GOG_CONTEXT"
+	// EnvVersion is the version of the gog binary running the plugin
+	EnvVersion = "GOG_VERSION"
+	// EnvProjectDir is the absolute directory of the project, empty outside a project
+	EnvProjectDir = "GOG_PROJECT_DIR"
+	// EnvModule is the module path of the project
+	EnvModule = "GOG_MODULE"
+)
+
+type (
+	// Context is the project a plugin runs in.
+	Context struct {
+		GogVersion string `json:"gog_version"`
+		// WorkDir is the directory gog was run from
+		WorkDir string `json:"work_dir"`
+		// ProjectDir is the directory of the go.mod above WorkDir, empty outside a project
+		ProjectDir string `json:"project_dir,omitempty"`
+		Module     string `json:"module,omitempty"`
+		// Template is read from the .gog/template.lock file of projects created by gog new
+		Template *Template `json:"template,omitempty"`
+	}
+
+	// Template is the template a project was generated from.
+	Template struct {
+		GogVersion   string    `json:"gog_version"`
+		Hash         string    `json:"hash"`
+		Name         string    `json:"name"`
+		Module       string    `json:"module"`
+		CreatedAt    time.Time `json:"created_at"`
+		SharedModule string    `json:"shared_module,omitempty"`
+		Excluded     []string  `json:"excluded,omitempty"`
+	}
+)
+
+// ReadContext returns the context gog passed to the plugin.
+func ReadContext() (*Context, error) {
+	data := os.Getenv(EnvContext)
+	if data == "" {
+		return nil, fmt.Errorf("❌ %s is not set, run the plugin through gog", EnvContext)
+	}
+
+	ctx := &Context{}
+	if err := json.Unmarshal([]byte(data), ctx); err != nil {
+		return nil, fmt.Errorf("❌ Failed to parse %s: %w", EnvContext, err)
+	}
+
+	return ctx, nil
+}
+
+// Main runs a plugin: it reads the context, calls run with the plugin arguments and exits
+// with status 1 when run fails.
+func Main(run func(ctx *Context, args []string) error) {
+	ctx, err := ReadContext()
+	if err == nil {
+		err = run(ctx, os.Args[1:])
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// ProjectName returns the name of the project, the one given to gog new when known.
+func (c *Context) ProjectName() string {
+	if c.Template != nil && c.Template.Name != "" {
+		return c.Template.Name
+	}
+
+	return filepath.Base(c.ProjectDir)
+}
+
+// Scaffold copies the files below root of fsys to dir, relative to the project directory. Like
+// gog new, the github.com/PROJECT_NAME and PROJECT_NAME placeholders are replaced with the
+// module and the name of the project, then the replacements are applied. Existing files are
+// never overwritten.
+func (c *Context) Scaffold(fsys fs.FS, root, dir string, replacements map[string]string) error {
+	if c.ProjectDir == "" {
+		return fmt.Errorf("❌ No project found in %s", c.WorkDir)
+	}
+
+	// the longest placeholders first so none is replaced inside another one
+	olds := make([]string, 0, len(replacements))
+	for old := range replacements {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool {
+		return len(olds[i]) > len(olds[j]) || (len(olds[i]) == len(olds[j]) && olds[i] < olds[j])
+	})
+
+	replaceFuncs := project.Placeholders(c.Module, c.ProjectName())
+	for _, old := range olds {
+		new := replacements[old]
+		replaceFuncs = append(replaceFuncs, func(data []byte) []byte {
+			return []byte(strings.ReplaceAll(string(data), old, new))
+		})
+	}
+
+	target := filepath.Join(c.ProjectDir, dir)
+
+	return project.CopyTemplate(fsys, root, target, replaceFuncs, func(relPath string) bool {
+		info, err := os.Stat(filepath.Join(target, relPath))
+		if err != nil || info.IsDir() {
+			return false
+		}
+
+		fmt.Printf("  ⏭️  Skipping existing file '%s'\n", filepath.Join(target, relPath))
+		return true
+	}, report.NewText(os.Stdout))
+}
@@ -0,0 +1,21 @@
+package config_cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and encrypt the configuration",
+	}
+
+	cmd.AddCommand(
+		newConfigPrint(),
+		newConfigEncrypt(),
+		newConfigDecrypt(),
+		newConfigRotateKey(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,122 @@
+package config_cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newConfigRotateKey() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key [config files]",
+		Short: "Generate a new config key and re-encrypt the enc: values with it",
+		Long: `Re-encrypts the enc: values of the config files, config.yaml and its profiles by default, with a
+new key. Every file is re-encrypted before any is written, a value the current key can not
+decrypt leaves them all untouched. The new key replaces the key file and the current one is kept
+as <key file>.old, update $` + config.KeyEnv + ` of the deployments from it.`,
+		Example: "PROJECT_NAME config rotate-key\nPROJECT_NAME config rotate-key config.production.yaml config.staging.yaml",
+		RunE:    runConfigRotateKey,
+	}
+
+	cmd.Flags().StringP("key-file", "k", config.DefaultKeyFile, "file of the config key, $"+config.KeyEnv+" takes precedence for the current key")
+
+	return cmd
+}
+
+func runConfigRotateKey(cmd *cobra.Command, args []string) error {
+	keyFile, err := cmd.Flags().GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get key file: %v", err)
+	}
+
+	files := args
+	if len(files) == 0 {
+		if files, err = configFiles(); err != nil {
+			return err
+		}
+	}
+
+	oldKey, err := config.LoadKey(keyFile)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := config.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	contents := map[string][]byte{}
+	for _, file := range files {
+		data, keys, err := config.Reencrypt(file, oldKey, newKey)
+		if err != nil {
+			return err
+		}
+
+		if len(keys) == 0 {
+			fmt.Printf("⏭️  %s has no encrypted value\n", file)
+			continue
+		}
+
+		contents[file] = data
+		fmt.Printf("🔄 Re-encrypted %s: %s\n", file, strings.Join(keys, ", "))
+	}
+
+	// the new key is written first, the files are never encrypted with a key that is lost
+	newKeyFile := keyFile + ".new"
+	if err := config.WriteKey(newKeyFile, newKey); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, ok := contents[file]
+		if !ok {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("❌ Failed to stat %s: %v", file, err)
+		}
+
+		if err := os.WriteFile(file, data, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("❌ Failed to write %s, the new key is %s: %v", file, newKeyFile, err)
+		}
+	}
+
+	if _, err := os.Stat(keyFile); err == nil {
+		if err := os.Rename(keyFile, keyFile+".old"); err != nil {
+			return fmt.Errorf("❌ Failed to keep the current key as %s.old: %v", keyFile, err)
+		}
+	}
+
+	if err := os.Rename(newKeyFile, keyFile); err != nil {
+		return fmt.Errorf("❌ Failed to replace %s by %s: %v", keyFile, newKeyFile, err)
+	}
+
+	fmt.Printf("✅ The config key %s is rotated, the previous one is %s.old\n", keyFile, keyFile)
+	if _, ok := os.LookupEnv(config.KeyEnv); ok {
+		fmt.Printf("⚠️ $%s holds the previous key, set it to the content of %s\n", config.KeyEnv, keyFile)
+	}
+
+	return nil
+}
+
+// configFiles returns config.yaml and its profiles.
+func configFiles() ([]string, error) {
+	profiles, err := filepath.Glob(config.ProfileFile("config.yaml", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("❌ Failed to list the config profiles: %v", err)
+	}
+
+	var files []string
+	if _, err := os.Stat("config.yaml"); err == nil {
+		files = append(files, "config.yaml")
+	}
+
+	return append(files, profiles...), nil
+}
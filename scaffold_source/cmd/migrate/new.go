@@ -23,7 +23,12 @@ func newMigrationNew() *cobra.Command {
 				return fmt.Errorf("❌ Failed to get config file: %v", err)
 			}
 
-			cfg, err := config.Load(configFile)
+			profile, err := cmd.Flags().GetString("profile")
+			if err != nil {
+				return fmt.Errorf("❌ Failed to get profile: %v", err)
+			}
+
+			cfg, err := config.Load(configFile, config.WithProfile(profile))
 			if err != nil {
 				return fmt.Errorf("❌ Failed to load configuration")
 			}
@@ -39,6 +44,7 @@ func newMigrationNew() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
 
 	return cmd
 }
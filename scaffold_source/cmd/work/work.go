@@ -0,0 +1,142 @@
+package work
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/lifecycle"
+	"github.com/PROJECT_NAME/internal/registry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cobra"
+)
+
+func NewWorkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "work",
+		Short: "Start the NATS consumers without the API",
+		Long:  "Start the NATS consumers without the API, the health checks and the metrics are served on worker.admin_port. Run the API pods with serve --no-consumers to scale them apart.",
+		RunE:  Run,
+	}
+
+	cmd.Flags().StringP("config", "c", "config.yaml", "config file")
+	cmd.Flags().StringP("profile", "p", "", "config profile merged over the config file, e.g. production for config.production.yaml (default $APP__ENV)")
+	cmd.Flags().Bool("watch-config", false, "reload the config when the file changes, it is always reloaded on SIGHUP")
+
+	return cmd
+}
+
+func Run(cmd *cobra.Command, args []string) error {
+	configFile, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get config file: %v", err)
+	}
+
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get profile: %v", err)
+	}
+
+	watchConfig, err := cmd.Flags().GetBool("watch-config")
+	if err != nil {
+		return fmt.Errorf("❌ Failed to get watch-config: %v", err)
+	}
+
+	watcher, err := config.NewWatcher(configFile, config.WithProfile(profile))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to load configuration: %v", err)
+	}
+	cfg := watcher.Config()
+
+	r := registry.NewRegistry(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.InitializeHeadless(ctx); err != nil {
+		return err
+	}
+
+	if err := r.StartConsumers(ctx); err != nil {
+		return err
+	}
+
+	if err := r.WatchConfig(watcher, watchConfig); err != nil {
+		return err
+	}
+
+	// The worker has no API, it only serves the health checks and the metrics
+	app := NewAdminApp(cfg, r)
+
+	serverErr := make(chan error, 1)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	// The admin server is started last and stopped first, before the consumers are drained and
+	// the database is closed
+	if err := r.Lifecycle().Register(NewAdminComponent(cfg, app, serverErr)); err != nil {
+		return err
+	}
+
+	if err := r.Lifecycle().Start(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-serverErr:
+		if cleanupErr := r.Cleanup(); cleanupErr != nil {
+			r.Logger().Errorw(context.Background(), "Error during cleanup", "error", cleanupErr)
+		}
+
+		return fmt.Errorf("admin server error: %w", err)
+	case sig := <-sigChan:
+		r.Logger().Infow(context.Background(), "Received shutdown signal", "signal", sig)
+
+		// Stops the admin server, drains the consumers and closes the DB connections
+		if err := r.Cleanup(); err != nil {
+			r.Logger().Errorw(context.Background(), "Error during graceful shutdown", "error", err)
+			return err
+		}
+
+		r.Logger().Infow(context.Background(), "Graceful shutdown completed")
+	}
+
+	return nil
+}
+
+// NewAdminComponent runs the admin app on worker.admin_port, Listen errors after the start are
+// sent to serverErr.
+func NewAdminComponent(cfg *config.Config, app *fiber.App, serverErr chan<- error) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      "admin",
+		DependsOn: []string{registry.ComponentConsumers},
+		Start: func(ctx context.Context) error {
+			go func() {
+				if err := app.Listen(fmt.Sprintf(":%d", cfg.Worker.AdminPort)); err != nil {
+					serverErr <- err
+				}
+			}()
+
+			return nil
+		},
+		Stop:    app.ShutdownWithContext,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// NewAdminApp serves /healthz and /metrics only.
+func NewAdminApp(cfg *config.Config, r *registry.Registry) *fiber.App {
+	app := fiber.New(fiber.Config{
+		AppName:               cfg.App.Name,
+		ErrorHandler:          r.ErrorHandler().Handle,
+		DisableStartupMessage: true,
+	})
+
+	r.RegisterAdminRoutes(app)
+
+	return app
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "<redacted>"
+
+// secretWords mark the keys whose values are secrets, they are matched against the last
+// segment of the key.
+var secretWords = []string{"password", "secret", "token", "api_key", "apikey", "admin_key", "dsn", "private_key"}
+
+// Print writes the effective config as YAML, i.e. the config file with the profile, the
+// environment overrides, the defaults and the references resolved. The secrets are redacted,
+// the values read from a reference show the reference instead. A config that fails validation
+// is printed too, its *ValidationError is returned once printed.
+func Print(w io.Writer, configFile string, opts ...Option) error {
+	v, refs, err := read(configFile, newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	cfg, validationErr := decode(v)
+	if cfg == nil {
+		return validationErr
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(toNode(reflect.ValueOf(*cfg), "", refs)); err != nil {
+		return fmt.Errorf("❌ Failed to print the config: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	return validationErr
+}
+
+// toNode converts a value of the config to YAML, the fields keep their order and are named
+// by their mapstructure tag, like in the config file.
+func toNode(v reflect.Value, key string, refs map[string]string) *yaml.Node {
+	if ref, ok := refs[key]; ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: redacted + " " + ref}
+	}
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: time.Duration(v.Int()).String()}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+		return toNode(v.Elem(), key, refs)
+	case reflect.Struct:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "-" {
+				continue
+			}
+
+			if opts == "squash" {
+				n.Content = append(n.Content, toNode(v.Field(i), key, refs).Content...)
+				continue
+			}
+
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+				toNode(v.Field(i), join(key, name), refs),
+			)
+		}
+		return n
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]reflect.Value{}
+		for _, k := range v.MapKeys() {
+			name := fmt.Sprint(k.Interface())
+			keys = append(keys, name)
+			values[name] = v.MapIndex(k)
+		}
+		slices.Sort(keys)
+
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range keys {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+				toNode(values[name], join(key, strings.ToLower(name)), refs),
+			)
+		}
+		return n
+	case reflect.Slice, reflect.Array:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			n.Content = append(n.Content, toNode(v.Index(i), key, refs))
+		}
+		return n
+	case reflect.String:
+		value := v.String()
+		if value != "" && isSecret(key) {
+			value = redacted
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v.Interface())}
+	}
+}
+
+// isSecret reports whether the value of a key is a secret, e.g. database.password.
+func isSecret(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	if name == "key" {
+		return true
+	}
+
+	for _, w := range secretWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func join(key string, name string) string {
+	if key == "" {
+		return name
+	}
+
+	return key + "." + name
+}
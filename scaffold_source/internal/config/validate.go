@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// squashed names the squashed structs in the validator namespaces, it is not a valid key.
+const squashed = "<squashed>"
+
+type (
+	// Problem is a key of the config that can not be decoded or fails validation.
+	Problem struct {
+		// Key is the path of the key in the config file, e.g. database.password
+		Key string
+		// Env is the environment variable overriding the key, e.g. DATABASE__PASSWORD
+		Env     string
+		Message string
+	}
+
+	// ValidationError lists every problem of the config, not only the first one.
+	ValidationError struct {
+		Problems []Problem
+	}
+)
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s (%s): %s", p.Key, p.Env, p.Message)
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  - " + p.String()
+	}
+
+	return fmt.Sprintf("❌ The config has %d problem(s):\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// problems collects the problems of the config.
+type problems []Problem
+
+func (ps *problems) add(key string, format string, args ...any) {
+	*ps = append(*ps, Problem{Key: key, Env: envName(key), Message: fmt.Sprintf(format, args...)})
+}
+
+func (ps problems) has(key string) bool {
+	return slices.ContainsFunc(ps, func(p Problem) bool { return p.Key == key })
+}
+
+// err returns the problems sorted by key, nil when there is none.
+func (ps problems) err() error {
+	if len(ps) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(ps, func(a, b Problem) int { return strings.Compare(a.Key, b.Key) })
+
+	return &ValidationError{Problems: ps}
+}
+
+// bindEnv binds the environment variable of every key of the config: viper only reads the
+// environment for the keys it knows, and the config runs from the environment alone when
+// there is no config file.
+func bindEnv(v *viper.Viper) {
+	leaves(reflect.TypeOf(Config{}), "", func(key string, t reflect.Type) {
+		// the error is only returned without a key
+		_ = v.BindEnv(key)
+	})
+}
+
+// decodeKeys decodes every key read by v on its own, like v.Unmarshal does, to report all the
+// values of the wrong type instead of the first one.
+func decodeKeys(v *viper.Viper, ps *problems) {
+	leaves(reflect.TypeOf(Config{}), "", func(key string, t reflect.Type) {
+		raw := v.Get(key)
+		if raw == nil {
+			return
+		}
+
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				mapstructure.StringToTimeDurationHookFunc(),
+				mapstructure.StringToSliceHookFunc(","),
+			),
+			WeaklyTypedInput: true,
+			Result:           reflect.New(t).Interface(),
+		})
+		if err != nil {
+			ps.add(key, "%v", err)
+			return
+		}
+
+		if err := decoder.Decode(raw); err != nil {
+			if t == durationType {
+				ps.add(key, "expected a duration, e.g. 500ms or 1m, got %q", fmt.Sprint(raw))
+			} else {
+				ps.add(key, "expected %s, got %q", t, fmt.Sprint(raw))
+			}
+		}
+	})
+}
+
+// validate checks the validate tags of the decoded config and the values the tags can not
+// check: the timezones and the ports. The paths are read by the components using them, e.g.
+// nats.creds_path when NATS connects, gog config validate checks them.
+func validate(c *Config, ps *problems) {
+	tags := validator.New(validator.WithRequiredStructEnabled())
+	tags.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if opts == "squash" {
+			// the validator names the squashed structs too, they are removed from the keys below
+			return squashed
+		}
+		if name == "" {
+			return strings.ToLower(field.Name)
+		}
+		return name
+	})
+
+	var errs validator.ValidationErrors
+	if err := tags.Struct(c); errors.As(err, &errs) {
+		for _, fe := range errs {
+			// the namespace starts with the struct, e.g. Config.database.password, and the keys of
+			// the maps are in brackets, e.g. Config.feature_flags.flags[new_kyc_flow].percentage
+			_, key, _ := strings.Cut(fe.Namespace(), ".")
+			key = strings.ReplaceAll(key, squashed+".", "")
+			key = strings.NewReplacer("[", ".", "]", "").Replace(key)
+			if !ps.has(key) {
+				ps.add(key, "%s", ruleMessage(fe))
+			}
+		}
+	}
+
+	timezones := map[string]string{"app.timezone": c.App.Timezone}
+	// database.timezone defaults to app.timezone, the problem is reported once
+	if c.Database.Timezone != c.App.Timezone {
+		timezones["database.timezone"] = c.Database.Timezone
+	}
+
+	for key, tz := range timezones {
+		if _, err := time.LoadLocation(tz); err != nil && !ps.has(key) {
+			ps.add(key, "unknown timezone %q, e.g. Asia/Riyadh or UTC", tz)
+		}
+	}
+
+	for key, port := range map[string]int{"app.port": c.App.Port, "database.port": c.Database.Port, "worker.admin_port": c.Worker.AdminPort} {
+		if (port < 1 || port > 65535) && !ps.has(key) {
+			ps.add(key, "must be a port between 1 and 65535, got %d", port)
+		}
+	}
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s, got %v", fe.Param(), fe.Value())
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s, got %v", fe.Param(), fe.Value())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(strings.Fields(fe.Param()), ", "), fmt.Sprint(fe.Value()))
+	default:
+		return fmt.Sprintf("fails the %s rule, got %v", fe.Tag(), fe.Value())
+	}
+}
+
+// leaves calls fn with the key and the type of every value of the struct t that is not a
+// struct itself, e.g. app.retry_delay. The squashed structs are inlined like mapstructure does.
+func leaves(t reflect.Type, key string, fn func(key string, t reflect.Type)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if opts == "squash" {
+			leaves(field.Type, key, fn)
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			leaves(field.Type, join(key, name), fn)
+			continue
+		}
+
+		fn(join(key, name), field.Type)
+	}
+}
+
+// envName returns the environment variable of a key, . is replaced by __.
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "__"))
+}
@@ -0,0 +1,54 @@
+package health_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/PROJECT_NAME/internal/registry"
+	nconfig "github.com/nayla-finance/go-nayla/config"
+)
+
+// fakeDB is a database answering the pings with err, the other methods are not used by the
+// health checks.
+type fakeDB struct {
+	db.Database
+	err error
+}
+
+func (d fakeDB) Ping() error {
+	return d.err
+}
+
+func TestReadinessCheck(t *testing.T) {
+	errDown := stderrors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		db      fakeDB
+		wantErr error
+	}{
+		{name: "database up", db: fakeDB{}},
+		{name: "database down", db: fakeDB{err: errDown}, wantErr: errDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := registry.NewTestRegistry(t,
+				registry.WithDB(tt.db),
+				registry.WithConfig(func(c *config.Config) {
+					c.Health.Dependencies = nconfig.Dependencies{
+						"database": {ReadinessCheck: true},
+					}
+				}),
+			)
+
+			err := r.HealthService().ReadinessCheck(context.Background())
+			if !stderrors.Is(err, tt.wantErr) {
+				t.Errorf("ReadinessCheck() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,12 +1,22 @@
 package model
 
-type Signal chan SignalPayload
-type SignalType int
+import (
+	"github.com/PROJECT_NAME/internal/bus"
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/featureflags"
+)
 
-const (
-	SignalTypeNatsConsumerRestart SignalType = iota + 1
+// The signals published in the service, subscribe to them in Registry.subscribeSignals.
+var (
+	SignalNatsConsumerRestart = bus.NewSignal[NatsConsumerRestart]("nats.consumer_restart")
+	// SignalConfigChanged is published when a reload changes hot keys, see config.Watcher
+	SignalConfigChanged = bus.NewSignal[config.Change]("config.changed")
+	// SignalFeatureFlagsChanged is published when a reload changes feature flags, e.g. toggled
+	// through the admin API or the config
+	SignalFeatureFlagsChanged = bus.NewSignal[featureflags.Change]("feature_flags.changed")
 )
 
-type SignalPayload struct {
-	Type SignalType
+// NatsConsumerRestart is published by the NATS monitoring when a consumer is restarted.
+type NatsConsumerRestart struct {
+	Consumer string
 }
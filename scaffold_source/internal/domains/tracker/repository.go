@@ -11,8 +11,8 @@ var _ Repository = &repo{}
 
 type (
 	Repository interface {
-		Create(ctx context.Context, tracker *Tracker) error
-		GetByID(ctx context.Context, id uuid.UUID) (*Tracker, error)
+		create(ctx context.Context, tracker *Tracker) error
+		getByID(ctx context.Context, id uuid.UUID) (*Tracker, error)
 	}
 
 	RepositoryProvider interface {
@@ -32,7 +32,7 @@ func NewRepository(d repoDependencies) Repository {
 	return &repo{d: d}
 }
 
-func (r *repo) Create(ctx context.Context, tracker *Tracker) error {
+func (r *repo) create(ctx context.Context, tracker *Tracker) error {
 	query := `
 		INSERT INTO vendor_tracker (id, is_success, path, method, request_body, response_body, response_time_ms)
 		VALUES (:id, :is_success, :path, :method, :request_body, :response_body, :response_time_ms)
@@ -43,7 +43,7 @@ func (r *repo) Create(ctx context.Context, tracker *Tracker) error {
 	return err
 }
 
-func (r *repo) GetByID(ctx context.Context, id uuid.UUID) (*Tracker, error) {
+func (r *repo) getByID(ctx context.Context, id uuid.UUID) (*Tracker, error) {
 	query := `
 		SELECT * FROM vendor_tracker WHERE id = $1
 	`
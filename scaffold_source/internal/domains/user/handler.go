@@ -45,7 +45,7 @@ func (h *Handler) RegisterRoutes(api fiber.Router) {
 // @Failure		500	{object}	errors.ErrorResponse
 // @Router			/users [get]
 func (h *Handler) getUsers(c *fiber.Ctx) error {
-	users, err := h.d.UserService().GetUsers(c.Context())
+	users, err := h.d.UserService().GetUsers(c.UserContext())
 	if err != nil {
 		return h.d.NewError(errors.ErrInternal, err.Error())
 	}
@@ -73,7 +73,7 @@ func (h *Handler) createUser(c *fiber.Ctx) error {
 		return h.d.NewError(errors.ErrBadRequest, err.Error())
 	}
 
-	if err := h.d.UserService().CreateUser(c.Context(), dto); err != nil {
+	if err := h.d.UserService().CreateUser(c.UserContext(), dto); err != nil {
 		return h.d.NewError(errors.ErrInternal, err.Error())
 	}
 
@@ -98,7 +98,7 @@ func (h *Handler) getUser(c *fiber.Ctx) error {
 	}
 
 	var user *model.User
-	if err := h.d.UserService().GetUserByID(c.Context(), id, user); err != nil {
+	if err := h.d.UserService().GetUserByID(c.UserContext(), id, user); err != nil {
 		return h.d.NewError(errors.ErrInternal, err.Error())
 	}
 
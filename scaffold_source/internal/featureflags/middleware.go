@@ -0,0 +1,35 @@
+package featureflags
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserIDHeader identifies the end user of a request. It is not authenticated, so the service
+// must run behind a trusted gateway setting it for the authenticated user and dropping the one
+// sent by the client, otherwise a caller picks the user its flags are evaluated for.
+const UserIDHeader = "X-User-ID"
+
+// Middleware sets the subject of the flags to the API key and the user ID of the request,
+// register it after the auth middleware.
+func Middleware(c *fiber.Ctx) error {
+	s := Subject{}
+
+	apiKey := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if apiKey == "" {
+		apiKey = c.Get("X-API-Key")
+	}
+
+	if apiKey != "" {
+		s[AttributeAPIKey] = apiKey
+	}
+
+	if userID := c.Get(UserIDHeader); userID != "" {
+		s[AttributeUserID] = userID
+	}
+
+	c.SetUserContext(WithSubject(c.UserContext(), s))
+
+	return c.Next()
+}
@@ -0,0 +1,117 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/lib/pq"
+	"github.com/nayla-finance/go-nayla/logger"
+)
+
+// undefinedTable is the code of the Postgres error of a missing table.
+const undefinedTable = "42P01"
+
+type (
+	repositoryDependencies interface {
+		logger.Provider
+		db.DBProvider
+	}
+
+	repository struct {
+		d repositoryDependencies
+		// tableMissing is set while the feature_flags table does not exist
+		tableMissing atomic.Bool
+	}
+
+	// row is a flag of the feature_flags table, the attributes are JSON.
+	row struct {
+		Name       string    `db:"name"`
+		Enabled    bool      `db:"enabled"`
+		Percentage *int      `db:"percentage"`
+		Attributes string    `db:"attributes"`
+		UpdatedAt  time.Time `db:"updated_at"`
+	}
+)
+
+func newRepository(d repositoryDependencies) *repository {
+	return &repository{
+		d: d,
+	}
+}
+
+// list returns the flags of the table, none while the table does not exist: the service can
+// start before the migrations create it, the flags of the config and the environment apply
+// until then.
+func (r *repository) list(ctx context.Context) (map[string]Flag, error) {
+	var rows []row
+	if err := r.d.DB().GetConn().SelectContext(ctx, &rows, "SELECT name, enabled, percentage, attributes, updated_at FROM feature_flags"); err != nil {
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || pqErr.Code != undefinedTable {
+			return nil, err
+		}
+
+		if !r.tableMissing.Swap(true) {
+			r.d.Logger().Warnw(ctx, "⚠️ The feature_flags table does not exist, the flags of the database are ignored until the migrations are applied")
+		}
+
+		return map[string]Flag{}, nil
+	}
+	r.tableMissing.Store(false)
+
+	flags := map[string]Flag{}
+	for _, row := range rows {
+		flag := Flag{Enabled: row.Enabled, Percentage: row.Percentage}
+		if err := json.Unmarshal([]byte(row.Attributes), &flag.Attributes); err != nil {
+			return nil, fmt.Errorf("❌ Invalid attributes of the feature flag %s: %w", row.Name, err)
+		}
+
+		if len(flag.Attributes) == 0 {
+			flag.Attributes = nil
+		}
+
+		flags[row.Name] = flag
+	}
+
+	return flags, nil
+}
+
+func (r *repository) upsert(ctx context.Context, name string, flag Flag) error {
+	attributes := []byte("{}")
+	if flag.Attributes != nil {
+		var err error
+		if attributes, err = json.Marshal(flag.Attributes); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.d.DB().GetConn().NamedExecContext(ctx, `INSERT INTO feature_flags (name, enabled, percentage, attributes, updated_at)
+		VALUES (:name, :enabled, :percentage, :attributes, :updated_at)
+		ON CONFLICT (name) DO UPDATE SET enabled = :enabled, percentage = :percentage, attributes = :attributes, updated_at = :updated_at`, row{
+		Name:       name,
+		Enabled:    flag.Enabled,
+		Percentage: flag.Percentage,
+		Attributes: string(attributes),
+		UpdatedAt:  time.Now().UTC(),
+	})
+
+	return err
+}
+
+// delete returns ErrNotStored when the flag is not in the table.
+func (r *repository) delete(ctx context.Context, name string) error {
+	result, err := r.d.DB().GetConn().ExecContext(ctx, "DELETE FROM feature_flags WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotStored
+	}
+
+	return nil
+}
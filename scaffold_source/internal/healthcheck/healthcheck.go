@@ -0,0 +1,109 @@
+// Package healthcheck runs the readiness and liveness checks of the dependencies of a service,
+// it does not know the service: the health domain lists its dependencies and how to ping them.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/nayla-finance/go-nayla/config"
+	"github.com/nayla-finance/go-nayla/logger"
+)
+
+type (
+	// Dependency is checked when its name is in health.dependencies, with the checks enabled
+	// there.
+	Dependency struct {
+		// Name is the key of the dependency in health.dependencies, e.g. database
+		Name string
+		// Label names the dependency in the logs and the errors, e.g. KYC client
+		Label string
+		// Critical dependencies fail the liveness check, the others are only logged. The
+		// readiness check fails on every dependency.
+		Critical bool
+		Ping     func(ctx context.Context) error
+
+		// ReadyLog and AliveLog are logged on success when the verbose logs are enabled
+		ReadyLog string
+		AliveLog string
+	}
+)
+
+// Ready returns the error of the first dependency that is not ready.
+func Ready(ctx context.Context, l logger.Logger, cfg config.Health, deps []Dependency) error {
+	isVerbose := cfg.Readiness.VerboseLog
+	if isVerbose {
+		PrintDependencies(ctx, l, cfg)
+	}
+
+	for _, dep := range deps {
+		if check, ok := cfg.Dependencies[dep.Name]; !ok || !check.ReadinessCheck {
+			continue
+		}
+
+		if err := dep.Ping(ctx); err != nil {
+			l.Errorw(ctx, fmt.Sprintf("❌ %s is not ready", dep.Label), "error", err)
+
+			sentry.CaptureException(fmt.Errorf("❌ %s is not ready: %w", dep.Label, err))
+			// 🚨 Readiness check for internal and external dependencies should return an error if they fail
+			return err
+		} else if isVerbose && dep.ReadyLog != "" {
+			l.Infow(ctx, dep.ReadyLog)
+		}
+	}
+
+	l.Infow(ctx, "✅ All service dependencies are healthy and having a great day! 🎉 Time to get back to some serious SMS business!")
+
+	return nil
+}
+
+// Alive returns the error of the first critical dependency that is not alive, the other
+// dependencies are only logged.
+func Alive(ctx context.Context, l logger.Logger, cfg config.Health, deps []Dependency) error {
+	isVerbose := cfg.Liveness.VerboseLog
+	if isVerbose {
+		PrintDependencies(ctx, l, cfg)
+	}
+
+	var failedServices []string
+
+	for _, dep := range deps {
+		if check, ok := cfg.Dependencies[dep.Name]; !ok || !check.LivenessCheck {
+			continue
+		}
+
+		if err := dep.Ping(ctx); err != nil {
+			l.Errorw(ctx, fmt.Sprintf("❌ %s is not healthy", dep.Label), "error", err)
+			sentry.CaptureException(fmt.Errorf("❌ %s is not healthy: %w", dep.Label, err))
+			if dep.Critical {
+				return fmt.Errorf("❌ Critical service %s is not healthy: %w", dep.Label, err)
+			}
+			failedServices = append(failedServices, dep.Label)
+		} else if isVerbose && dep.AliveLog != "" {
+			l.Infow(ctx, dep.AliveLog)
+		}
+	}
+
+	// Only log success if no services failed
+	if len(failedServices) == 0 {
+		l.Infow(ctx, "✅ All service dependencies are healthy and having a great day! 🎉 Time to get back to some serious business!")
+	} else {
+		l.Warnw(ctx, "⚠️ Some services are not healthy, but service is still operational",
+			"failed_services", failedServices,
+			"total_failed", len(failedServices))
+	}
+
+	return nil
+}
+
+// PrintDependencies logs the dependencies of health.dependencies and their checks.
+func PrintDependencies(ctx context.Context, l logger.Logger, cfg config.Health) {
+	l.Infow(ctx, "This Service Depends on the following dependencies:")
+
+	for dep, check := range cfg.Dependencies {
+		depName := strings.ToLower(dep)
+		l.Infow(ctx, "🔗 Dependency", "name", depName, "readiness_check", check.ReadinessCheck, "liveness_check", check.LivenessCheck)
+	}
+}
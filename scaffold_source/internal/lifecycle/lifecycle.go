@@ -0,0 +1,283 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nayla-finance/go-nayla/logger"
+)
+
+// DefaultTimeout bounds the Start and Stop of the components without a Timeout.
+const DefaultTimeout = 10 * time.Second
+
+const (
+	StatePending  State = "pending"
+	StateRunning  State = "running"
+	StateFailed   State = "failed"
+	StateStopping State = "stopping"
+	StateStopped  State = "stopped"
+)
+
+var _ Manager = new(manager)
+
+type (
+	State string
+
+	// Component is a part of the service with a start and a stop, e.g. the database or the
+	// HTTP server. It is started after the components it depends on and stopped before them.
+	Component struct {
+		Name string
+		// DependsOn are the names of the components started before this one
+		DependsOn []string
+		// Start must return once the component is running, e.g. run a server in a goroutine
+		Start func(ctx context.Context) error
+		// Stop releases the component, it is only called when Start succeeded
+		Stop func(ctx context.Context) error
+		// Timeout bounds Start and Stop, DefaultTimeout when zero
+		Timeout time.Duration
+	}
+
+	Manager interface {
+		// Register adds components, they are started by the next Start
+		Register(components ...Component) error
+		// Start starts the registered components that are not running yet, in the order of
+		// their dependencies. When one fails, every running component is stopped.
+		Start(ctx context.Context) error
+		// Stop stops the running components in the reverse order they were started, a
+		// component failing to stop does not prevent the others from stopping
+		Stop(ctx context.Context) error
+		// Ready returns an error when a component is not running or the manager is stopping
+		Ready() error
+		// State returns the state of a component
+		State(name string) State
+	}
+
+	ManagerProvider interface {
+		Lifecycle() Manager
+	}
+
+	managerDependencies interface {
+		logger.Provider
+	}
+
+	component struct {
+		Component
+		state State
+	}
+
+	manager struct {
+		d          managerDependencies
+		mu         sync.Mutex
+		components []*component
+		byName     map[string]*component
+		// started are the running components in the order they were started
+		started  []*component
+		stopping bool
+	}
+)
+
+func NewManager(d managerDependencies) *manager {
+	return &manager{
+		d:      d,
+		byName: map[string]*component{},
+	}
+}
+
+func (m *manager) Register(components ...Component) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range components {
+		if c.Name == "" {
+			return fmt.Errorf("❌ A component has no name")
+		}
+
+		if _, ok := m.byName[c.Name]; ok {
+			return fmt.Errorf("❌ Component %s is already registered", c.Name)
+		}
+
+		if c.Timeout == 0 {
+			c.Timeout = DefaultTimeout
+		}
+
+		cp := &component{Component: c, state: StatePending}
+		m.components = append(m.components, cp)
+		m.byName[c.Name] = cp
+	}
+
+	return nil
+}
+
+func (m *manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopping {
+		return fmt.Errorf("❌ Failed to start the components: the service is stopping")
+	}
+
+	order, err := m.order()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range order {
+		if c.state == StateRunning {
+			continue
+		}
+
+		m.d.Logger().Debugw(ctx, "🚀 Starting component", "component", c.Name)
+		start := time.Now()
+
+		if err := run(ctx, c.Timeout, c.Start); err != nil {
+			c.state = StateFailed
+			m.d.Logger().Errorw(ctx, "❌ Failed to start component", "component", c.Name, "error", err)
+
+			// the service can not run without it, release what is already running
+			if stopErr := m.stop(ctx); stopErr != nil {
+				return errors.Join(fmt.Errorf("❌ Failed to start %s: %w", c.Name, err), stopErr)
+			}
+			return fmt.Errorf("❌ Failed to start %s: %w", c.Name, err)
+		}
+
+		c.state = StateRunning
+		m.started = append(m.started, c)
+		m.d.Logger().Infow(ctx, "✅ Component started", "component", c.Name, "duration", time.Since(start).String())
+	}
+
+	return nil
+}
+
+func (m *manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopping = true
+
+	return m.stop(ctx)
+}
+
+// stop stops the started components in reverse order, m.mu must be held.
+func (m *manager) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		c := m.started[i]
+		c.state = StateStopping
+
+		m.d.Logger().Infow(ctx, "🔌 Stopping component", "component", c.Name)
+		if err := run(ctx, c.Timeout, c.Stop); err != nil {
+			c.state = StateFailed
+			m.d.Logger().Errorw(ctx, "❌ Failed to stop component", "component", c.Name, "error", err)
+			errs = append(errs, fmt.Errorf("❌ Failed to stop %s: %w", c.Name, err))
+			continue
+		}
+
+		c.state = StateStopped
+	}
+	m.started = nil
+
+	return errors.Join(errs...)
+}
+
+func (m *manager) Ready() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopping {
+		return fmt.Errorf("❌ The service is shutting down")
+	}
+
+	for _, c := range m.components {
+		if c.state != StateRunning {
+			return fmt.Errorf("❌ Component %s is %s", c.Name, c.state)
+		}
+	}
+
+	return nil
+}
+
+func (m *manager) State(name string) State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.byName[name]
+	if !ok {
+		return ""
+	}
+
+	return c.state
+}
+
+// order returns the components sorted by their dependencies, the components without a
+// dependency between them keep the order they were registered in.
+func (m *manager) order() ([]*component, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var order []*component
+	marks := map[string]int{}
+
+	var visit func(c *component, path []string) error
+	visit = func(c *component, path []string) error {
+		path = append(path, c.Name)
+
+		switch marks[c.Name] {
+		case visiting:
+			return fmt.Errorf("❌ The components depend on each other: %s", strings.Join(path, " → "))
+		case visited:
+			return nil
+		}
+
+		marks[c.Name] = visiting
+		for _, name := range c.DependsOn {
+			dep, ok := m.byName[name]
+			if !ok {
+				return fmt.Errorf("❌ Component %s depends on %s which is not registered", c.Name, name)
+			}
+
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		marks[c.Name] = visited
+
+		order = append(order, c)
+		return nil
+	}
+
+	for _, c := range m.components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// run calls fn with a timeout, it returns when the timeout expires even if fn ignores ctx.
+func run(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s: %w", timeout, ctx.Err())
+	}
+}
@@ -2,24 +2,24 @@ package registry
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
+	"github.com/PROJECT_NAME/internal/bus"
 	"github.com/PROJECT_NAME/internal/config"
 	"github.com/PROJECT_NAME/internal/db"
 	"github.com/PROJECT_NAME/internal/domains/health"
 	"github.com/PROJECT_NAME/internal/domains/interfaces"
-	"github.com/PROJECT_NAME/internal/domains/model"
 	"github.com/PROJECT_NAME/internal/domains/post"
 	"github.com/PROJECT_NAME/internal/domains/user"
 	"github.com/PROJECT_NAME/internal/errors"
+	"github.com/PROJECT_NAME/internal/featureflags"
+	"github.com/PROJECT_NAME/internal/lifecycle"
 	"github.com/getsentry/sentry-go"
-	sentryfiber "github.com/getsentry/sentry-go/fiber"
-	"github.com/gofiber/contrib/otelfiber"
 	"github.com/gofiber/fiber/v2"
 	"github.com/nayla-finance/go-nayla/clients/rest/kyc"
 	"github.com/nayla-finance/go-nayla/clients/rest/los"
 	"github.com/nayla-finance/go-nayla/logger"
-	"github.com/nayla-finance/go-nayla/middleware"
 	"github.com/nayla-finance/go-nayla/nats"
 	"github.com/nayla-finance/go-nayla/otel"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -30,31 +30,37 @@ import (
 var _ RegistryProvider = new(Registry)
 
 type Registry struct {
-	signal model.Signal
+	bus bus.Bus
 
-	db     db.Database
-	config *config.Config
-	logger logger.Logger
+	db db.Database
+	// config and logger are swapped when the config is reloaded, see WatchConfig
+	config atomic.Pointer[config.Config]
+	logger atomic.Pointer[logger.Logger]
 
 	// errors
-	errorHandler *errors.Handler
+	errorHandler Lazy[*errors.Handler]
 
-	healthService health.Service
+	healthService Lazy[health.Service]
+
+	flags Lazy[featureflags.Flags]
 
 	natsService nats.Service
 
 	// domains
-	userRepository user.Repository
-	userService    interfaces.UserService
+	userRepository Lazy[user.Repository]
+	userService    Lazy[interfaces.UserService]
 
-	postRepository post.Repository
-	postService    interfaces.PostService
+	postRepository Lazy[post.Repository]
+	postService    Lazy[interfaces.PostService]
 
 	kycClient kyc.Client
 	losClient los.Client
 
 	// otel
 	otelClient *otel.Client
+
+	// lifecycle starts and stops the components, e.g. the database and the servers
+	lifecycle lifecycle.Manager
 }
 
 // Uncomment if you need child spans
@@ -66,154 +72,62 @@ type Registry struct {
 // }
 
 func NewRegistry(c *config.Config) *Registry {
-	return &Registry{
-		signal: make(model.Signal, 10),
-		config: c,
-	}
-}
+	r := &Registry{}
+	r.config.Store(c)
+	r.bus = bus.NewBus(r)
+	r.lifecycle = lifecycle.NewManager(r)
 
-func (r *Registry) InitializeWithFiber(app *fiber.App) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	return r
+}
 
+// InitializeHeadless initializes sentry and starts the components of the registry, the
+// serve and work commands share it before registering their server and the consumers.
+func (r *Registry) InitializeHeadless(ctx context.Context) error {
 	sentry.Init(sentry.ClientOptions{
-		Dsn:              r.config.Sentry.Dsn,
-		TracesSampleRate: r.config.Sentry.TracesSampleRate,
-		Environment:      r.config.App.Env,
-	})
-
-	sentryHandler := sentryfiber.New(sentryfiber.Options{
-		Repanic:         true,
-		WaitForDelivery: true,
+		Dsn:              r.Config().Sentry.Dsn,
+		TracesSampleRate: r.Config().Sentry.TracesSampleRate,
+		Environment:      r.Config().App.Env,
 	})
 
-	app.Use(sentryHandler)
-
-	var err error
-	if r.Config().OpenTelemetry.Enabled {
-		r.otelClient, err = otel.NewClient(ctx)
-		if err != nil {
-			return err
-		}
-
-		// skip health check requests
-		app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
-			for _, route := range r.Config().OpenTelemetry.ExcludedRoutes {
-				if c.Path() == route {
-					return true
-				}
-			}
-
-			return false
-		})))
-
-		serveMetrics(app)
-	}
-
 	if err := r.Initialize(ctx); err != nil {
 		sentry.CaptureException(err)
 		return err
 	}
 
-	if err := r.RegisterConsumers(); err != nil {
-		return err
-	}
-
-	// Register pre middlewares
-	if err := r.RegisterPreMiddlewares(app); err != nil {
-		return err
-	}
-
-	r.RegisterApiRoutes(app.Group("/api"))
-
-	// Register post middlewares
-	if err := r.RegisterPostMiddlewares(app); err != nil {
-		return err
-	}
-	// register other "things" (e.g. listeners, consumers, etc.)
-
-	// Register signal after initializing dependencies
-	r.RegisterSignalListener()
+	// Create the services and repositories now instead of on their first use
+	r.WarmUp()
 
 	return nil
 }
 
+// Initialize creates the logger and starts the components of the registry, the servers are
+// registered as components by the commands running them.
 func (r *Registry) Initialize(ctx context.Context) error {
-	var err error
-
-	r.logger, err = logger.NewLogger(
-		logger.WithLogLevel(r.Config().App.LogLevel),
-		logger.WithSpanLevel(r.Config().App.LogLevel),
-	)
-	if err != nil {
-		return err
-	}
-
-	r.db, err = db.Connect(r)
-	if err != nil {
-		return err
-	}
-
-	r.natsService, err = nats.NewService(
-		ctx,
-		nats.WithServers([]string{r.config.Nats.Servers}),
-		nats.WithAuthProvider(nats.NewCredsAuth(r.config.Nats.CredsPath)),
-		nats.WithClientName(r.config.Nats.ClientName),
-		nats.WithLogger(r.Logger()),
-		nats.WithJetstreamEnabled(true),
-		nats.WithStream(nats.Stream{
-			Name:     r.config.Nats.DefaultStreamName,
-			Subjects: r.config.Nats.DefaultStreamSubjects,
-		}),
-		nats.WithMonitoringInterval(r.config.Nats.Monitoring.Interval),
-		nats.WithMonitoringPendingMessagesThreshold(r.config.Nats.Monitoring.PendingMessagesThreshold),
-		nats.WithMonitoringExcludedConsumers(r.config.Nats.Monitoring.ExcludedConsumers),
-		nats.WithMonitoringOnConsumerRestart(func(ctx context.Context, consumerName string) {
-			r.SendSignal(model.SignalPayload{
-				Type: model.SignalTypeNatsConsumerRestart,
-			})
-		}),
-	)
+	l, err := newLogger(r.Config().App.LogLevel)
 	if err != nil {
 		return err
 	}
+	r.setLogger(l)
 
-	if err := r.InitializeClients(); err != nil {
+	if err := r.Lifecycle().Register(r.components()...); err != nil {
 		return err
 	}
 
-	return nil
+	return r.Lifecycle().Start(ctx)
 }
 
+// Cleanup stops the components in the reverse order they were started, e.g. the HTTP server
+// and the consumers before the database.
 func (r *Registry) Cleanup() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	r.Logger().Debugw(ctx, "🧹 Cleaning up registry")
 
-	r.Logger().Infow(ctx, "🔌 Closing database connection")
-	if err := r.db.Close(); err != nil {
-		return err
-	}
-
-	r.Logger().Infow(ctx, "🔌 Closing NATS connection")
-	if err := r.NatsService().Cleanup(ctx); err != nil {
+	if err := r.Lifecycle().Stop(ctx); err != nil {
 		return err
 	}
 
-	if r.otelClient != nil {
-		if err := r.otelClient.Shutdown(ctx); err != nil {
-			r.Logger().Errorw(ctx, "Error shutting down tracer provider", "error", err)
-		}
-	}
-
-	if r.signal != nil {
-		r.Logger().Debugw(ctx, "🔄 Closing signal channel")
-		close(r.signal)
-		r.signal = nil
-		r.Logger().Debugw(ctx, "✅ Signal channel closed")
-	}
-
 	r.Logger().Infow(ctx, "✅ Registry cleaned up successfully")
 	// call cleanup funcs (e.g. unsubscribe listeners, etc.)
 
@@ -226,64 +140,14 @@ func (r *Registry) RegisterConsumers() error {
 	return nil
 }
 
-func (r *Registry) RegisterPreMiddlewares(app *fiber.App) error {
-	// Global middlewares apply to all routes
-
-	app.Use(middleware.NewRequestIDMiddleware().Handle)
-
-	requestIDMiddleware, err := middleware.NewLoggingMiddleware(
-		middleware.WithLogger(r.Logger()),
-	)
-	if err != nil {
-		return err
-	}
-	app.Use(requestIDMiddleware.Handle)
-
-	authMiddleware, err := middleware.NewAuthMiddleware(
-		middleware.WithAuthAPIKey(r.Config().Api.Key),
-		middleware.WithAuthFallbackToXAPIKeyHeader(true),
-		middleware.WithAuthLogger(r.Logger()),
-		middleware.WithAuthPublicRoutes(r.Config().Api.PublicRoutes),
-		middleware.WithAuthOnUnauthorized(func() error {
-			return r.NewError(errors.ErrUnauthorized, "unauthorized missing or invalid API key")
-		}),
-	)
-	if err != nil {
-		return err
-	}
-	app.Use(authMiddleware.Handle)
-
-	// register other middlewares
-
-	return nil
-}
-
-func (r *Registry) RegisterApiRoutes(api fiber.Router) {
-	// health check
-	health.NewHandler(r).RegisterRoutes(api)
-
-	// user routes
-	user.NewHandler(r).RegisterRoutes(api)
-
-	// post routes
-	post.NewHandler(r).RegisterRoutes(api)
-
-	// register other routes
-}
-
-func (r *Registry) RegisterPostMiddlewares(app *fiber.App) error {
-	notFoundMiddleware, err := middleware.NewNotFoundMiddleware(
-		middleware.WithNotFoundLogger(r.Logger()),
-		middleware.WithNotFoundOnNotFound(func() error {
-			return r.NewError(errors.ErrResourceNotFound, "route not found")
-		}),
-	)
-	if err != nil {
-		return err
-	}
-	app.Use(notFoundMiddleware.Handle)
+// RegisterAdminRoutes serves the health checks and the metrics of the work command, it has no
+// API and no auth.
+func (r *Registry) RegisterAdminRoutes(app *fiber.App) {
+	h := health.NewHandler(r)
+	app.Get("/healthz/alive", h.LivenessCheck)
+	app.Get("/healthz/ready", h.ReadinessCheck)
 
-	return nil
+	serveMetrics(app)
 }
 
 func serveMetrics(app *fiber.App) {
@@ -0,0 +1,161 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/bus"
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/domains/health"
+	"github.com/PROJECT_NAME/internal/domains/model"
+	"github.com/PROJECT_NAME/internal/domains/post"
+	"github.com/PROJECT_NAME/internal/domains/user"
+	"github.com/PROJECT_NAME/internal/errors"
+	"github.com/PROJECT_NAME/internal/featureflags"
+	sentryfiber "github.com/getsentry/sentry-go/fiber"
+	"github.com/gofiber/contrib/otelfiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nayla-finance/go-nayla/middleware"
+)
+
+// InitializeWithFiber initializes the registry and registers the middlewares and the routes of
+// the API on app. The consumers are started by the command with StartConsumers, serve
+// --no-consumers runs the API alone.
+func (r *Registry) InitializeWithFiber(app *fiber.App) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sentryHandler := sentryfiber.New(sentryfiber.Options{
+		Repanic:         true,
+		WaitForDelivery: true,
+	})
+
+	app.Use(sentryHandler)
+
+	if err := r.InitializeHeadless(ctx); err != nil {
+		return err
+	}
+
+	if r.Config().OpenTelemetry.Enabled {
+		// skip health check requests
+		app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
+			for _, route := range r.Config().OpenTelemetry.ExcludedRoutes {
+				if c.Path() == route {
+					return true
+				}
+			}
+
+			return false
+		})))
+
+		serveMetrics(app)
+	}
+
+	// Register pre middlewares
+	if err := r.RegisterPreMiddlewares(app); err != nil {
+		return err
+	}
+
+	r.RegisterApiRoutes(app.Group("/api"))
+
+	// Register post middlewares
+	if err := r.RegisterPostMiddlewares(app); err != nil {
+		return err
+	}
+	// register other "things" (e.g. listeners, etc.)
+
+	return nil
+}
+
+func (r *Registry) RegisterPreMiddlewares(app *fiber.App) error {
+	// Global middlewares apply to all routes
+
+	app.Use(middleware.NewRequestIDMiddleware().Handle)
+
+	requestIDMiddleware, err := middleware.NewLoggingMiddleware(
+		middleware.WithLogger(r.runningLogger()),
+	)
+	if err != nil {
+		return err
+	}
+	app.Use(requestIDMiddleware.Handle)
+
+	// the auth middleware is created again when the public routes are reloaded
+	var auth atomic.Pointer[fiber.Handler]
+	newAuth := func(cfg *config.Config) error {
+		authMiddleware, err := middleware.NewAuthMiddleware(
+			middleware.WithAuthAPIKey(cfg.Api.Key),
+			middleware.WithAuthFallbackToXAPIKeyHeader(true),
+			middleware.WithAuthLogger(r.runningLogger()),
+			middleware.WithAuthPublicRoutes(cfg.Api.PublicRoutes),
+			middleware.WithAuthOnUnauthorized(func() error {
+				return r.NewError(errors.ErrUnauthorized, "unauthorized missing or invalid API key")
+			}),
+		)
+		if err != nil {
+			return err
+		}
+
+		handle := fiber.Handler(authMiddleware.Handle)
+		auth.Store(&handle)
+
+		return nil
+	}
+
+	if err := newAuth(r.Config()); err != nil {
+		return err
+	}
+	app.Use(func(c *fiber.Ctx) error {
+		return (*auth.Load())(c)
+	})
+
+	if err := model.SignalConfigChanged.Subscribe(r.Bus(), "registry.auth_middleware", func(ctx context.Context, change config.Change) error {
+		if !slices.Contains(change.Changed, "api.public_routes") {
+			return nil
+		}
+
+		return newAuth(change.Config)
+	}, bus.WithDelivery(bus.Sync)); err != nil {
+		return err
+	}
+
+	// the feature flags are evaluated for the API key and the user of the request
+	app.Use(featureflags.Middleware)
+
+	// register other middlewares
+
+	return nil
+}
+
+func (r *Registry) RegisterApiRoutes(api fiber.Router) {
+	// health check
+	health.NewHandler(r).RegisterRoutes(api)
+
+	// feature flags admin
+	featureflags.NewHandler(r).RegisterRoutes(api)
+
+	// user routes
+	user.NewHandler(r).RegisterRoutes(api)
+
+	// post routes
+	post.NewHandler(r).RegisterRoutes(api)
+
+	// register other routes
+}
+
+func (r *Registry) RegisterPostMiddlewares(app *fiber.App) error {
+	notFoundMiddleware, err := middleware.NewNotFoundMiddleware(
+		middleware.WithNotFoundLogger(r.runningLogger()),
+		middleware.WithNotFoundOnNotFound(func() error {
+			return r.NewError(errors.ErrResourceNotFound, "route not found")
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	app.Use(notFoundMiddleware.Handle)
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewTestApp returns a fiber app with the routes, middlewares and error handler of the test
+// registry, for app.Test:
+//
+//	r := registry.NewTestRegistry(t, registry.WithUserRepository(fakeRepository))
+//	app := registry.NewTestApp(t, r)
+//	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+//	req.Header.Set("Authorization", "Bearer "+registry.TestAPIKey)
+//	res, err := app.Test(req)
+func NewTestApp(t testing.TB, r *Registry) *fiber.App {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{
+		AppName:               r.Config().App.Name,
+		ErrorHandler:          r.ErrorHandler().Handle,
+		DisableStartupMessage: true,
+	})
+
+	if err := r.RegisterPreMiddlewares(app); err != nil {
+		t.Fatalf("❌ Failed to register the pre middlewares: %v", err)
+	}
+
+	r.RegisterApiRoutes(app.Group("/api"))
+
+	if err := r.RegisterPostMiddlewares(app); err != nil {
+		t.Fatalf("❌ Failed to register the post middlewares: %v", err)
+	}
+
+	return app
+}
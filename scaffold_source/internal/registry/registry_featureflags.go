@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/domains/model"
+	"github.com/PROJECT_NAME/internal/featureflags"
+	"github.com/PROJECT_NAME/internal/lifecycle"
+)
+
+func (r *Registry) Flags() featureflags.Flags {
+	return r.flags.Get(func() featureflags.Flags {
+		return featureflags.NewFlags(r)
+	})
+}
+
+// featureFlagsComponent loads the feature flags, and reloads them every
+// feature_flags.refresh_interval to apply the ones toggled through another instance. The
+// changes are published as model.SignalFeatureFlagsChanged.
+func (r *Registry) featureFlagsComponent() lifecycle.Component {
+	var cancel context.CancelFunc
+
+	return lifecycle.Component{
+		Name:      ComponentFeatureFlags,
+		DependsOn: []string{ComponentDB, ComponentSignals},
+		Start: func(ctx context.Context) error {
+			r.Flags().Subscribe(r.applyFeatureFlags)
+
+			if _, err := r.Flags().Reload(ctx); err != nil {
+				return err
+			}
+
+			// ctx is canceled once the component is started
+			var watchCtx context.Context
+			watchCtx, cancel = context.WithCancel(context.Background())
+			if interval := r.Config().FeatureFlags.RefreshInterval; interval > 0 {
+				r.Flags().Watch(watchCtx, interval, func(err error) {
+					r.Logger().Errorw(watchCtx, "❌ Failed to reload the feature flags", "error", err)
+				})
+			}
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	}
+}
+
+func (r *Registry) applyFeatureFlags(change featureflags.Change) {
+	ctx := context.Background()
+
+	r.Logger().Infow(ctx, "🚩 Feature flags changed", "changed", change.Changed)
+	if err := model.SignalFeatureFlagsChanged.Publish(ctx, r.Bus(), change); err != nil {
+		r.Logger().Errorw(ctx, "❌ Failed to publish the feature flags change", "error", err)
+	}
+}
+
+// reloadFeatureFlags applies the flags of the config when it is reloaded.
+func (r *Registry) reloadFeatureFlags(ctx context.Context, change config.Change) error {
+	if r.Lifecycle().State(ComponentFeatureFlags) != lifecycle.StateRunning {
+		return nil
+	}
+
+	if !slices.ContainsFunc(change.Changed, func(key string) bool { return strings.HasPrefix(key, "feature_flags.flags") }) {
+		return nil
+	}
+
+	_, err := r.Flags().Reload(ctx)
+	return err
+}
+
+func WithFlags(f featureflags.Flags) TestOption {
+	return func(o *testOptions) {
+		o.r.flags.Set(f)
+	}
+}
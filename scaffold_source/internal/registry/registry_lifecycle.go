@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/PROJECT_NAME/internal/domains/model"
+	"github.com/PROJECT_NAME/internal/lifecycle"
+	"github.com/nayla-finance/go-nayla/nats"
+	"github.com/nayla-finance/go-nayla/otel"
+)
+
+// Names of the components of the registry, the servers depend on them.
+const (
+	ComponentOtel         = "otel"
+	ComponentDB           = "db"
+	ComponentNats         = "nats"
+	ComponentClients      = "clients"
+	ComponentSignals      = "signals"
+	ComponentConfig       = "config"
+	ComponentConsumers    = "consumers"
+	ComponentFeatureFlags = "feature_flags"
+)
+
+// components are started in the order of their dependencies and stopped in reverse: the
+// NATS subscriptions are drained before the database is closed, and the traces are flushed
+// last.
+func (r *Registry) components() []lifecycle.Component {
+	var components []lifecycle.Component
+	if r.Config().OpenTelemetry.Enabled {
+		components = append(components, lifecycle.Component{
+			Name: ComponentOtel,
+			Start: func(ctx context.Context) error {
+				var err error
+				r.otelClient, err = otel.NewClient(ctx)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return r.otelClient.Shutdown(ctx)
+			},
+		})
+	}
+
+	dependsOnOtel := func(names ...string) []string {
+		if r.Config().OpenTelemetry.Enabled {
+			return append(names, ComponentOtel)
+		}
+		return names
+	}
+
+	components = append(components,
+		lifecycle.Component{
+			Name:      ComponentDB,
+			DependsOn: dependsOnOtel(),
+			Start: func(ctx context.Context) error {
+				var err error
+				r.db, err = db.Connect(r)
+				return err
+			},
+			Stop: func(ctx context.Context) error {
+				return r.db.Close()
+			},
+		},
+		lifecycle.Component{
+			Name: ComponentNats,
+			// the consumers use the database until they are drained
+			DependsOn: dependsOnOtel(ComponentDB),
+			Start:     r.connectNats,
+			Stop: func(ctx context.Context) error {
+				return r.NatsService().Cleanup(ctx)
+			},
+		},
+		lifecycle.Component{
+			Name:      ComponentClients,
+			DependsOn: dependsOnOtel(),
+			Start: func(ctx context.Context) error {
+				return r.InitializeClients()
+			},
+		},
+		lifecycle.Component{
+			Name: ComponentSignals,
+			// the pending signals are delivered before the stores are closed
+			DependsOn: dependsOnOtel(ComponentDB, ComponentNats, ComponentClients),
+			Start: func(ctx context.Context) error {
+				return r.subscribeSignals()
+			},
+			Stop: r.Bus().Close,
+		},
+		r.featureFlagsComponent(),
+	)
+
+	return components
+}
+
+// StartConsumers registers the NATS consumers and starts them, they are drained when the
+// nats component stops.
+func (r *Registry) StartConsumers(ctx context.Context) error {
+	if err := r.Lifecycle().Register(lifecycle.Component{
+		Name:      ComponentConsumers,
+		DependsOn: []string{ComponentDB, ComponentNats, ComponentClients},
+		Start: func(ctx context.Context) error {
+			return r.RegisterConsumers()
+		},
+	}); err != nil {
+		return err
+	}
+
+	return r.Lifecycle().Start(ctx)
+}
+
+func (r *Registry) connectNats(ctx context.Context) error {
+	// config.Load does not read the creds, a missing file fails here with its key
+	if path := r.Config().Nats.CredsPath; path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("❌ Failed to read nats.creds_path: %w", err)
+		}
+	}
+
+	var err error
+	r.natsService, err = nats.NewService(
+		ctx,
+		nats.WithServers([]string{r.Config().Nats.Servers}),
+		nats.WithAuthProvider(nats.NewCredsAuth(r.Config().Nats.CredsPath)),
+		nats.WithClientName(r.Config().Nats.ClientName),
+		nats.WithLogger(r.runningLogger()),
+		nats.WithJetstreamEnabled(true),
+		nats.WithStream(nats.Stream{
+			Name:     r.Config().Nats.DefaultStreamName,
+			Subjects: r.Config().Nats.DefaultStreamSubjects,
+		}),
+		nats.WithMonitoringInterval(r.Config().Nats.Monitoring.Interval),
+		nats.WithMonitoringPendingMessagesThreshold(r.Config().Nats.Monitoring.PendingMessagesThreshold),
+		nats.WithMonitoringExcludedConsumers(r.Config().Nats.Monitoring.ExcludedConsumers),
+		nats.WithMonitoringOnConsumerRestart(func(ctx context.Context, consumerName string) {
+			if err := model.SignalNatsConsumerRestart.Publish(ctx, r.Bus(), model.NatsConsumerRestart{Consumer: consumerName}); err != nil {
+				r.Logger().Warnw(ctx, "⚠️ Failed to publish the consumer restart", "consumer", consumerName, "error", err)
+			}
+		}),
+	)
+
+	return err
+}
+
+func (r *Registry) Lifecycle() lifecycle.Manager {
+	return r.lifecycle
+}
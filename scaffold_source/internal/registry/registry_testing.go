@@ -0,0 +1,244 @@
+package registry
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PROJECT_NAME/internal/config"
+	"github.com/PROJECT_NAME/internal/configbase"
+	"github.com/PROJECT_NAME/internal/db"
+	"github.com/PROJECT_NAME/internal/domains/health"
+	"github.com/PROJECT_NAME/internal/errors"
+	"github.com/PROJECT_NAME/internal/lifecycle"
+	"github.com/nayla-finance/go-nayla/clients/rest/kyc"
+	"github.com/nayla-finance/go-nayla/clients/rest/los"
+	nconfig "github.com/nayla-finance/go-nayla/config"
+	"github.com/nayla-finance/go-nayla/logger"
+	"github.com/nayla-finance/go-nayla/nats"
+)
+
+// TestAPIKey is the API key of the test registry, send it in the Authorization header of the
+// requests to the routes that are not public.
+const TestAPIKey = "test-api-key"
+
+type (
+	// TestOption overrides a provider of the test registry.
+	TestOption func(o *testOptions)
+
+	testOptions struct {
+		r *Registry
+		// components are the names of the components to start, e.g. ComponentDB
+		components []string
+		// overridden are the providers set by the options, the components keep them
+		overridden struct {
+			db, nats, kyc, los bool
+		}
+	}
+)
+
+// NewTestRegistry returns a registry without any connection, the providers that are not
+// overridden are created on first use:
+//
+//	r := registry.NewTestRegistry(t, registry.WithNatsService(fakeNats))
+func NewTestRegistry(t testing.TB, opts ...TestOption) *Registry {
+	t.Helper()
+
+	r := NewRegistry(&config.Config{
+		Base: configbase.Base{
+			App: nconfig.App{
+				Name:     "PROJECT_NAME",
+				Env:      "test",
+				LogLevel: "error",
+			},
+			Api: nconfig.API{
+				Key: TestAPIKey,
+			},
+			// no dependency is checked by the health checks
+			Health: nconfig.Health{
+				Dependencies: nconfig.Dependencies{},
+			},
+		},
+	})
+
+	o := &testOptions{r: r}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if r.Logger() == nil {
+		l, err := newLogger(r.Config().App.LogLevel)
+		if err != nil {
+			t.Fatalf("❌ Failed to create the logger: %v", err)
+		}
+		r.setLogger(l)
+	}
+
+	if len(o.components) > 0 {
+		startTestComponents(t, o)
+	}
+
+	return r
+}
+
+// startTestComponents starts the components named by o.components and their dependencies,
+// they are stopped when the test ends. The providers overridden by the options are not
+// connected nor replaced.
+func startTestComponents(t testing.TB, o *testOptions) {
+	t.Helper()
+
+	r, names := o.r, o.components
+
+	all := r.components()
+	byName := map[string]lifecycle.Component{}
+	for _, c := range all {
+		byName[c.Name] = c
+	}
+
+	wanted := map[string]bool{}
+	var want func(name string)
+	want = func(name string) {
+		c, ok := byName[name]
+		if !ok {
+			t.Fatalf("❌ Unknown component %s", name)
+		}
+
+		wanted[name] = true
+		for _, dep := range c.DependsOn {
+			want(dep)
+		}
+	}
+	for _, name := range names {
+		want(name)
+	}
+
+	var components []lifecycle.Component
+	for _, c := range all {
+		if wanted[c.Name] {
+			components = append(components, keepOverrides(o, c))
+		}
+	}
+
+	if err := r.Lifecycle().Register(components...); err != nil {
+		t.Fatalf("❌ Failed to register the components: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := r.Lifecycle().Start(ctx); err != nil {
+		t.Fatalf("❌ Failed to start the components: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := r.Lifecycle().Stop(ctx); err != nil {
+			t.Errorf("❌ Failed to stop the components: %v", err)
+		}
+	})
+}
+
+// keepOverrides returns c without the Start and Stop of the providers overridden by the
+// options, the clients are created and the overridden ones are set back.
+func keepOverrides(o *testOptions, c lifecycle.Component) lifecycle.Component {
+	r := o.r
+
+	switch c.Name {
+	case ComponentDB:
+		if o.overridden.db {
+			c.Start, c.Stop = nil, nil
+		}
+	case ComponentNats:
+		if o.overridden.nats {
+			c.Start, c.Stop = nil, nil
+		}
+	case ComponentClients:
+		kycClient, losClient := r.kycClient, r.losClient
+		start := c.Start
+		c.Start = func(ctx context.Context) error {
+			if err := start(ctx); err != nil {
+				return err
+			}
+
+			if o.overridden.kyc {
+				r.kycClient = kycClient
+			}
+			if o.overridden.los {
+				r.losClient = losClient
+			}
+
+			return nil
+		}
+	}
+
+	return c
+}
+
+// WithConfig changes the config of the test registry, e.g. to set the database of
+// WithComponents.
+func WithConfig(fn func(c *config.Config)) TestOption {
+	return func(o *testOptions) {
+		fn(o.r.Config())
+	}
+}
+
+// WithComponents connects the components of the registry, e.g. ComponentDB and ComponentNats
+// for the integration tests, and their dependencies. Their config must be set by WithConfig.
+func WithComponents(names ...string) TestOption {
+	return func(o *testOptions) {
+		for _, name := range names {
+			if !slices.Contains(o.components, name) {
+				o.components = append(o.components, name)
+			}
+		}
+	}
+}
+
+func WithLogger(l logger.Logger) TestOption {
+	return func(o *testOptions) {
+		o.r.setLogger(l)
+	}
+}
+
+func WithDB(d db.Database) TestOption {
+	return func(o *testOptions) {
+		o.r.db = d
+		o.overridden.db = true
+	}
+}
+
+func WithNatsService(s nats.Service) TestOption {
+	return func(o *testOptions) {
+		o.r.natsService = s
+		o.overridden.nats = true
+	}
+}
+
+func WithKYCClient(c kyc.Client) TestOption {
+	return func(o *testOptions) {
+		o.r.kycClient = c
+		o.overridden.kyc = true
+	}
+}
+
+func WithLOSClient(c los.Client) TestOption {
+	return func(o *testOptions) {
+		o.r.losClient = c
+		o.overridden.los = true
+	}
+}
+
+func WithErrorHandler(h *errors.Handler) TestOption {
+	return func(o *testOptions) {
+		o.r.errorHandler.Set(h)
+	}
+}
+
+func WithHealthService(s health.Service) TestOption {
+	return func(o *testOptions) {
+		o.r.healthService.Set(s)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"os"
 	"time"
 
+	config_cmd "github.com/PROJECT_NAME/cmd/config"
 	"github.com/PROJECT_NAME/cmd/migrate"
 	"github.com/PROJECT_NAME/cmd/serve"
+	"github.com/PROJECT_NAME/cmd/work"
 	"github.com/getsentry/sentry-go"
 	"github.com/spf13/cobra"
 )
@@ -15,13 +17,13 @@ func main() {
 	dns := os.Getenv("SENTRY__DSN")
 
 	if dns == "" {
-		fmt.Println("⚠️ SENTRY__DSN is not set in env")
+		fmt.Fprintln(os.Stderr, "⚠️ SENTRY__DSN is not set in env")
 	}
 	err := sentry.Init(sentry.ClientOptions{
 		Dsn: dns,
 	})
 	if err != nil {
-		fmt.Println("⚠️ Failed to initialize sentry: ", err)
+		fmt.Fprintln(os.Stderr, "⚠️ Failed to initialize sentry: ", err)
 	}
 	defer sentry.Flush(2 * time.Second)
 
@@ -33,7 +35,7 @@ func main() {
 		},
 	}
 
-	cmd.AddCommand(serve.NewServeCmd(), migrate.NewMigrateCmd())
+	cmd.AddCommand(serve.NewServeCmd(), work.NewWorkCmd(), migrate.NewMigrateCmd(), config_cmd.NewConfigCmd())
 	if err := cmd.Execute(); err != nil {
 		sentry.CaptureException(err)
 		panic(err)